@@ -0,0 +1,81 @@
+// Package auth hashes user passwords and generates the opaque tokens used for
+// browser login sessions and personal API tokens.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password length limits. bcrypt ignores everything after 72 bytes.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// tokenPrefix marks tokens issued by this server so they are easy to spot in logs and scripts.
+const tokenPrefix = "lp_"
+
+var (
+	// ErrPasswordTooShort is returned for passwords under MinPasswordLength bytes.
+	ErrPasswordTooShort = errors.New("password is too short")
+	// ErrPasswordTooLong is returned for passwords over MaxPasswordLength bytes.
+	ErrPasswordTooLong = errors.New("password is too long")
+)
+
+// ValidatePassword checks that a password can be hashed safely.
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	return nil
+}
+
+// HashPassword returns the bcrypt hash of a valid password.
+func HashPassword(password string) (string, error) {
+	if err := ValidatePassword(password); err != nil {
+		return "", err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches a hash from HashPassword.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// NewToken returns a random token and the hash to store for it. Only the hash is
+// persisted, so a leaked database does not leak usable tokens.
+func NewToken() (token, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token = tokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex SHA-256 hash under which a token is stored.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashAndCheckPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, "correct horse", hash)
+
+	assert.True(t, CheckPassword(hash, "correct horse"))
+	assert.False(t, CheckPassword(hash, "wrong horse"))
+	assert.False(t, CheckPassword("", "correct horse"))
+}
+
+func TestHashPasswordRejectsInvalidLengths(t *testing.T) {
+	_, err := HashPassword("short")
+	assert.ErrorIs(t, err, ErrPasswordTooShort)
+
+	_, err = HashPassword(strings.Repeat("a", MaxPasswordLength+1))
+	assert.ErrorIs(t, err, ErrPasswordTooLong)
+}
+
+func TestNewToken(t *testing.T) {
+	token, hash, err := NewToken()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, tokenPrefix))
+	assert.Equal(t, HashToken(token), hash)
+
+	other, _, err := NewToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend_go/auth"
+	"backend_go/db"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionCookieName is the cookie carrying a browser's login session token.
+const sessionCookieName = "lang_portal_session"
+
+// userIDHeader picks the user a request acts as in open mode. Requests without it
+// act as the default user, so single-user clients keep working unchanged.
+const userIDHeader = "X-User-ID"
+
+// currentUserKey is the gin context key holding the request's *models.User.
+const currentUserKey = "currentUser"
+
+// passwordErrorMessage is returned for passwords auth.ValidatePassword rejects.
+var passwordErrorMessage = fmt.Sprintf("password must be between %d and %d characters", auth.MinPasswordLength, auth.MaxPasswordLength)
+
+// publicRoutes can be called without credentials. External learning apps
+// authenticate their reviews with a study session token instead.
+var publicRoutes = map[string]bool{
+	"/api/ping":                             true,
+	"/healthz":                              true,
+	"/metrics":                              true,
+	"/readyz":                               true,
+	"/api/auth/login":                       true,
+	"/api/auth/logout":                      true,
+	"/api/external/sessions/:token/reviews": true,
+}
+
+// authMiddleware identifies the request's user from an API token in the
+// Authorization header or a login session cookie, and rejects anonymous requests
+// to non-public routes. In open mode the X-User-ID header is trusted instead.
+func authMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if appConfig.OpenMode {
+			openModeUser(c)
+			return
+		}
+
+		user, err := authenticate(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate request"})
+			log.Println("Failed to authenticate request:", err)
+			return
+		}
+		if user == nil {
+			if publicRoutes[c.FullPath()] {
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		c.Set(currentUserKey, user)
+		c.Next()
+	}
+}
+
+// openModeUser loads the user named by the X-User-ID header, or the default user.
+func openModeUser(c *gin.Context) {
+	userID := models.DefaultUserID
+	if header := c.GetHeader(userIDHeader); header != "" {
+		id, err := strconv.Atoi(header)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		userID = id
+	}
+
+	user, err := db.GetUserByID(dbConn, userID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		log.Println("Failed to fetch user:", err)
+		return
+	}
+	if user == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unknown user"})
+		return
+	}
+
+	c.Set(currentUserKey, user)
+	c.Next()
+}
+
+// authenticate returns the user owning the request's bearer token or session
+// cookie, or nil when neither is present and valid.
+func authenticate(c *gin.Context) (*models.User, error) {
+	token, kind := bearerToken(c), models.TokenKindAPI
+	if token == "" {
+		cookie, err := c.Cookie(sessionCookieName)
+		if err != nil || cookie == "" {
+			return nil, nil
+		}
+		token, kind = cookie, models.TokenKindSession
+	}
+
+	authToken, user, err := db.GetAuthTokenUser(dbConn, auth.HashToken(token), kind)
+	if err != nil || authToken == nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if authToken.ExpiresAt != nil && !now.Before(*authToken.ExpiresAt) {
+		return nil, nil
+	}
+
+	if kind == models.TokenKindAPI {
+		if err := db.TouchAuthToken(dbConn, authToken.ID, now); err != nil {
+			log.Println("Failed to record API token use:", err)
+		}
+	}
+
+	return user, nil
+}
+
+// bearerToken returns the token from an "Authorization: Bearer" header, if any.
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// requireRole rejects requests whose user has none of the given roles. Admins
+// pass every role check, and no checks are made in open mode.
+func requireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if appConfig.OpenMode {
+			c.Next()
+			return
+		}
+
+		user := currentUser(c)
+		if user.Role == models.RoleAdmin {
+			c.Next()
+			return
+		}
+		for _, role := range roles {
+			if user.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	}
+}
+
+// loginRequest is the payload for POST /api/auth/login.
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// loginHandler handles the POST /api/auth/login endpoint.
+// A successful login starts a session kept in an HTTP-only cookie.
+func loginHandler(c *gin.Context) {
+	var request loginRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user, err := db.GetUserByUsername(dbConn, strings.TrimSpace(request.Username))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		log.Println("Failed to fetch user:", err)
+		return
+	}
+	if user == nil || !auth.CheckPassword(user.PasswordHash, request.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+		return
+	}
+
+	token, hash, err := auth.NewToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session"})
+		log.Println("Failed to generate session token:", err)
+		return
+	}
+
+	expiresAt := time.Now().UTC().Add(appConfig.LoginTTL)
+	session := models.AuthToken{UserID: user.ID, Kind: models.TokenKindSession, ExpiresAt: &expiresAt}
+	if _, err := db.CreateAuthToken(dbConn, &session, hash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session"})
+		log.Println("Failed to create login session:", err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookieName, token, int(appConfig.LoginTTL.Seconds()), "/", "", appConfig.CookieSecure, true)
+
+	c.JSON(http.StatusOK, gin.H{"item": user, "expires_at": expiresAt})
+}
+
+// logoutHandler handles the POST /api/auth/logout endpoint.
+func logoutHandler(c *gin.Context) {
+	if cookie, err := c.Cookie(sessionCookieName); err == nil && cookie != "" {
+		if err := db.DeleteAuthTokenByHash(dbConn, auth.HashToken(cookie)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end session"})
+			log.Println("Failed to delete login session:", err)
+			return
+		}
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookieName, "", -1, "/", "", appConfig.CookieSecure, true)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// changePasswordRequest is the payload for the password endpoints.
+// CurrentPassword is ignored when an admin resets another user's password.
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// updateOwnPasswordHandler handles the PUT /api/users/me/password endpoint.
+func updateOwnPasswordHandler(c *gin.Context) {
+	var request changePasswordRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user := currentUser(c)
+	hash, err := db.GetUserPasswordHash(dbConn, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		log.Println("Failed to fetch password hash:", err)
+		return
+	}
+	if hash != "" && !auth.CheckPassword(hash, request.CurrentPassword) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+
+	setUserPassword(c, user.ID, request.NewPassword)
+}
+
+// updateUserPasswordHandler handles the PUT /api/users/:id/password endpoint,
+// letting admins reset any user's password.
+func updateUserPasswordHandler(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var request changePasswordRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user, err := db.GetUserByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		log.Println("Failed to fetch user:", err)
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	setUserPassword(c, id, request.NewPassword)
+}
+
+// setUserPassword hashes and stores a new password and writes the response.
+func setUserPassword(c *gin.Context, userID int, password string) {
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": passwordErrorMessage})
+		return
+	}
+
+	if err := db.UpdateUserPassword(dbConn, userID, hash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password in database"})
+		log.Println("Failed to update password:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password updated successfully"})
+}
+
+// createAPITokenRequest is the payload for POST /api/auth/tokens.
+type createAPITokenRequest struct {
+	Name      string     `json:"name"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// getAPITokensHandler handles the GET /api/auth/tokens endpoint.
+func getAPITokensHandler(c *gin.Context) {
+	tokens, err := db.GetAuthTokens(dbConn, currentUser(c).ID, models.TokenKindAPI)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API tokens"})
+		log.Println("Failed to fetch API tokens:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": tokens})
+}
+
+// createAPITokenHandler handles the POST /api/auth/tokens endpoint.
+// The token value is only returned in this response.
+func createAPITokenHandler(c *gin.Context) {
+	var request createAPITokenRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	if request.ExpiresAt != nil {
+		if !request.ExpiresAt.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+			return
+		}
+		expiresAt := request.ExpiresAt.UTC()
+		request.ExpiresAt = &expiresAt
+	}
+
+	token, hash, err := auth.NewToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API token"})
+		log.Println("Failed to generate API token:", err)
+		return
+	}
+
+	apiToken := models.AuthToken{
+		UserID:    currentUser(c).ID,
+		Kind:      models.TokenKindAPI,
+		Name:      request.Name,
+		ExpiresAt: request.ExpiresAt,
+	}
+	if _, err := db.CreateAuthToken(dbConn, &apiToken, hash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API token"})
+		log.Println("Failed to create API token:", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"item": apiToken, "token": token})
+}
+
+// deleteAPITokenHandler handles the DELETE /api/auth/tokens/:id endpoint.
+func deleteAPITokenHandler(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API token ID"})
+		return
+	}
+
+	deleted, err := db.DeleteAuthToken(dbConn, id, currentUser(c).ID, models.TokenKindAPI)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete API token"})
+		log.Println("Failed to delete API token:", err)
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API token not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API token deleted successfully"})
+}
+
+// bootstrapAdmin creates an "admin" user with the given password when no admin
+// exists yet, so a fresh installation can be logged into.
+func bootstrapAdmin(password string) error {
+	admins, err := db.CountUsersWithRole(dbConn, models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	if admins > 0 {
+		return nil
+	}
+	if password == "" {
+		if !appConfig.OpenMode {
+			log.Println("No admin user exists; set LANG_PORTAL_ADMIN_PASSWORD to create one")
+		}
+		return nil
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("invalid LANG_PORTAL_ADMIN_PASSWORD: %w", err)
+	}
+
+	admin := models.User{Username: "admin", DisplayName: "Administrator", Role: models.RoleAdmin, PasswordHash: hash}
+	if _, err := db.CreateUser(dbConn, &admin); err != nil {
+		return err
+	}
+
+	log.Println("Created admin user")
+	return nil
+}
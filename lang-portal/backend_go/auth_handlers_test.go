@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"backend_go/auth"
+	dbpkg "backend_go/db"
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthentication(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = false
+	appConfig.CookieSecure = false
+	appConfig.LoginTTL = time.Hour
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	hash, err := auth.HashPassword("learner-password")
+	require.NoError(t, err)
+	require.NoError(t, dbpkg.UpdateUserPassword(db, models.DefaultUserID, hash))
+	require.NoError(t, bootstrapAdmin("admin-password"))
+
+	request := func(method, url string, body string, setup func(*http.Request)) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		if setup != nil {
+			setup(req)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	login := func(username, password string) *http.Cookie {
+		resp := request("POST", "/api/auth/login", `{"username": "`+username+`", "password": "`+password+`"}`, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+		for _, cookie := range resp.Result().Cookies() {
+			if cookie.Name == sessionCookieName {
+				assert.True(t, cookie.HttpOnly)
+				return cookie
+			}
+		}
+		t.Fatal("login did not set a session cookie")
+		return nil
+	}
+	withCookie := func(cookie *http.Cookie) func(*http.Request) {
+		return func(req *http.Request) { req.AddCookie(cookie) }
+	}
+	withToken := func(token string) func(*http.Request) {
+		return func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) }
+	}
+
+	t.Run("Anonymous requests are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request("GET", "/api/words", "", nil).Code)
+		assert.Equal(t, http.StatusOK, request("GET", "/api/ping", "", nil).Code)
+		assert.Equal(t, http.StatusUnauthorized, request("GET", "/api/words", "", withToken("lp_unknown")).Code)
+	})
+
+	t.Run("Login rejects wrong credentials", func(t *testing.T) {
+		resp := request("POST", "/api/auth/login", `{"username": "learner", "password": "wrong-password"}`, nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		resp = request("POST", "/api/auth/login", `{"username": "nobody", "password": "learner-password"}`, nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+
+	t.Run("Session cookie authenticates until logout", func(t *testing.T) {
+		cookie := login("learner", "learner-password")
+
+		resp := request("GET", "/api/users/me", "", withCookie(cookie))
+		require.Equal(t, http.StatusOK, resp.Code)
+		var me struct{ Item models.User }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &me))
+		assert.Equal(t, models.DefaultUserID, me.Item.ID)
+		assert.NotContains(t, resp.Body.String(), "password")
+
+		assert.Equal(t, http.StatusOK, request("POST", "/api/auth/logout", "", withCookie(cookie)).Code)
+		assert.Equal(t, http.StatusUnauthorized, request("GET", "/api/users/me", "", withCookie(cookie)).Code)
+	})
+
+	t.Run("Only admins edit the catalogue", func(t *testing.T) {
+		learner := login("learner", "learner-password")
+		admin := login("admin", "admin-password")
+
+		assert.Equal(t, http.StatusOK, request("GET", "/api/words", "", withCookie(learner)).Code)
+		assert.Equal(t, http.StatusForbidden, request("DELETE", "/api/words/1", "", withCookie(learner)).Code)
+		assert.Equal(t, http.StatusOK, request("DELETE", "/api/words/1", "", withCookie(admin)).Code)
+	})
+
+	t.Run("API tokens can be created, used and revoked", func(t *testing.T) {
+		cookie := login("learner", "learner-password")
+
+		resp := request("POST", "/api/auth/tokens", `{"name": "anki sync"}`, withCookie(cookie))
+		require.Equal(t, http.StatusCreated, resp.Code)
+		var created struct {
+			Item  models.AuthToken
+			Token string
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+		require.NotEmpty(t, created.Token)
+
+		resp = request("GET", "/api/auth/tokens", "", withToken(created.Token))
+		require.Equal(t, http.StatusOK, resp.Code)
+		var list struct{ Items []models.AuthToken }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "anki sync", list.Items[0].Name)
+		assert.NotContains(t, resp.Body.String(), created.Token)
+
+		tokenURL := "/api/auth/tokens/" + strconv.Itoa(created.Item.ID)
+		admin := login("admin", "admin-password")
+		assert.Equal(t, http.StatusNotFound, request("DELETE", tokenURL, "", withCookie(admin)).Code)
+		assert.Equal(t, http.StatusOK, request("DELETE", tokenURL, "", withCookie(cookie)).Code)
+		assert.Equal(t, http.StatusUnauthorized, request("GET", "/api/auth/tokens", "", withToken(created.Token)).Code)
+	})
+
+	t.Run("Passwords can be changed", func(t *testing.T) {
+		cookie := login("learner", "learner-password")
+
+		resp := request("PUT", "/api/users/me/password", `{"current_password": "wrong-password", "new_password": "new-password"}`, withCookie(cookie))
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		resp = request("PUT", "/api/users/me/password", `{"current_password": "learner-password", "new_password": "short"}`, withCookie(cookie))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		resp = request("PUT", "/api/users/me/password", `{"current_password": "learner-password", "new_password": "new-password"}`, withCookie(cookie))
+		require.Equal(t, http.StatusOK, resp.Code)
+		login("learner", "new-password")
+
+		admin := login("admin", "admin-password")
+		userURL := "/api/users/" + strconv.Itoa(models.DefaultUserID) + "/password"
+		assert.Equal(t, http.StatusForbidden, request("PUT", userURL, `{"new_password": "reset-password"}`, withCookie(cookie)).Code)
+		assert.Equal(t, http.StatusOK, request("PUT", userURL, `{"new_password": "reset-password"}`, withCookie(admin)).Code)
+		login("learner", "reset-password")
+	})
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend_go/db"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// classParam loads the class named by the :id parameter. Teachers only see their
+// own classes; admins see every class. It writes an error response and returns
+// nil when the ID is malformed or the class is not visible to the request's user.
+func classParam(c *gin.Context) *models.Class {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return nil
+	}
+
+	class, err := db.GetClassByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch class from database"})
+		log.Println("Failed to fetch class:", err)
+		return nil
+	}
+
+	user := currentUser(c)
+	if class == nil || (class.TeacherID != user.ID && user.Role != models.RoleAdmin) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Class not found"})
+		return nil
+	}
+
+	return class
+}
+
+// assignmentParam loads the assignment named by the :id parameter, applying the
+// same visibility rules as classParam to the assignment's class.
+func assignmentParam(c *gin.Context) *models.Assignment {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return nil
+	}
+
+	assignment, err := db.GetAssignmentByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assignment from database"})
+		log.Println("Failed to fetch assignment:", err)
+		return nil
+	}
+	if assignment == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+		return nil
+	}
+
+	class, err := db.GetClassByID(dbConn, assignment.ClassID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch class from database"})
+		log.Println("Failed to fetch class:", err)
+		return nil
+	}
+
+	user := currentUser(c)
+	if class == nil || (class.TeacherID != user.ID && user.Role != models.RoleAdmin) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+		return nil
+	}
+
+	return assignment
+}
+
+// getClassesHandler handles the GET /api/classes endpoint.
+// Teachers list their own classes; admins list every class.
+func getClassesHandler(c *gin.Context) {
+	teacherID := currentUser(c).ID
+	if currentUser(c).Role == models.RoleAdmin {
+		teacherID = 0
+	}
+
+	classes, err := db.GetClasses(dbConn, teacherID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch classes from database"})
+		log.Println("Failed to fetch classes:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": classes})
+}
+
+// getClassByIDHandler handles the GET /api/classes/:id endpoint.
+// The class is returned with its members.
+func getClassByIDHandler(c *gin.Context) {
+	class := classParam(c)
+	if class == nil {
+		return
+	}
+
+	members, err := db.GetClassMembers(dbConn, class.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch class members from database"})
+		log.Println("Failed to fetch class members:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"item": class, "members": members})
+}
+
+// createClassHandler handles the POST /api/classes endpoint.
+// The class is taught by the request's user.
+func createClassHandler(c *gin.Context) {
+	var class models.Class
+	if err := c.BindJSON(&class); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	class.Name = strings.TrimSpace(class.Name)
+	if class.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	class.TeacherID = currentUser(c).ID
+
+	id, err := db.CreateClass(dbConn, &class)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create class in database"})
+		log.Println("Failed to create class:", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// deleteClassHandler handles the DELETE /api/classes/:id endpoint.
+func deleteClassHandler(c *gin.Context) {
+	class := classParam(c)
+	if class == nil {
+		return
+	}
+
+	if err := db.DeleteClass(dbConn, class.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete class from database"})
+		log.Println("Failed to delete class:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Class deleted successfully"})
+}
+
+// addClassMemberRequest is the payload for POST /api/classes/:id/members.
+type addClassMemberRequest struct {
+	UserID int `json:"user_id"`
+}
+
+// addClassMemberHandler handles the POST /api/classes/:id/members endpoint.
+// Only learners can be enrolled.
+func addClassMemberHandler(c *gin.Context) {
+	class := classParam(c)
+	if class == nil {
+		return
+	}
+
+	var request addClassMemberRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user, err := db.GetUserByID(dbConn, request.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user from database"})
+		log.Println("Failed to fetch user:", err)
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User not found"})
+		return
+	}
+	if user.Role != models.RoleLearner {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only learners can join a class"})
+		return
+	}
+
+	added, err := db.AddClassMember(dbConn, class.ID, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add class member"})
+		log.Println("Failed to add class member:", err)
+		return
+	}
+	if !added {
+		c.JSON(http.StatusConflict, gin.H{"error": "User is already a class member"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Class member added successfully"})
+}
+
+// removeClassMemberHandler handles the DELETE /api/classes/:id/members/:user_id endpoint.
+func removeClassMemberHandler(c *gin.Context) {
+	class := classParam(c)
+	if class == nil {
+		return
+	}
+
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	removed, err := db.RemoveClassMember(dbConn, class.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove class member"})
+		log.Println("Failed to remove class member:", err)
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Class member not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Class member removed successfully"})
+}
+
+// getClassAssignmentsHandler handles the GET /api/classes/:id/assignments endpoint.
+func getClassAssignmentsHandler(c *gin.Context) {
+	class := classParam(c)
+	if class == nil {
+		return
+	}
+
+	assignments, err := db.GetAssignments(dbConn, class.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assignments from database"})
+		log.Println("Failed to fetch assignments:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": assignments})
+}
+
+// createAssignmentRequest is the payload for POST /api/classes/:id/assignments.
+// TargetSessions defaults to 1 and TargetAccuracy, a percentage, to 0.
+type createAssignmentRequest struct {
+	GroupID        int       `json:"group_id"`
+	DueAt          time.Time `json:"due_at"`
+	TargetAccuracy float64   `json:"target_accuracy"`
+	TargetSessions int       `json:"target_sessions"`
+}
+
+// createAssignmentHandler handles the POST /api/classes/:id/assignments endpoint.
+func createAssignmentHandler(c *gin.Context) {
+	class := classParam(c)
+	if class == nil {
+		return
+	}
+
+	var request createAssignmentRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if request.TargetSessions == 0 {
+		request.TargetSessions = 1
+	}
+	switch {
+	case !request.DueAt.After(time.Now()):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "due_at must be in the future"})
+		return
+	case request.TargetSessions < 1:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_sessions must be at least 1"})
+		return
+	case request.TargetAccuracy < 0 || request.TargetAccuracy > 100:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_accuracy must be between 0 and 100"})
+		return
+	}
+
+	group, err := db.GetGroupByID(dbConn, request.GroupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group from database"})
+		log.Println("Failed to fetch group:", err)
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Group not found"})
+		return
+	}
+
+	assignment := models.Assignment{
+		ClassID:        class.ID,
+		GroupID:        group.ID,
+		DueAt:          request.DueAt,
+		TargetAccuracy: request.TargetAccuracy,
+		TargetSessions: request.TargetSessions,
+	}
+	id, err := db.CreateAssignment(dbConn, &assignment)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create assignment in database"})
+		log.Println("Failed to create assignment:", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// deleteAssignmentHandler handles the DELETE /api/assignments/:id endpoint.
+func deleteAssignmentHandler(c *gin.Context) {
+	assignment := assignmentParam(c)
+	if assignment == nil {
+		return
+	}
+
+	if err := db.DeleteAssignment(dbConn, assignment.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete assignment from database"})
+		log.Println("Failed to delete assignment:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Assignment deleted successfully"})
+}
+
+// getAssignmentProgressHandler handles the GET /api/assignments/:id/progress endpoint.
+// It lists every class member's progress towards the assignment's target.
+func getAssignmentProgressHandler(c *gin.Context) {
+	assignment := assignmentParam(c)
+	if assignment == nil {
+		return
+	}
+
+	learners, err := db.GetAssignmentProgress(dbConn, assignment)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assignment progress"})
+		log.Println("Failed to fetch assignment progress:", err)
+		return
+	}
+
+	now := time.Now()
+	completed := 0
+	for i := range learners {
+		learners[i].Status = assignmentStatus(assignment, &learners[i], now)
+		if learners[i].Status == models.AssignmentCompleted {
+			completed++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"item":      assignment,
+		"items":     learners,
+		"completed": completed,
+		"learners":  len(learners),
+	})
+}
+
+// assignmentStatus reports whether a learner has met the assignment's target of
+// TargetSessions sessions each at TargetAccuracy or better, is still working on it
+// or has missed the due date.
+func assignmentStatus(assignment *models.Assignment, progress *models.AssignmentProgress, now time.Time) string {
+	switch {
+	case progress.SessionsMet >= assignment.TargetSessions:
+		return models.AssignmentCompleted
+	case now.After(assignment.DueAt):
+		return models.AssignmentOverdue
+	case progress.StudySessions == 0:
+		return models.AssignmentNotStarted
+	default:
+		return models.AssignmentInProgress
+	}
+}
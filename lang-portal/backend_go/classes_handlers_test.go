@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	dbpkg "backend_go/db"
+	"backend_go/models"
+	"backend_go/sessiontoken"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassAssignments(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = false
+	tokenSigner = sessiontoken.NewSigner([]byte("test-secret"), time.Hour)
+
+	_, err = db.Exec(`INSERT INTO words_groups (word_id, group_id) VALUES (1, 1), (2, 1)`)
+	require.NoError(t, err)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	tokens := map[int]string{}
+	createUser := func(username, role string) int {
+		user := models.User{Username: username, DisplayName: username, Role: role}
+		_, err := dbpkg.CreateUser(db, &user)
+		require.NoError(t, err)
+		tokens[user.ID] = issueTestToken(t, user.ID)
+		return user.ID
+	}
+	teacher := createUser("prof", models.RoleTeacher)
+	otherTeacher := createUser("other", models.RoleTeacher)
+	ana := createUser("ana", models.RoleLearner)
+	bruno := createUser("bruno", models.RoleLearner)
+
+	request := func(method, url string, userID int, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+tokens[userID])
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	createdID := func(resp *httptest.ResponseRecorder) int {
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		var created struct{ ID int }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+		return created.ID
+	}
+	study := func(userID int, reviews string) {
+		sessionID := createdID(request("POST", "/api/study_sessions", userID, `{"GroupID": 1, "StudyActivityID": 1}`))
+		resp := request("POST", "/api/study_sessions/"+strconv.Itoa(sessionID)+"/reviews", userID, reviews)
+		require.Equal(t, http.StatusCreated, resp.Code)
+	}
+
+	classID := createdID(request("POST", "/api/classes", teacher, `{"name": "Portuguese A1"}`))
+	classURL := "/api/classes/" + strconv.Itoa(classID)
+
+	assert.Equal(t, http.StatusForbidden, request("POST", "/api/classes", ana, `{"name": "Mine"}`).Code)
+	assert.Equal(t, http.StatusNotFound, request("GET", classURL, otherTeacher, "").Code)
+
+	for _, userID := range []int{ana, bruno} {
+		resp := request("POST", classURL+"/members", teacher, `{"user_id": `+strconv.Itoa(userID)+`}`)
+		require.Equal(t, http.StatusCreated, resp.Code)
+	}
+	resp := request("POST", classURL+"/members", teacher, `{"user_id": `+strconv.Itoa(ana)+`}`)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	resp = request("POST", classURL+"/members", teacher, `{"user_id": `+strconv.Itoa(otherTeacher)+`}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = request("GET", classURL, teacher, "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var class struct {
+		Item    models.Class
+		Members []models.User
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &class))
+	assert.Equal(t, 2, class.Item.MemberCount)
+	assert.Len(t, class.Members, 2)
+
+	// Reviews recorded before the assignment do not count towards it
+	study(ana, `[{"word_id": 1, "correct": true}]`)
+	_, err = db.Exec(`UPDATE study_sessions SET created_at = '2020-01-01 00:00:00'`)
+	require.NoError(t, err)
+
+	dueAt := time.Now().Add(7 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	resp = request("POST", classURL+"/assignments", teacher, `{"group_id": 1, "due_at": "2020-01-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = request("POST", classURL+"/assignments", teacher, `{"group_id": 99, "due_at": "`+dueAt+`"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assignmentID := createdID(request("POST", classURL+"/assignments", teacher,
+		`{"group_id": 1, "due_at": "`+dueAt+`", "target_accuracy": 75, "target_sessions": 2}`))
+	progressURL := "/api/assignments/" + strconv.Itoa(assignmentID) + "/progress"
+
+	study(ana, `[{"word_id": 1, "correct": true}, {"word_id": 2, "correct": true}]`)
+	study(ana, `[{"word_id": 1, "correct": true}, {"word_id": 2, "correct": false}]`)
+	study(bruno, `[{"word_id": 1, "correct": true}]`)
+
+	t.Run("Assignments are listed per class", func(t *testing.T) {
+		resp := request("GET", classURL+"/assignments", teacher, "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var list struct{ Items []models.Assignment }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, 2, list.Items[0].TargetSessions)
+		assert.NotEmpty(t, list.Items[0].GroupName)
+	})
+
+	t.Run("Progress report shows who completed the assignment", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, request("GET", progressURL, otherTeacher, "").Code)
+		assert.Equal(t, http.StatusForbidden, request("GET", progressURL, ana, "").Code)
+
+		type report struct {
+			Items     []models.AssignmentProgress
+			Completed int
+			Learners  int
+		}
+		progress := func() report {
+			resp := request("GET", progressURL, teacher, "")
+			require.Equal(t, http.StatusOK, resp.Code)
+			var body report
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			require.Len(t, body.Items, 2)
+			return body
+		}
+
+		// Ana's 100% and 50% sessions average 75%, but only one session met the target
+		before := progress()
+		assert.Equal(t, 0, before.Completed)
+		assert.Equal(t, 2, before.Learners)
+		assert.Equal(t, ana, before.Items[0].UserID)
+		assert.Equal(t, 2, before.Items[0].StudySessions)
+		assert.Equal(t, 1, before.Items[0].SessionsMet)
+		assert.Equal(t, 4, before.Items[0].ReviewCount)
+		assert.Equal(t, 75.0, before.Items[0].Accuracy)
+		assert.Equal(t, models.AssignmentInProgress, before.Items[0].Status)
+
+		study(ana, `[{"word_id": 1, "correct": true}, {"word_id": 2, "correct": true}, {"word_id": 1, "correct": false}, {"word_id": 2, "correct": true}]`)
+		after := progress()
+		assert.Equal(t, 1, after.Completed)
+		assert.Equal(t, 3, after.Items[0].StudySessions)
+		assert.Equal(t, 2, after.Items[0].SessionsMet)
+		assert.Equal(t, models.AssignmentCompleted, after.Items[0].Status)
+
+		assert.Equal(t, bruno, after.Items[1].UserID)
+		assert.Equal(t, 1, after.Items[1].StudySessions)
+		assert.Equal(t, 1, after.Items[1].SessionsMet)
+		assert.Equal(t, models.AssignmentInProgress, after.Items[1].Status)
+
+		// Sessions on a subgroup count, including one stored in another UTC offset at
+		// the very moment the assignment was created
+		_, err := db.Exec(`
+			INSERT INTO groups (id, name, description, parent_id) VALUES (50, 'Greetings', '', 1);
+			INSERT INTO words_groups (word_id, group_id) VALUES (3, 50)`)
+		require.NoError(t, err)
+		var list struct{ Items []models.Assignment }
+		require.NoError(t, json.Unmarshal(request("GET", classURL+"/assignments", teacher, "").Body.Bytes(), &list))
+		require.Len(t, list.Items, 1)
+		sessionID := createdID(request("POST", "/api/study_sessions", bruno, `{"GroupID": 50, "StudyActivityID": 1}`))
+		resp := request("POST", "/api/study_sessions/"+strconv.Itoa(sessionID)+"/reviews", bruno, `[{"word_id": 3, "correct": true}]`)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		_, err = db.Exec("UPDATE study_sessions SET created_at = ? WHERE id = ?",
+			list.Items[0].CreatedAt.In(time.FixedZone("BRT", -3*60*60)), sessionID)
+		require.NoError(t, err)
+
+		subgroup := progress()
+		assert.Equal(t, 2, subgroup.Items[1].StudySessions)
+		assert.Equal(t, 2, subgroup.Items[1].SessionsMet)
+		assert.Equal(t, models.AssignmentCompleted, subgroup.Items[1].Status)
+	})
+
+	t.Run("Deleting a class removes its assignments", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request("DELETE", classURL+"/members/"+strconv.Itoa(bruno), teacher, "").Code)
+		assert.Equal(t, http.StatusNotFound, request("DELETE", classURL+"/members/"+strconv.Itoa(bruno), teacher, "").Code)
+
+		assert.Equal(t, http.StatusOK, request("DELETE", classURL, teacher, "").Code)
+		assert.Equal(t, http.StatusNotFound, request("GET", classURL, teacher, "").Code)
+		assert.Equal(t, http.StatusNotFound, request("GET", progressURL, teacher, "").Code)
+	})
+}
+
+func TestAssignmentStatus(t *testing.T) {
+	now := time.Now()
+	assignment := &models.Assignment{DueAt: now.Add(time.Hour), TargetSessions: 2, TargetAccuracy: 80}
+
+	assert.Equal(t, models.AssignmentNotStarted, assignmentStatus(assignment, &models.AssignmentProgress{}, now))
+	assert.Equal(t, models.AssignmentInProgress, assignmentStatus(assignment, &models.AssignmentProgress{StudySessions: 2, SessionsMet: 1, Accuracy: 85}, now))
+	assert.Equal(t, models.AssignmentCompleted, assignmentStatus(assignment, &models.AssignmentProgress{StudySessions: 3, SessionsMet: 2, Accuracy: 70}, now))
+	assert.Equal(t, models.AssignmentOverdue, assignmentStatus(assignment, &models.AssignmentProgress{StudySessions: 1, SessionsMet: 1, Accuracy: 100}, now.Add(2*time.Hour)))
+}
@@ -0,0 +1,119 @@
+// Package cloze builds fill-in-the-blank exercises from example sentences.
+// Sentences usually contain an inflected form of the word being practised
+// ("falamos" for "falar", "gatas" for "gato"), so words are matched by stem.
+package cloze
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Blank is what replaces the word in an exercise prompt.
+const Blank = "____"
+
+// minStemLength keeps suffix stripping from reducing short words to nothing.
+const minStemLength = 3
+
+// suffixes are Portuguese inflectional endings, accent-folded and longest first:
+// verb tenses and persons, then plural and gender endings.
+var suffixes = []string{
+	"avamos", "iamos", "aram", "eram", "iram", "ando", "endo", "indo",
+	"amos", "emos", "imos", "aste", "este", "iste", "ava", "oes", "aes",
+	"ais", "eis", "ar", "er", "ir", "ou", "eu", "iu", "ei", "am", "em",
+	"es", "as", "os", "a", "e", "o", "s",
+}
+
+// accentFolder removes Portuguese diacritics.
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u", "ç", "c",
+)
+
+// fold lowercases s and removes its accents.
+func fold(s string) string {
+	return accentFolder.Replace(strings.ToLower(s))
+}
+
+// Stem returns the accent-folded stem of a word by stripping the longest
+// inflectional ending that leaves at least minStemLength letters.
+func Stem(word string) string {
+	folded := fold(strings.TrimSpace(word))
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(folded, suffix) && utf8.RuneCountInString(folded)-len(suffix) >= minStemLength {
+			return strings.TrimSuffix(folded, suffix)
+		}
+	}
+	return folded
+}
+
+// token is a run of letters in a sentence, with its byte offsets.
+type token struct {
+	start, end int
+}
+
+// tokens splits a sentence into runs of letters.
+func tokens(sentence string) []token {
+	var result []token
+	start := -1
+	for i, r := range sentence {
+		letter := unicode.IsLetter(r)
+		if letter && start < 0 {
+			start = i
+		} else if !letter && start >= 0 {
+			result = append(result, token{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		result = append(result, token{start, len(sentence)})
+	}
+	return result
+}
+
+// Make blanks out the word in the sentence. It returns the prompt, the form of the
+// word as it appears in the sentence (the expected answer) and whether the word
+// was found. An exact match is preferred over a match by stem; phrases of several
+// words must appear verbatim apart from case.
+func Make(sentence, word string) (prompt, answer string, ok bool) {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return "", "", false
+	}
+
+	if strings.ContainsFunc(word, unicode.IsSpace) {
+		// Lowercasing keeps byte offsets for Portuguese text; bail out if it did not
+		lower := strings.ToLower(sentence)
+		index := strings.Index(lower, strings.ToLower(word))
+		if index < 0 || len(lower) != len(sentence) {
+			return "", "", false
+		}
+		end := index + len(word)
+		return sentence[:index] + Blank + sentence[end:], sentence[index:end], true
+	}
+
+	words := tokens(sentence)
+	match := func(t token) (string, string, bool) {
+		return sentence[:t.start] + Blank + sentence[t.end:], sentence[t.start:t.end], true
+	}
+	for _, t := range words {
+		if strings.EqualFold(sentence[t.start:t.end], word) {
+			return match(t)
+		}
+	}
+	stem := Stem(word)
+	for _, t := range words {
+		if Stem(sentence[t.start:t.end]) == stem {
+			return match(t)
+		}
+	}
+	return "", "", false
+}
+
+// Check reports whether a learner's answer matches the expected form, ignoring
+// case and surrounding whitespace but not accents.
+func Check(answer, expected string) bool {
+	return strings.EqualFold(strings.TrimSpace(answer), expected)
+}
@@ -0,0 +1,46 @@
+package cloze
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStem(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"falar", "falamos"}, {"falar", "falávamos"}, {"falar", "falou"}, {"falar", "Falando"},
+		{"comer", "comeu"}, {"gato", "gatas"}, {"bonito", "bonitas"}, {"casa", "casas"},
+	} {
+		assert.Equal(t, Stem(pair[0]), Stem(pair[1]), pair[1])
+	}
+	assert.NotEqual(t, Stem("falar"), Stem("fazer"))
+	assert.Equal(t, "sol", Stem("sol"), "short words are left alone")
+}
+
+func TestMake(t *testing.T) {
+	cases := []struct{ sentence, word, prompt, answer string }{
+		{"Nós falamos português.", "falar", "Nós ____ português.", "falamos"},
+		{"O gato dorme. Os gatos comem.", "gatos", "O gato dorme. Os ____ comem.", "gatos"},
+		{"As meninas são bonitas!", "bonito", "As meninas são ____!", "bonitas"},
+		{"Ela disse Bom Dia ao vizinho.", "bom dia", "Ela disse ____ ao vizinho.", "Bom Dia"},
+		{"Eu comi maçã.", "Maçã", "Eu comi ____.", "maçã"},
+		{"Você já disse Bom Dia à avó?", "bom dia", "Você já disse ____ à avó?", "Bom Dia"},
+	}
+	for _, tc := range cases {
+		prompt, answer, ok := Make(tc.sentence, tc.word)
+		if assert.True(t, ok, tc.sentence) {
+			assert.Equal(t, tc.prompt, prompt)
+			assert.Equal(t, tc.answer, answer)
+		}
+	}
+
+	_, _, ok := Make("O cão ladra.", "gato")
+	assert.False(t, ok)
+	_, _, ok = Make("O cão ladra.", "")
+	assert.False(t, ok)
+}
+
+func TestCheck(t *testing.T) {
+	assert.True(t, Check(" Falamos ", "falamos"))
+	assert.False(t, Check("maca", "maçã"))
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend_go/cloze"
+	"backend_go/db"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cloze exercise limits for GET /api/groups/:id/cloze.
+const (
+	defaultClozeExercises = 10
+	maxClozeExercises     = 100
+)
+
+// clozeAnswer is one answer submitted to POST /api/study_sessions/:id/cloze_answers.
+type clozeAnswer struct {
+	SentenceID int    `json:"sentence_id"`
+	Answer     string `json:"answer"`
+	ResponseMS *int   `json:"response_ms"`
+}
+
+// getGroupClozeHandler handles the GET /api/groups/:id/cloze endpoint.
+// It returns a random selection of the group's example sentences with the
+// practised word blanked out. The word filter parameters, such as tag, limit the
+// sentences to those of some of the words.
+func getGroupClozeHandler(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultClozeExercises)))
+	if err != nil || limit < 1 || limit > maxClozeExercises {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxClozeExercises)})
+		return
+	}
+
+	group := groupParam(c)
+	if group == nil {
+		return
+	}
+
+	words, err := db.GetGroupWords(dbConn, group.ID, requestScope(c), wordFilterParam(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group words from database"})
+		log.Println("Failed to fetch group words:", err)
+		return
+	}
+	sentences, err := db.GetGroupSentences(dbConn, group.ID, requestScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sentences"})
+		log.Println("Failed to fetch sentences:", err)
+		return
+	}
+
+	wordsByID := make(map[int]models.Word, len(words))
+	for _, word := range words {
+		wordsByID[word.ID] = word
+	}
+
+	exercises := []models.ClozeExercise{}
+	for _, sentence := range sentences {
+		word, ok := wordsByID[sentence.WordID]
+		if !ok {
+			continue
+		}
+		prompt, _, ok := cloze.Make(sentence.Text, word.TargetText)
+		if !ok {
+			continue
+		}
+		exercises = append(exercises, models.ClozeExercise{
+			SentenceID:  sentence.ID,
+			WordID:      word.ID,
+			Prompt:      prompt,
+			Translation: sentence.Translation,
+			Hint:        word.SourceText,
+		})
+	}
+
+	rand.Shuffle(len(exercises), func(i, j int) { exercises[i], exercises[j] = exercises[j], exercises[i] })
+	if len(exercises) > limit {
+		exercises = exercises[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": exercises})
+}
+
+// createClozeAnswersHandler handles the POST /api/study_sessions/:id/cloze_answers endpoint.
+// Each answer is graded against the form of the word in the sentence and recorded
+// as a review of the word; answers that cannot be graded are reported per item and skipped.
+func createClozeAnswersHandler(c *gin.Context) {
+	var answers []clozeAnswer
+	if err := c.BindJSON(&answers); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(answers) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No answers provided"})
+		return
+	}
+	if len(answers) > maxReviewBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many answers in one batch", "max_batch_size": maxReviewBatchSize})
+		return
+	}
+
+	session, words := sessionWords(c, db.WordFilter{}, nil)
+	if session == nil {
+		return
+	}
+
+	sentences, err := db.GetGroupSentences(dbConn, session.GroupID, db.SessionScope(session))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sentences"})
+		log.Println("Failed to fetch sentences:", err)
+		return
+	}
+	sentencesByID := make(map[int]models.Sentence, len(sentences))
+	for _, sentence := range sentences {
+		sentencesByID[sentence.ID] = sentence
+	}
+
+	now := time.Now().UTC()
+	results := make([]gradedResult, len(answers))
+	items := []models.WordReviewItem{}
+	itemIndexes := []int{}
+	for i, answer := range answers {
+		results[i] = gradedResult{reviewResult: reviewResult{Index: i, Status: "rejected"}}
+
+		sentence, ok := sentencesByID[answer.SentenceID]
+		if !ok {
+			results[i].Error = "sentence is not in the study session's group"
+			continue
+		}
+		word := words[sentence.WordID]
+		results[i].WordID = word.ID
+		if answer.ResponseMS != nil && *answer.ResponseMS < 0 {
+			results[i].Error = "response_ms must not be negative"
+			continue
+		}
+		_, expected, ok := cloze.Make(sentence.Text, word.TargetText)
+		if !ok {
+			results[i].Error = "sentence no longer contains the word"
+			continue
+		}
+
+		results[i].Expected = expected
+		results[i].Correct = cloze.Check(answer.Answer, expected)
+		items = append(items, models.WordReviewItem{
+			WordID:         word.ID,
+			StudySessionID: session.ID,
+			Correct:        results[i].Correct,
+			CreatedAt:      now,
+			ResponseMS:     answer.ResponseMS,
+			GivenAnswer:    answer.Answer,
+			ActivityType:   models.ActivityTypeCloze,
+		})
+		itemIndexes = append(itemIndexes, i)
+	}
+
+	recordGradedAnswers(c, session.ID, results, items, itemIndexes)
+}
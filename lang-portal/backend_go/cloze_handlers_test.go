@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	dbpkg "backend_go/db"
+	"backend_go/llm"
+	"backend_go/llm/llmtest"
+	"backend_go/models"
+	"backend_go/sessiontoken"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClozeExercises(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+	tokenSigner = sessiontoken.NewSigner([]byte("test-secret"), time.Hour)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	falar, err := dbpkg.CreateWord(db, &models.Word{SourceText: "to speak", TargetText: "falar", Parts: "verb"})
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO words_groups (word_id, group_id) VALUES (?, 1), (2, 1)", falar)
+	require.NoError(t, err)
+	falarID := strconv.Itoa(falar)
+
+	t.Run("Sentences are imported when they use the word", func(t *testing.T) {
+		resp := request("POST", "/api/sentences/import", `[
+			{"word_id": `+falarID+`, "text": "Nós falamos português.", "translation": "We speak Portuguese."},
+			{"word_id": `+falarID+`, "text": "Nós falamos português."},
+			{"word_id": `+falarID+`, "text": "O gato dorme."},
+			{"word_id": 2, "text": "Ela disse adeus.", "translation": "She said goodbye."},
+			{"word_id": 999, "text": "Adeus."}
+		]`)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+		var body struct {
+			Items   []importedSentence
+			Created int
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, 2, body.Created)
+		assert.Equal(t, "sentence already exists", body.Items[1].Error)
+		assert.Equal(t, "sentence does not contain the word", body.Items[2].Error)
+		assert.Equal(t, "word not found", body.Items[4].Error)
+
+		resp = request("POST", "/api/words/"+falarID+"/sentences", `{"text": "Ele falou comigo ontem."}`)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/words/"+falarID+"/sentences", `{"text": ""}`).Code)
+
+		resp = request("GET", "/api/words/"+falarID+"/sentences", "")
+		var list struct{ Items []models.Sentence }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		require.Len(t, list.Items, 2)
+		assert.Equal(t, models.SentenceOriginImport, list.Items[0].Origin)
+		assert.Equal(t, models.SentenceOriginManual, list.Items[1].Origin)
+	})
+
+	t.Run("Sentences are generated with the LLM", func(t *testing.T) {
+		llmClient = nil
+		assert.Equal(t, http.StatusServiceUnavailable, request("POST", "/api/words/2/sentences/generate", "").Code)
+
+		server := llmtest.NewServer(llmtest.Reply(`[
+			{"text": "Disse adeus e partiu.", "translation": "He said goodbye and left."},
+			{"text": "Até logo!", "translation": "See you later!"}
+		]`))
+		defer server.Close()
+		llmClient = llm.NewClient(server.URL, "", "", time.Second)
+		defer func() { llmClient = nil }()
+
+		resp := request("POST", "/api/words/2/sentences/generate", `{"count": 2}`)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		var body struct {
+			Items     []models.Sentence
+			Discarded int
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Len(t, body.Items, 1)
+		assert.Equal(t, models.SentenceOriginLLM, body.Items[0].Origin)
+		assert.Equal(t, 1, body.Discarded)
+	})
+
+	t.Run("Exercises blank out the inflected word", func(t *testing.T) {
+		resp := request("GET", "/api/groups/1/cloze?limit=100", "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var body struct{ Items []models.ClozeExercise }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Len(t, body.Items, 4)
+
+		prompts := map[string]models.ClozeExercise{}
+		for _, exercise := range body.Items {
+			prompts[exercise.Prompt] = exercise
+		}
+		require.Contains(t, prompts, "Nós ____ português.")
+		assert.Equal(t, "to speak", prompts["Nós ____ português."].Hint)
+		assert.Equal(t, "We speak Portuguese.", prompts["Nós ____ português."].Translation)
+		assert.Contains(t, prompts, "Ele ____ comigo ontem.")
+
+		assert.Equal(t, http.StatusBadRequest, request("GET", "/api/groups/1/cloze?limit=0", "").Code)
+		assert.Equal(t, http.StatusNotFound, request("GET", "/api/groups/999/cloze", "").Code)
+	})
+
+	t.Run("Answers are graded and recorded as reviews", func(t *testing.T) {
+		resp := request("POST", "/api/study_sessions", `{"GroupID": 1, "StudyActivityID": 1}`)
+		require.Equal(t, http.StatusCreated, resp.Code)
+		var session struct{ ID int }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &session))
+
+		sentences, err := dbpkg.GetWordSentences(db, falar)
+		require.NoError(t, err)
+		resp = request("POST", "/api/study_sessions/"+strconv.Itoa(session.ID)+"/cloze_answers", `[
+			{"sentence_id": `+strconv.Itoa(sentences[0].ID)+`, "answer": " Falamos ", "response_ms": 1500},
+			{"sentence_id": `+strconv.Itoa(sentences[1].ID)+`, "answer": "falei"},
+			{"sentence_id": 999, "answer": "x"}
+		]`)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+		var body struct {
+			Items   []gradedResult
+			Created int
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, 2, body.Created)
+		assert.True(t, body.Items[0].Correct)
+		assert.Equal(t, falar, body.Items[0].WordID)
+		assert.False(t, body.Items[1].Correct)
+		assert.Equal(t, "falou", body.Items[1].Expected)
+		assert.Equal(t, "rejected", body.Items[2].Status)
+
+		var activityType string
+		require.NoError(t, db.QueryRow("SELECT activity_type FROM word_review_items WHERE id = ?", body.Items[0].ID).Scan(&activityType))
+		assert.Equal(t, models.ActivityTypeCloze, activityType)
+	})
+
+	t.Run("Deleting a word removes its sentences", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, request("DELETE", "/api/words/"+falarID, "").Code)
+		sentences, err := dbpkg.GetWordSentences(db, falar)
+		require.NoError(t, err)
+		assert.Empty(t, sentences)
+	})
+}
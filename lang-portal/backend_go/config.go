@@ -0,0 +1,175 @@
+package main
+
+import (
+	"crypto/rand"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config holds runtime settings read from the environment.
+type Config struct {
+	// TokenSecret signs session tokens handed to external learning apps.
+	TokenSecret []byte
+	// TokenTTL is how long a session token stays valid after launch.
+	TokenTTL time.Duration
+	// ListenAddr is the address the HTTP server listens on.
+	ListenAddr string
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout bound how long the
+	// HTTP server waits on a client. WriteTimeout must leave room for LLM requests.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout is how long in-flight requests may run after a shutdown signal.
+	ShutdownTimeout time.Duration
+	// PublicURL is the externally visible base URL, used to build xAPI activity IRIs.
+	PublicURL string
+	// GoalCheckInterval is how often the reminder looks for missed daily goals.
+	GoalCheckInterval time.Duration
+	// SessionIdleTimeout is how long an active study session may go without reviews
+	// before it is closed as abandoned. Zero disables closing idle sessions.
+	SessionIdleTimeout time.Duration
+	// SessionSweepInterval is how often idle study sessions are looked for.
+	SessionSweepInterval time.Duration
+	// OpenMode disables authentication: requests act as the user in the X-User-ID
+	// header, or the default user, and role checks are skipped.
+	OpenMode bool
+	// LoginTTL is how long a browser login session lasts.
+	LoginTTL time.Duration
+	// CookieSecure marks the login cookie Secure so it is only sent over HTTPS.
+	CookieSecure bool
+	// AdminPassword, when set, creates an "admin" user at startup if no admin exists.
+	AdminPassword string
+	// MediaDir is where uploaded word audio and images are stored.
+	MediaDir string
+	// MaxUploadBytes is the largest media file that may be uploaded.
+	MaxUploadBytes int64
+	// TTSEndpoint is the text-to-speech service used to generate pronunciation audio.
+	// Empty disables generation; "stub" uses an offline provider that emits tones.
+	TTSEndpoint string
+	// TTSVoice is passed to the text-to-speech service with every request.
+	TTSVoice string
+	// TTSTimeout bounds a single text-to-speech request.
+	TTSTimeout time.Duration
+	// LLMEndpoint is the base URL of an OpenAI-compatible API, such as
+	// "http://localhost:8008/v1", used to propose vocabulary. Empty disables it.
+	LLMEndpoint string
+	// LLMModel names the model sent with every chat request.
+	LLMModel string
+	// LLMAPIKey is sent as a bearer token when set.
+	LLMAPIKey string
+	// LLMTimeout bounds a single chat request.
+	LLMTimeout time.Duration
+}
+
+// appConfig is the configuration loaded at startup
+var appConfig Config
+
+// loadConfig reads the configuration from environment variables, falling back to defaults.
+func loadConfig() Config {
+	cfg := Config{
+		TokenSecret:          []byte(os.Getenv("LANG_PORTAL_TOKEN_SECRET")),
+		TokenTTL:             getEnvDuration("LANG_PORTAL_TOKEN_TTL", 2*time.Hour),
+		ListenAddr:           getEnv("LANG_PORTAL_ADDR", ":5000"),
+		ReadHeaderTimeout:    getEnvDuration("LANG_PORTAL_READ_HEADER_TIMEOUT", 10*time.Second),
+		ReadTimeout:          getEnvDuration("LANG_PORTAL_READ_TIMEOUT", time.Minute),
+		WriteTimeout:         getEnvDuration("LANG_PORTAL_WRITE_TIMEOUT", 3*time.Minute),
+		IdleTimeout:          getEnvDuration("LANG_PORTAL_IDLE_TIMEOUT", 2*time.Minute),
+		ShutdownTimeout:      getEnvDuration("LANG_PORTAL_SHUTDOWN_TIMEOUT", 30*time.Second),
+		PublicURL:            getEnv("LANG_PORTAL_PUBLIC_URL", "http://localhost:5000"),
+		GoalCheckInterval:    getEnvDuration("LANG_PORTAL_GOAL_CHECK_INTERVAL", time.Hour),
+		SessionIdleTimeout:   getEnvOptionalDuration("LANG_PORTAL_SESSION_IDLE_TIMEOUT", 30*time.Minute),
+		SessionSweepInterval: getEnvDuration("LANG_PORTAL_SESSION_SWEEP_INTERVAL", time.Minute),
+		OpenMode:             getEnvBool("LANG_PORTAL_OPEN_MODE", false),
+		LoginTTL:             getEnvDuration("LANG_PORTAL_LOGIN_TTL", 7*24*time.Hour),
+		CookieSecure:         getEnvBool("LANG_PORTAL_COOKIE_SECURE", true),
+		AdminPassword:        os.Getenv("LANG_PORTAL_ADMIN_PASSWORD"),
+		MediaDir:             getEnv("LANG_PORTAL_MEDIA_DIR", "uploads"),
+		MaxUploadBytes:       getEnvInt64("LANG_PORTAL_MAX_UPLOAD_BYTES", 10<<20),
+		TTSEndpoint:          os.Getenv("LANG_PORTAL_TTS_ENDPOINT"),
+		TTSVoice:             os.Getenv("LANG_PORTAL_TTS_VOICE"),
+		TTSTimeout:           getEnvDuration("LANG_PORTAL_TTS_TIMEOUT", 30*time.Second),
+		LLMEndpoint:          os.Getenv("LANG_PORTAL_LLM_ENDPOINT"),
+		LLMModel:             os.Getenv("LANG_PORTAL_LLM_MODEL"),
+		LLMAPIKey:            os.Getenv("LANG_PORTAL_LLM_API_KEY"),
+		LLMTimeout:           getEnvDuration("LANG_PORTAL_LLM_TIMEOUT", 2*time.Minute),
+	}
+
+	if cfg.OpenMode {
+		log.Println("LANG_PORTAL_OPEN_MODE is enabled, requests are not authenticated")
+	}
+
+	if len(cfg.TokenSecret) == 0 {
+		// Without a configured secret, tokens only survive until the server restarts.
+		log.Println("LANG_PORTAL_TOKEN_SECRET not set, generating a random token secret")
+		cfg.TokenSecret = make([]byte, 32)
+		if _, err := rand.Read(cfg.TokenSecret); err != nil {
+			log.Fatalf("Failed to generate token secret: %v", err)
+		}
+	}
+
+	return cfg
+}
+
+// getEnv returns the environment variable or fallback when it is unset.
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvDuration parses a positive duration such as "90m" from the environment.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid duration for %s (%q), using %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
+
+// getEnvOptionalDuration is getEnvDuration for settings that zero turns off.
+func getEnvOptionalDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d == 0 {
+		return 0
+	}
+	return getEnvDuration(key, fallback)
+}
+
+// getEnvBool parses a boolean such as "true" or "0" from the environment.
+func getEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid boolean for %s (%q), using %t", key, value, fallback)
+		return fallback
+	}
+	return b
+}
+
+// getEnvInt64 parses an integer such as "1048576" from the environment.
+func getEnvInt64(key string, fallback int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		log.Printf("Invalid integer for %s (%q), using %d", key, value, fallback)
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,274 @@
+// Package conjugation conjugates Portuguese verbs in the present, preterite and
+// imperfect indicative. Regular -ar, -er and -ir verbs follow the usual endings,
+// with the spelling changes their stems need; irregular verbs come from an
+// override table. Plural forms follow Brazilian usage ("falamos" rather than
+// "falámos" in the preterite).
+package conjugation
+
+import (
+	"fmt"
+	"strings"
+
+	"backend_go/grammar"
+	"backend_go/models"
+)
+
+// Tenses of the indicative mood the package conjugates.
+const (
+	Present   = "present"
+	Preterite = "preterite"
+	Imperfect = "imperfect"
+)
+
+// Tenses lists every supported tense in drill order.
+var Tenses = []string{Present, Preterite, Imperfect}
+
+// regularEndings are the endings appended to a regular verb's stem by infinitive
+// ending and tense, in grammar.Persons order.
+var regularEndings = map[string]map[string][]string{
+	"ar": {
+		Present:   {"o", "as", "a", "amos", "ais", "am"},
+		Preterite: {"ei", "aste", "ou", "amos", "astes", "aram"},
+		Imperfect: {"ava", "avas", "ava", "ávamos", "áveis", "avam"},
+	},
+	"er": {
+		Present:   {"o", "es", "e", "emos", "eis", "em"},
+		Preterite: {"i", "este", "eu", "emos", "estes", "eram"},
+		Imperfect: {"ia", "ias", "ia", "íamos", "íeis", "iam"},
+	},
+	"ir": {
+		Present:   {"o", "es", "e", "imos", "is", "em"},
+		Preterite: {"i", "iste", "iu", "imos", "istes", "iram"},
+		Imperfect: {"ia", "ias", "ia", "íamos", "íeis", "iam"},
+	},
+}
+
+// irregulars overrides the tenses in which a verb is irregular; tenses not
+// listed are conjugated regularly.
+var irregulars = map[string]map[string][]string{
+	"ser": {
+		Present:   {"sou", "és", "é", "somos", "sois", "são"},
+		Preterite: {"fui", "foste", "foi", "fomos", "fostes", "foram"},
+		Imperfect: {"era", "eras", "era", "éramos", "éreis", "eram"},
+	},
+	"ir": {
+		Present:   {"vou", "vais", "vai", "vamos", "ides", "vão"},
+		Preterite: {"fui", "foste", "foi", "fomos", "fostes", "foram"},
+		Imperfect: {"ia", "ias", "ia", "íamos", "íeis", "iam"},
+	},
+	"ter": {
+		Present:   {"tenho", "tens", "tem", "temos", "tendes", "têm"},
+		Preterite: {"tive", "tiveste", "teve", "tivemos", "tivestes", "tiveram"},
+		Imperfect: {"tinha", "tinhas", "tinha", "tínhamos", "tínheis", "tinham"},
+	},
+	"estar": {
+		Present:   {"estou", "estás", "está", "estamos", "estais", "estão"},
+		Preterite: {"estive", "estiveste", "esteve", "estivemos", "estivestes", "estiveram"},
+	},
+	"fazer": {
+		Present:   {"faço", "fazes", "faz", "fazemos", "fazeis", "fazem"},
+		Preterite: {"fiz", "fizeste", "fez", "fizemos", "fizestes", "fizeram"},
+	},
+	"poder": {
+		Present:   {"posso", "podes", "pode", "podemos", "podeis", "podem"},
+		Preterite: {"pude", "pudeste", "pôde", "pudemos", "pudestes", "puderam"},
+	},
+	"dizer": {
+		Present:   {"digo", "dizes", "diz", "dizemos", "dizeis", "dizem"},
+		Preterite: {"disse", "disseste", "disse", "dissemos", "dissestes", "disseram"},
+	},
+	"haver": {
+		Present:   {"hei", "hás", "há", "havemos", "haveis", "hão"},
+		Preterite: {"houve", "houveste", "houve", "houvemos", "houvestes", "houveram"},
+	},
+	"ver": {
+		Present:   {"vejo", "vês", "vê", "vemos", "vedes", "veem"},
+		Preterite: {"vi", "viste", "viu", "vimos", "vistes", "viram"},
+	},
+	"vir": {
+		Present:   {"venho", "vens", "vem", "vimos", "vindes", "vêm"},
+		Preterite: {"vim", "vieste", "veio", "viemos", "viestes", "vieram"},
+		Imperfect: {"vinha", "vinhas", "vinha", "vínhamos", "vínheis", "vinham"},
+	},
+	"dar": {
+		Present:   {"dou", "dás", "dá", "damos", "dais", "dão"},
+		Preterite: {"dei", "deste", "deu", "demos", "destes", "deram"},
+	},
+	"saber": {
+		Present:   {"sei", "sabes", "sabe", "sabemos", "sabeis", "sabem"},
+		Preterite: {"soube", "soubeste", "soube", "soubemos", "soubestes", "souberam"},
+	},
+	"querer": {
+		Present:   {"quero", "queres", "quer", "queremos", "quereis", "querem"},
+		Preterite: {"quis", "quiseste", "quis", "quisemos", "quisestes", "quiseram"},
+	},
+	"pôr": {
+		Present:   {"ponho", "pões", "põe", "pomos", "pondes", "põem"},
+		Preterite: {"pus", "puseste", "pôs", "pusemos", "pusestes", "puseram"},
+		Imperfect: {"punha", "punhas", "punha", "púnhamos", "púnheis", "punham"},
+	},
+}
+
+// ValidTense reports whether tense is one of Tenses.
+func ValidTense(tense string) bool {
+	for _, t := range Tenses {
+		if t == tense {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIrregular reports whether the verb has an entry in the irregular table.
+func IsIrregular(infinitive string) bool {
+	_, ok := irregulars[normalize(infinitive)]
+	return ok
+}
+
+// Conjugate returns every form of the verb by tense and person.
+func Conjugate(infinitive string) (map[string]map[string]string, error) {
+	forms := make(map[string]map[string]string, len(Tenses))
+	for _, tense := range Tenses {
+		tenseForms, err := conjugateTense(normalize(infinitive), tense)
+		if err != nil {
+			return nil, err
+		}
+		forms[tense] = make(map[string]string, len(grammar.Persons))
+		for i, person := range grammar.Persons {
+			forms[tense][person] = tenseForms[i]
+		}
+	}
+	return forms, nil
+}
+
+// Form returns the verb's form for one tense and person.
+func Form(infinitive, tense, person string) (string, error) {
+	if !ValidTense(tense) {
+		return "", fmt.Errorf("unknown tense %q", tense)
+	}
+	forms, err := conjugateTense(normalize(infinitive), tense)
+	if err != nil {
+		return "", err
+	}
+	for i, p := range grammar.Persons {
+		if p == person {
+			return forms[i], nil
+		}
+	}
+	return "", fmt.Errorf("unknown person %q", person)
+}
+
+// normalize lowercases an infinitive and drops surrounding spaces.
+func normalize(infinitive string) string {
+	return strings.ToLower(strings.TrimSpace(infinitive))
+}
+
+// conjugateTense returns the six forms of a normalized infinitive in one tense.
+func conjugateTense(infinitive, tense string) ([]string, error) {
+	if forms, ok := irregulars[infinitive][tense]; ok {
+		return forms, nil
+	}
+
+	if len(infinitive) < 3 {
+		return nil, fmt.Errorf("%q is not a Portuguese infinitive", infinitive)
+	}
+	stem, ending := infinitive[:len(infinitive)-2], infinitive[len(infinitive)-2:]
+	endings, ok := regularEndings[ending][tense]
+	if !ok {
+		return nil, fmt.Errorf("%q is not a Portuguese infinitive", infinitive)
+	}
+
+	forms := make([]string, len(endings))
+	for i, e := range endings {
+		forms[i] = stem + e
+	}
+	forms[0] = firstPersonSpelling(stem, ending, tense, forms[0])
+	return forms, nil
+}
+
+// firstPersonSpelling applies the stem spelling changes that keep the stem's
+// sound before the first person singular ending: ficar → fiquei, chegar → cheguei,
+// começar → comecei, conhecer → conheço, proteger → protejo, erguer → ergo.
+func firstPersonSpelling(stem, ending, tense, form string) string {
+	switch {
+	case ending == "ar" && tense == Preterite:
+		switch {
+		case strings.HasSuffix(stem, "c"):
+			return strings.TrimSuffix(stem, "c") + "quei"
+		case strings.HasSuffix(stem, "g"):
+			return stem + "uei"
+		case strings.HasSuffix(stem, "ç"):
+			return strings.TrimSuffix(stem, "ç") + "cei"
+		}
+	case ending != "ar" && tense == Present:
+		switch {
+		case strings.HasSuffix(stem, "gu"):
+			return strings.TrimSuffix(stem, "u") + "o"
+		case strings.HasSuffix(stem, "c"):
+			return strings.TrimSuffix(stem, "c") + "ço"
+		case strings.HasSuffix(stem, "g"):
+			return strings.TrimSuffix(stem, "g") + "jo"
+		}
+	}
+	return form
+}
+
+// pronouns are the subject pronouns shown in drill prompts, by person.
+var pronouns = map[string]string{
+	"1s": "eu", "2s": "tu", "3s": "ele/ela", "1p": "nós", "2p": "vós", "3p": "eles/elas",
+}
+
+// IsVerb reports whether a word is a Portuguese verb that can be drilled.
+func IsVerb(word models.Word) bool {
+	return word.TargetLanguage == "pt" && grammar.PartOfSpeech(word.Parts) == "verb"
+}
+
+// Expected returns the correct form of a verb word. Forms stored in the word's
+// grammar take precedence over generated ones.
+func Expected(word models.Word, tense, person string) (string, error) {
+	if word.Grammar != nil {
+		if form, ok := word.Grammar.Conjugations[tense][person]; ok {
+			return form, nil
+		}
+	}
+	return Form(word.TargetText, tense, person)
+}
+
+// Drills returns a drill for every person of every verb among words, in the
+// given tense or in every tense when tense is empty. Words that cannot be
+// conjugated are skipped.
+func Drills(words []models.Word, tense string) []models.ConjugationDrill {
+	tenses := Tenses
+	if tense != "" {
+		tenses = []string{tense}
+	}
+
+	drills := []models.ConjugationDrill{}
+	for _, word := range words {
+		if !IsVerb(word) {
+			continue
+		}
+		infinitive := normalize(word.TargetText)
+		for _, t := range tenses {
+			if _, err := Expected(word, t, grammar.Persons[0]); err != nil {
+				continue
+			}
+			for _, person := range grammar.Persons {
+				drills = append(drills, models.ConjugationDrill{
+					WordID:     word.ID,
+					Infinitive: infinitive,
+					Tense:      t,
+					Person:     person,
+					Prompt:     fmt.Sprintf("%s ___ (%s, %s)", pronouns[person], infinitive, t),
+				})
+			}
+		}
+	}
+	return drills
+}
+
+// Check reports whether answer matches the expected form, ignoring case and
+// surrounding spaces. Accents must be right.
+func Check(answer, expected string) bool {
+	return strings.EqualFold(strings.TrimSpace(answer), expected)
+}
@@ -0,0 +1,112 @@
+package conjugation
+
+import (
+	"testing"
+
+	"backend_go/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConjugateRegularVerbs(t *testing.T) {
+	forms, err := Conjugate("falar")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"1s": "falo", "2s": "falas", "3s": "fala", "1p": "falamos", "2p": "falais", "3p": "falam"}, forms[Present])
+	assert.Equal(t, "falei", forms[Preterite]["1s"])
+	assert.Equal(t, "falávamos", forms[Imperfect]["1p"])
+
+	forms, err = Conjugate("comer")
+	require.NoError(t, err)
+	assert.Equal(t, "come", forms[Present]["3s"])
+	assert.Equal(t, "comeu", forms[Preterite]["3s"])
+	assert.Equal(t, "comíamos", forms[Imperfect]["1p"])
+
+	forms, err = Conjugate(" Partir ")
+	require.NoError(t, err)
+	assert.Equal(t, "partimos", forms[Present]["1p"])
+	assert.Equal(t, "partiram", forms[Preterite]["3p"])
+	assert.Equal(t, "partia", forms[Imperfect]["3s"])
+}
+
+func TestSpellingChanges(t *testing.T) {
+	cases := []struct{ infinitive, tense, want string }{
+		{"ficar", Preterite, "fiquei"},
+		{"chegar", Preterite, "cheguei"},
+		{"começar", Preterite, "comecei"},
+		{"conhecer", Present, "conheço"},
+		{"proteger", Present, "protejo"},
+		{"erguer", Present, "ergo"},
+		{"distinguir", Present, "distingo"},
+	}
+	for _, tc := range cases {
+		form, err := Form(tc.infinitive, tc.tense, "1s")
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, form, tc.infinitive)
+	}
+
+	form, err := Form("ficar", Preterite, "3s")
+	require.NoError(t, err)
+	assert.Equal(t, "ficou", form)
+}
+
+func TestIrregularVerbs(t *testing.T) {
+	cases := []struct{ infinitive, tense, person, want string }{
+		{"ser", Present, "1s", "sou"},
+		{"ser", Imperfect, "1p", "éramos"},
+		{"ir", Preterite, "3s", "foi"},
+		{"ir", Imperfect, "1s", "ia"},
+		{"ter", Present, "3p", "têm"},
+		{"estar", Preterite, "1s", "estive"},
+		{"estar", Imperfect, "3s", "estava"},
+		{"pôr", Present, "1s", "ponho"},
+	}
+	for _, tc := range cases {
+		form, err := Form(tc.infinitive, tc.tense, tc.person)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, form, tc.infinitive+" "+tc.tense+" "+tc.person)
+	}
+
+	assert.True(t, IsIrregular("Ser"))
+	assert.False(t, IsIrregular("falar"))
+}
+
+func TestInvalidInput(t *testing.T) {
+	_, err := Conjugate("casa")
+	assert.Error(t, err)
+	_, err = Form("falar", "future", "1s")
+	assert.Error(t, err)
+	_, err = Form("falar", Present, "eu")
+	assert.Error(t, err)
+}
+
+func TestDrills(t *testing.T) {
+	words := []models.Word{
+		{ID: 1, TargetLanguage: "pt", TargetText: "falar", Parts: "verb"},
+		{ID: 2, TargetLanguage: "pt", TargetText: "casa", Parts: "noun"},
+		{ID: 3, TargetLanguage: "pt", TargetText: "falar alto", Parts: "verb"},
+		{ID: 4, TargetLanguage: "es", TargetText: "hablar", Parts: "verb"},
+	}
+
+	drills := Drills(words, Present)
+	require.Len(t, drills, 6)
+	assert.Equal(t, models.ConjugationDrill{WordID: 1, Infinitive: "falar", Tense: Present, Person: "1s",
+		Prompt: "eu ___ (falar, present)"}, drills[0])
+	assert.Len(t, Drills(words, ""), 18)
+}
+
+func TestExpectedPrefersStoredForms(t *testing.T) {
+	word := models.Word{TargetLanguage: "pt", TargetText: "falar", Parts: "verb",
+		Grammar: &models.Grammar{Conjugations: map[string]map[string]string{Present: {"2p": "falam"}}}}
+
+	form, err := Expected(word, Present, "2p")
+	require.NoError(t, err)
+	assert.Equal(t, "falam", form)
+
+	form, err = Expected(word, Present, "1s")
+	require.NoError(t, err)
+	assert.Equal(t, "falo", form)
+
+	assert.True(t, Check(" Falo ", "falo"))
+	assert.False(t, Check("falavamos", "falávamos"))
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend_go/conjugation"
+	"backend_go/db"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Conjugation drill limits for GET /api/study_sessions/:id/conjugation_drills.
+const (
+	defaultConjugationDrills = 10
+	maxConjugationDrills     = 100
+)
+
+// conjugationAnswer is one answer submitted to POST /api/study_sessions/:id/conjugation_answers.
+type conjugationAnswer struct {
+	WordID     int    `json:"word_id"`
+	Tense      string `json:"tense"`
+	Person     string `json:"person"`
+	Answer     string `json:"answer"`
+	ResponseMS *int   `json:"response_ms"`
+}
+
+// gradedResult reports how one drill or exercise answer was graded.
+type gradedResult struct {
+	reviewResult
+	Correct  bool   `json:"correct"`
+	Expected string `json:"expected,omitempty"`
+}
+
+// sessionWords loads the request user's study session named by the :id parameter
+// and the words in its group matching filter and accepted by keep (every word when
+// keep is nil), keyed by word ID. It writes an error response and returns nil when the session is not found.
+func sessionWords(c *gin.Context, filter db.WordFilter, keep func(models.Word) bool) (*models.StudySession, map[int]models.Word) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid study session ID"})
+		return nil, nil
+	}
+
+	session, err := db.GetStudySessionByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session"})
+		log.Println("Failed to fetch study session:", err)
+		return nil, nil
+	}
+	if session == nil || session.UserID != currentUser(c).ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
+		return nil, nil
+	}
+
+	words, err := db.GetGroupWords(dbConn, session.GroupID, db.SessionScope(session), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group words"})
+		log.Println("Failed to fetch group words:", err)
+		return nil, nil
+	}
+
+	kept := make(map[int]models.Word)
+	for _, word := range words {
+		if keep == nil || keep(word) {
+			kept[word.ID] = word
+		}
+	}
+	return session, kept
+}
+
+// getConjugationDrillsHandler handles the GET /api/study_sessions/:id/conjugation_drills endpoint.
+// It returns a random selection of conjugation drills for the verbs in the session's
+// group, optionally limited to one tense and by the word filter parameters, such as tag.
+func getConjugationDrillsHandler(c *gin.Context) {
+	tense := c.Query("tense")
+	if tense != "" && !conjugation.ValidTense(tense) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tense must be present, preterite or imperfect"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultConjugationDrills)))
+	if err != nil || limit < 1 || limit > maxConjugationDrills {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxConjugationDrills)})
+		return
+	}
+
+	session, verbs := sessionWords(c, wordFilterParam(c), conjugation.IsVerb)
+	if session == nil {
+		return
+	}
+
+	words := make([]models.Word, 0, len(verbs))
+	for _, word := range verbs {
+		words = append(words, word)
+	}
+
+	drills := conjugation.Drills(words, tense)
+	rand.Shuffle(len(drills), func(i, j int) { drills[i], drills[j] = drills[j], drills[i] })
+	if len(drills) > limit {
+		drills = drills[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": drills})
+}
+
+// createConjugationAnswersHandler handles the POST /api/study_sessions/:id/conjugation_answers endpoint.
+// Each answer is graded against the verb's conjugation and recorded as a review of
+// the verb; answers that cannot be graded are reported per item and skipped.
+func createConjugationAnswersHandler(c *gin.Context) {
+	var answers []conjugationAnswer
+	if err := c.BindJSON(&answers); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(answers) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No answers provided"})
+		return
+	}
+	if len(answers) > maxReviewBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many answers in one batch", "max_batch_size": maxReviewBatchSize})
+		return
+	}
+
+	session, verbs := sessionWords(c, db.WordFilter{}, conjugation.IsVerb)
+	if session == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	results := make([]gradedResult, len(answers))
+	items := []models.WordReviewItem{}
+	itemIndexes := []int{}
+	for i, answer := range answers {
+		results[i] = gradedResult{reviewResult: reviewResult{Index: i, WordID: answer.WordID, Status: "rejected"}}
+
+		word, ok := verbs[answer.WordID]
+		if !ok {
+			results[i].Error = "word is not a verb in the study session's group"
+			continue
+		}
+		if answer.ResponseMS != nil && *answer.ResponseMS < 0 {
+			results[i].Error = "response_ms must not be negative"
+			continue
+		}
+		expected, err := conjugation.Expected(word, answer.Tense, answer.Person)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		results[i].Expected = expected
+		results[i].Correct = conjugation.Check(answer.Answer, expected)
+		items = append(items, models.WordReviewItem{
+			WordID:         word.ID,
+			StudySessionID: session.ID,
+			Correct:        results[i].Correct,
+			CreatedAt:      now,
+			ResponseMS:     answer.ResponseMS,
+			GivenAnswer:    answer.Answer,
+			ActivityType:   models.ActivityTypeConjugation,
+		})
+		itemIndexes = append(itemIndexes, i)
+	}
+
+	recordGradedAnswers(c, session.ID, results, items, itemIndexes)
+}
+
+// recordGradedAnswers stores the reviews of graded answers, where items[i] is the
+// review for results[itemIndexes[i]], and responds with the results and the
+// session's totals. The response is 201, or 400 when no answer could be graded.
+func recordGradedAnswers(c *gin.Context, sessionID int, results []gradedResult, items []models.WordReviewItem, itemIndexes []int) {
+	if len(items) > 0 {
+		ids, err := db.CreateWordReviewItems(dbConn, items)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record word reviews"})
+			log.Println("Failed to record word reviews:", err)
+			return
+		}
+		reviewsRecorded.Add(float64(len(ids)))
+		for i, index := range itemIndexes {
+			results[index].Status = "created"
+			results[index].ID = ids[i]
+		}
+	}
+
+	totals, err := db.GetStudySessionTotals(dbConn, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session totals"})
+		log.Println("Failed to fetch study session totals:", err)
+		return
+	}
+
+	status := http.StatusCreated
+	if len(items) == 0 {
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"items":   results,
+		"created": len(items),
+		"totals":  totals,
+	})
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	dbpkg "backend_go/db"
+	"backend_go/models"
+	"backend_go/sessiontoken"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConjugationDrills(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+	tokenSigner = sessiontoken.NewSigner([]byte("test-secret"), time.Hour)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	addWord := func(word models.Word) int {
+		id, err := dbpkg.CreateWord(db, &word)
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO words_groups (word_id, group_id) VALUES (?, 1)", id)
+		require.NoError(t, err)
+		return id
+	}
+	falar := addWord(models.Word{SourceText: "to speak", TargetText: "falar", Parts: "verb"})
+	ser := addWord(models.Word{SourceText: "to be", TargetText: "ser", Parts: "verb"})
+	casa := addWord(models.Word{SourceText: "house", TargetText: "casa", Parts: "noun"})
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := request("POST", "/api/study_sessions", `{"GroupID": 1, "StudyActivityID": 1}`)
+	require.Equal(t, http.StatusCreated, resp.Code)
+	var session struct{ ID int }
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &session))
+	sessionURL := "/api/study_sessions/" + strconv.Itoa(session.ID)
+
+	t.Run("Drills cover the group's verbs", func(t *testing.T) {
+		resp := request("GET", sessionURL+"/conjugation_drills?tense=present&limit=100", "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var drills struct{ Items []models.ConjugationDrill }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &drills))
+		require.Len(t, drills.Items, 12)
+		for _, drill := range drills.Items {
+			assert.Contains(t, []int{falar, ser}, drill.WordID)
+			assert.Equal(t, "present", drill.Tense)
+		}
+
+		resp = request("GET", sessionURL+"/conjugation_drills", "")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &drills))
+		assert.Len(t, drills.Items, defaultConjugationDrills)
+
+		assert.Equal(t, http.StatusBadRequest, request("GET", sessionURL+"/conjugation_drills?tense=future", "").Code)
+		assert.Equal(t, http.StatusNotFound, request("GET", "/api/study_sessions/999/conjugation_drills", "").Code)
+	})
+
+	t.Run("Answers are graded and recorded as reviews of the verb", func(t *testing.T) {
+		resp := request("POST", sessionURL+"/conjugation_answers", `[
+			{"word_id": `+strconv.Itoa(falar)+`, "tense": "imperfect", "person": "1p", "answer": "Falávamos"},
+			{"word_id": `+strconv.Itoa(ser)+`, "tense": "present", "person": "1s", "answer": "é", "response_ms": 900},
+			{"word_id": `+strconv.Itoa(ser)+`, "tense": "future", "person": "1s", "answer": "serei"},
+			{"word_id": `+strconv.Itoa(casa)+`, "tense": "present", "person": "1s", "answer": "caso"}
+		]`)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+		var body struct {
+			Items   []gradedResult
+			Created int
+			Totals  models.StudySessionTotals
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Len(t, body.Items, 4)
+		assert.Equal(t, 2, body.Created)
+		assert.True(t, body.Items[0].Correct)
+		assert.False(t, body.Items[1].Correct)
+		assert.Equal(t, "sou", body.Items[1].Expected)
+		assert.Equal(t, "rejected", body.Items[2].Status)
+		assert.Equal(t, "rejected", body.Items[3].Status)
+		assert.Equal(t, 1, body.Totals.CorrectCount)
+
+		var activityType, givenAnswer string
+		require.NoError(t, db.QueryRow("SELECT activity_type, given_answer FROM word_review_items WHERE id = ?",
+			body.Items[1].ID).Scan(&activityType, &givenAnswer))
+		assert.Equal(t, models.ActivityTypeConjugation, activityType)
+		assert.Equal(t, "é", givenAnswer)
+	})
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend_go/models"
+)
+
+// CreateAuthToken stores a login session or API token under the hash of its value.
+func CreateAuthToken(db *sql.DB, token *models.AuthToken, tokenHash string) (int, error) {
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := db.Exec(`INSERT INTO auth_tokens (user_id, kind, name, token_hash, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		token.UserID, token.Kind, token.Name, tokenHash, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create auth token: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	token.ID = int(id)
+	return int(id), nil
+}
+
+// GetAuthTokenUser retrieves a token of the given kind by the hash of its value,
+// together with its user. It returns nil, nil, nil when no such token exists;
+// expiry is left to the caller.
+func GetAuthTokenUser(db *sql.DB, tokenHash, kind string) (*models.AuthToken, *models.User, error) {
+	row := db.QueryRow(`
+        SELECT t.id, t.user_id, t.kind, t.name, t.created_at, t.expires_at, t.last_used_at,
+               u.id, u.username, u.display_name, u.role, u.created_at
+        FROM auth_tokens t
+        JOIN users u ON u.id = t.user_id
+        WHERE t.token_hash = ? AND t.kind = ?`, tokenHash, kind)
+
+	var token models.AuthToken
+	var user models.User
+	err := row.Scan(&token.ID, &token.UserID, &token.Kind, &token.Name, &token.CreatedAt, &token.ExpiresAt, &token.LastUsedAt,
+		&user.ID, &user.Username, &user.DisplayName, &user.Role, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, nil // Token not found
+		}
+		return nil, nil, fmt.Errorf("failed to scan auth token row: %w", err)
+	}
+
+	return &token, &user, nil
+}
+
+// TouchAuthToken records when a token was last used.
+func TouchAuthToken(db *sql.DB, id int, usedAt time.Time) error {
+	if _, err := db.Exec("UPDATE auth_tokens SET last_used_at = ? WHERE id = ?", usedAt.UTC(), id); err != nil {
+		return fmt.Errorf("failed to update auth token: %w", err)
+	}
+	return nil
+}
+
+// GetAuthTokens retrieves a user's tokens of the given kind, newest first.
+func GetAuthTokens(db *sql.DB, userID int, kind string) ([]models.AuthToken, error) {
+	rows, err := db.Query(`
+        SELECT id, user_id, kind, name, created_at, expires_at, last_used_at
+        FROM auth_tokens
+        WHERE user_id = ? AND kind = ?
+        ORDER BY created_at DESC, id DESC`, userID, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query auth tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []models.AuthToken{}
+	for rows.Next() {
+		var token models.AuthToken
+		if err := rows.Scan(&token.ID, &token.UserID, &token.Kind, &token.Name, &token.CreatedAt, &token.ExpiresAt, &token.LastUsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan auth token row: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating auth token rows: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// DeleteAuthToken deletes a user's token of the given kind. It reports whether
+// a token was deleted.
+func DeleteAuthToken(db *sql.DB, id, userID int, kind string) (bool, error) {
+	result, err := db.Exec("DELETE FROM auth_tokens WHERE id = ? AND user_id = ? AND kind = ?", id, userID, kind)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete auth token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// DeleteAuthTokenByHash deletes the token stored under tokenHash, if any.
+func DeleteAuthTokenByHash(db *sql.DB, tokenHash string) error {
+	if _, err := db.Exec("DELETE FROM auth_tokens WHERE token_hash = ?", tokenHash); err != nil {
+		return fmt.Errorf("failed to delete auth token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend_go/models"
+)
+
+// GetClasses retrieves the classes taught by teacherID, or every class when teacherID is 0.
+func GetClasses(db *sql.DB, teacherID int) ([]models.Class, error) {
+	rows, err := db.Query(`
+        SELECT c.id, c.name, c.teacher_id, c.created_at,
+               (SELECT COUNT(*) FROM class_members cm WHERE cm.class_id = c.id) as member_count
+        FROM classes c
+        WHERE ? = 0 OR c.teacher_id = ?
+        ORDER BY c.id`, teacherID, teacherID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query classes: %w", err)
+	}
+	defer rows.Close()
+
+	classes := []models.Class{}
+	for rows.Next() {
+		var class models.Class
+		if err := rows.Scan(&class.ID, &class.Name, &class.TeacherID, &class.CreatedAt, &class.MemberCount); err != nil {
+			return nil, fmt.Errorf("failed to scan class row: %w", err)
+		}
+		classes = append(classes, class)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating class rows: %w", err)
+	}
+
+	return classes, nil
+}
+
+// GetClassByID retrieves a class from the database by its ID.
+func GetClassByID(db *sql.DB, id int) (*models.Class, error) {
+	row := db.QueryRow(`
+        SELECT c.id, c.name, c.teacher_id, c.created_at,
+               (SELECT COUNT(*) FROM class_members cm WHERE cm.class_id = c.id) as member_count
+        FROM classes c
+        WHERE c.id = ?`, id)
+
+	var class models.Class
+	err := row.Scan(&class.ID, &class.Name, &class.TeacherID, &class.CreatedAt, &class.MemberCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Class not found
+		}
+		return nil, fmt.Errorf("failed to scan class row: %w", err)
+	}
+
+	return &class, nil
+}
+
+// CreateClass creates a new class in the database.
+func CreateClass(db *sql.DB, class *models.Class) (int, error) {
+	if class.CreatedAt.IsZero() {
+		class.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := db.Exec("INSERT INTO classes (name, teacher_id, created_at) VALUES (?, ?, ?)",
+		class.Name, class.TeacherID, class.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create class: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	class.ID = int(id)
+	return int(id), nil
+}
+
+// DeleteClass deletes a class together with its members and assignments.
+func DeleteClass(db *sql.DB, id int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		"DELETE FROM assignments WHERE class_id = ?",
+		"DELETE FROM class_members WHERE class_id = ?",
+		"DELETE FROM classes WHERE id = ?",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			return fmt.Errorf("failed to delete class: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit class deletion: %w", err)
+	}
+
+	return nil
+}
+
+// GetClassMembers retrieves the users enrolled in a class ordered by ID.
+func GetClassMembers(db *sql.DB, classID int) ([]models.User, error) {
+	rows, err := db.Query(`
+        SELECT u.id, u.username, u.display_name, u.role, u.created_at
+        FROM class_members cm
+        JOIN users u ON u.id = cm.user_id
+        WHERE cm.class_id = ?
+        ORDER BY u.id`, classID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query class members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.DisplayName, &user.Role, &user.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan class member row: %w", err)
+		}
+		members = append(members, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating class member rows: %w", err)
+	}
+
+	return members, nil
+}
+
+// AddClassMember enrolls a user in a class. It reports false when the user
+// was already a member.
+func AddClassMember(db *sql.DB, classID, userID int) (bool, error) {
+	result, err := db.Exec("INSERT OR IGNORE INTO class_members (class_id, user_id, joined_at) VALUES (?, ?, ?)",
+		classID, userID, time.Now().UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to add class member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// RemoveClassMember removes a user from a class. It reports false when the
+// user was not a member.
+func RemoveClassMember(db *sql.DB, classID, userID int) (bool, error) {
+	result, err := db.Exec("DELETE FROM class_members WHERE class_id = ? AND user_id = ?", classID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove class member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+const selectAssignmentSQL = `
+        SELECT a.id, a.class_id, a.group_id, g.name, a.due_at, a.target_accuracy, a.target_sessions, a.created_at
+        FROM assignments a
+        JOIN groups g ON g.id = a.group_id`
+
+// scanAssignment scans a row selected with selectAssignmentSQL.
+func scanAssignment(row interface{ Scan(...interface{}) error }) (models.Assignment, error) {
+	var assignment models.Assignment
+	err := row.Scan(&assignment.ID, &assignment.ClassID, &assignment.GroupID, &assignment.GroupName,
+		&assignment.DueAt, &assignment.TargetAccuracy, &assignment.TargetSessions, &assignment.CreatedAt)
+	return assignment, err
+}
+
+// GetAssignments retrieves a class's assignments ordered by due date.
+func GetAssignments(db *sql.DB, classID int) ([]models.Assignment, error) {
+	rows, err := db.Query(selectAssignmentSQL+" WHERE a.class_id = ? ORDER BY a.due_at, a.id", classID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []models.Assignment{}
+	for rows.Next() {
+		assignment, err := scanAssignment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan assignment row: %w", err)
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assignment rows: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// GetAssignmentByID retrieves an assignment from the database by its ID.
+func GetAssignmentByID(db *sql.DB, id int) (*models.Assignment, error) {
+	assignment, err := scanAssignment(db.QueryRow(selectAssignmentSQL+" WHERE a.id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Assignment not found
+		}
+		return nil, fmt.Errorf("failed to scan assignment row: %w", err)
+	}
+
+	return &assignment, nil
+}
+
+// CreateAssignment creates a new assignment in the database.
+func CreateAssignment(db *sql.DB, assignment *models.Assignment) (int, error) {
+	if assignment.CreatedAt.IsZero() {
+		assignment.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := db.Exec(`INSERT INTO assignments (class_id, group_id, due_at, target_accuracy, target_sessions, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		assignment.ClassID, assignment.GroupID, assignment.DueAt.UTC(), assignment.TargetAccuracy,
+		assignment.TargetSessions, assignment.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create assignment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	assignment.ID = int(id)
+	return int(id), nil
+}
+
+// DeleteAssignment deletes an assignment from the database.
+func DeleteAssignment(db *sql.DB, id int) error {
+	if _, err := db.Exec("DELETE FROM assignments WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete assignment: %w", err)
+	}
+	return nil
+}
+
+// GetAssignmentProgress retrieves every class member's sessions and reviews on the
+// assigned group or its subgroups between the assignment's creation and due date,
+// and how many of those sessions reached the target accuracy on their own. Status
+// is left to the caller.
+func GetAssignmentProgress(db *sql.DB, assignment *models.Assignment) ([]models.AssignmentProgress, error) {
+	query := `
+        WITH RECURSIVE ` + subgroupsCTE + `,
+        assigned_sessions AS (
+            SELECT id, user_id
+            FROM study_sessions
+            WHERE group_id IN (SELECT id FROM subgroups)
+              AND julianday(created_at) >= julianday(?) AND julianday(created_at) <= julianday(?)
+        ),
+        session_accuracy AS (
+            SELECT s.user_id, AVG(CASE WHEN wri.is_correct = 1 THEN 100.0 ELSE 0.0 END) as accuracy
+            FROM assigned_sessions s
+            JOIN word_review_items wri ON wri.study_session_id = s.id
+            GROUP BY s.id, s.user_id
+        )
+        SELECT
+            u.id,
+            u.username,
+            u.display_name,
+            COUNT(DISTINCT wri.study_session_id) as study_sessions,
+            (SELECT COUNT(*) FROM session_accuracy sa WHERE sa.user_id = u.id AND sa.accuracy >= ?) as sessions_met,
+            COUNT(wri.id) as review_count,
+            COALESCE(SUM(CASE WHEN wri.is_correct = 1 THEN 1 ELSE 0 END), 0) as correct_count,
+            COALESCE(ROUND(AVG(CASE WHEN wri.is_correct = 1 THEN 100.0 WHEN wri.is_correct = 0 THEN 0.0 END), 1), 0) as accuracy,
+            MAX(wri.created_at) as last_review
+        FROM class_members cm
+        JOIN users u ON u.id = cm.user_id
+        LEFT JOIN assigned_sessions ss ON ss.user_id = u.id
+        LEFT JOIN word_review_items wri ON wri.study_session_id = ss.id
+        WHERE cm.class_id = ?
+        GROUP BY u.id, u.username, u.display_name
+        ORDER BY u.id`
+
+	// Sessions record their start to the second, so one begun in the same second as
+	// the assignment still falls inside the window
+	rows, err := db.Query(query, assignment.GroupID,
+		assignment.CreatedAt.UTC().Truncate(time.Second),
+		assignment.DueAt.UTC(),
+		assignment.TargetAccuracy,
+		assignment.ClassID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying assignment progress: %w", err)
+	}
+	defer rows.Close()
+
+	progress := []models.AssignmentProgress{}
+	for rows.Next() {
+		var learner models.AssignmentProgress
+		var lastReview sql.NullString
+		if err := rows.Scan(&learner.UserID, &learner.Username, &learner.DisplayName, &learner.StudySessions, &learner.SessionsMet,
+			&learner.ReviewCount, &learner.CorrectCount, &learner.Accuracy, &lastReview); err != nil {
+			return nil, fmt.Errorf("error scanning assignment progress row: %w", err)
+		}
+		if lastReview.Valid {
+			lastStudyDate, err := parseTimestamp(lastReview.String)
+			if err != nil {
+				return nil, err
+			}
+			learner.LastStudyDate = &lastStudyDate
+		}
+		progress = append(progress, learner)
+	}
+
+	return progress, rows.Err()
+}
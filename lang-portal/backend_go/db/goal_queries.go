@@ -0,0 +1,188 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend_go/models"
+)
+
+// GetGoal retrieves a user's daily goal.
+func GetGoal(db *sql.DB, userID int) (*models.Goal, error) {
+	row := db.QueryRow("SELECT user_id, daily_review_target, time_zone, created_at, updated_at FROM goals WHERE user_id = ?", userID)
+
+	var goal models.Goal
+	err := row.Scan(&goal.UserID, &goal.DailyReviewTarget, &goal.TimeZone, &goal.CreatedAt, &goal.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Goal not configured
+		}
+		return nil, fmt.Errorf("failed to scan goal row: %w", err)
+	}
+
+	return &goal, nil
+}
+
+// GetGoals retrieves the daily goals of all users.
+func GetGoals(db *sql.DB) ([]models.Goal, error) {
+	rows, err := db.Query("SELECT user_id, daily_review_target, time_zone, created_at, updated_at FROM goals ORDER BY user_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query goals: %w", err)
+	}
+	defer rows.Close()
+
+	var goals []models.Goal
+	for rows.Next() {
+		var goal models.Goal
+		if err := rows.Scan(&goal.UserID, &goal.DailyReviewTarget, &goal.TimeZone, &goal.CreatedAt, &goal.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan goal row: %w", err)
+		}
+		goals = append(goals, goal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating goal rows: %w", err)
+	}
+
+	return goals, nil
+}
+
+// UpdateGoal updates a user's daily review target and time zone.
+func UpdateGoal(db *sql.DB, goal *models.Goal) error {
+	goal.UpdatedAt = time.Now().UTC()
+
+	_, err := db.Exec(`
+        INSERT INTO goals (user_id, daily_review_target, time_zone, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?)
+        ON CONFLICT (user_id) DO UPDATE SET
+            daily_review_target = excluded.daily_review_target,
+            time_zone = excluded.time_zone,
+            updated_at = excluded.updated_at`,
+		goal.UserID, goal.DailyReviewTarget, goal.TimeZone, goal.UpdatedAt, goal.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update goal: %w", err)
+	}
+
+	return nil
+}
+
+// GetReviewTimestamps retrieves the creation time of every review a user made at or after since.
+func GetReviewTimestamps(db *sql.DB, userID int, since time.Time) ([]time.Time, error) {
+	rows, err := db.Query(`
+        SELECT created_at
+        FROM word_review_items
+        WHERE user_id = ? AND julianday(created_at) >= julianday(?)
+        ORDER BY created_at`, userID, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review timestamps: %w", err)
+	}
+	defer rows.Close()
+
+	var timestamps []time.Time
+	for rows.Next() {
+		var timestamp time.Time
+		if err := rows.Scan(&timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan review timestamp: %w", err)
+		}
+		timestamps = append(timestamps, timestamp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review timestamps: %w", err)
+	}
+
+	return timestamps, nil
+}
+
+// GetReviewBuckets counts a user's reviews per quarter hour, oldest first, so that
+// daily totals can be built in any time zone without loading every review.
+func GetReviewBuckets(db *sql.DB, userID int) ([]models.ReviewBucket, error) {
+	rows, err := db.Query(`
+        SELECT CAST(strftime('%s', created_at) AS INTEGER) / 900 AS bucket, COUNT(*)
+        FROM word_review_items
+        WHERE user_id = ?
+        GROUP BY bucket
+        ORDER BY bucket`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review buckets: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []models.ReviewBucket
+	for rows.Next() {
+		var bucket int64
+		var count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan review bucket: %w", err)
+		}
+		buckets = append(buckets, models.ReviewBucket{Start: time.Unix(bucket*900, 0).UTC(), Count: count})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review buckets: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// CreateGoalEvent records a goal event unless the user already has one of the same
+// type for that day. It reports whether a new event was stored.
+func CreateGoalEvent(db *sql.DB, event *models.GoalEvent) (bool, error) {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := db.Exec(`
+        INSERT OR IGNORE INTO goal_events (user_id, event_type, day, review_count, daily_review_target, created_at)
+        VALUES (?, ?, ?, ?, ?, ?)`,
+		event.UserID, event.EventType, event.Day, event.ReviewCount, event.DailyReviewTarget, event.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create goal event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	event.ID = int(id)
+
+	return true, nil
+}
+
+// GetGoalEvents retrieves a user's most recent goal events.
+func GetGoalEvents(db *sql.DB, userID, limit int) ([]models.GoalEvent, error) {
+	rows, err := db.Query(`
+        SELECT id, user_id, event_type, day, review_count, daily_review_target, created_at
+        FROM goal_events
+        WHERE user_id = ?
+        ORDER BY day DESC, id DESC
+        LIMIT ?`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query goal events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.GoalEvent{}
+	for rows.Next() {
+		var event models.GoalEvent
+		if err := rows.Scan(&event.ID, &event.UserID, &event.EventType, &event.Day, &event.ReviewCount, &event.DailyReviewTarget, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan goal event row: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating goal event rows: %w", err)
+	}
+
+	return events, nil
+}
@@ -2,15 +2,62 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
 	"backend_go/models" // Import your models package
 )
 
+// ErrGroupCycle is returned when a group would be nested under itself or one of
+// its descendants.
+var ErrGroupCycle = errors.New("group cannot be nested under itself or one of its subgroups")
+
+// subgroupsCTE is a recursive common table expression naming a group and all of
+// its descendants "subgroups". It takes the group ID as its only argument. UNION
+// rather than UNION ALL keeps it finite even if the tree was corrupted into a cycle.
+const subgroupsCTE = `subgroups(id) AS (
+            SELECT ?
+            UNION
+            SELECT g.id FROM groups g JOIN subgroups s ON g.parent_id = s.id
+        )`
+
+// groupColumns lists the columns scanGroup expects, in order.
+const groupColumns = "id, name, description, parent_id, rule"
+
+// scanGroup scans a row selected with groupColumns.
+func scanGroup(row interface{ Scan(...interface{}) error }) (models.Group, error) {
+	var group models.Group
+	var ruleJSON sql.NullString
+	if err := row.Scan(&group.ID, &group.Name, &group.Description, &group.ParentID, &ruleJSON); err != nil {
+		return group, err
+	}
+
+	if ruleJSON.Valid {
+		group.Rule = &models.GroupRule{}
+		if err := json.Unmarshal([]byte(ruleJSON.String), group.Rule); err != nil {
+			return group, fmt.Errorf("invalid rule for group %d: %w", group.ID, err)
+		}
+	}
+	return group, nil
+}
+
+// ruleValue encodes a smart group's rule for the rule column.
+func ruleValue(rule *models.GroupRule) (sql.NullString, error) {
+	if rule == nil {
+		return sql.NullString{}, nil
+	}
+	encoded, err := json.Marshal(rule)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to encode group rule: %w", err)
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
+
 // GetAllGroups retrieves all groups from the database.
 func GetAllGroups(db *sql.DB) ([]models.Group, error) {
-	rows, err := db.Query("SELECT id, name, description FROM groups")
+	rows, err := db.Query("SELECT " + groupColumns + " FROM groups")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query groups: %w", err)
 	}
@@ -18,8 +65,8 @@ func GetAllGroups(db *sql.DB) ([]models.Group, error) {
 
 	var groups []models.Group
 	for rows.Next() {
-		var group models.Group
-		if err := rows.Scan(&group.ID, &group.Name, &group.Description); err != nil {
+		group, err := scanGroup(rows)
+		if err != nil {
 			log.Println("Error scanning group row:", err)
 			continue
 		}
@@ -35,10 +82,7 @@ func GetAllGroups(db *sql.DB) ([]models.Group, error) {
 
 // GetGroupByID retrieves a group from the database by its ID.
 func GetGroupByID(db *sql.DB, id int) (*models.Group, error) {
-	row := db.QueryRow("SELECT id, name, description FROM groups WHERE id = ?", id)
-
-	var group models.Group
-	err := row.Scan(&group.ID, &group.Name, &group.Description)
+	group, err := scanGroup(db.QueryRow("SELECT "+groupColumns+" FROM groups WHERE id = ?", id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Group not found
@@ -51,8 +95,13 @@ func GetGroupByID(db *sql.DB, id int) (*models.Group, error) {
 
 // CreateGroup creates a new group in the database.
 func CreateGroup(db *sql.DB, group *models.Group) (int, error) {
-	result, err := db.Exec("INSERT INTO groups (name, description) VALUES (?, ?)",
-		group.Name, group.Description)
+	rule, err := ruleValue(group.Rule)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := db.Exec("INSERT INTO groups (name, description, parent_id, rule) VALUES (?, ?, ?, ?)",
+		group.Name, group.Description, group.ParentID, rule)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create group: %w", err)
 	}
@@ -65,10 +114,35 @@ func CreateGroup(db *sql.DB, group *models.Group) (int, error) {
 	return int(id), nil
 }
 
-// UpdateGroup updates an existing group in the database.
+// UpdateGroup updates an existing group in the database. It returns
+// ErrGroupCycle when the new parent is the group itself or one of its descendants.
 func UpdateGroup(db *sql.DB, group *models.Group) error {
-	result, err := db.Exec("UPDATE groups SET name = ?, description = ? WHERE id = ?",
-		group.Name, group.Description, group.ID)
+	rule, err := ruleValue(group.Rule)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if group.ParentID != nil {
+		var cycle bool
+		err := tx.QueryRow(`
+        WITH RECURSIVE `+subgroupsCTE+`
+        SELECT EXISTS (SELECT 1 FROM subgroups WHERE id = ?)`, group.ID, *group.ParentID).Scan(&cycle)
+		if err != nil {
+			return fmt.Errorf("failed to check group nesting: %w", err)
+		}
+		if cycle {
+			return ErrGroupCycle
+		}
+	}
+
+	result, err := tx.Exec("UPDATE groups SET name = ?, description = ?, parent_id = ?, rule = ? WHERE id = ?",
+		group.Name, group.Description, group.ParentID, rule, group.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update group: %w", err)
 	}
@@ -82,12 +156,36 @@ func UpdateGroup(db *sql.DB, group *models.Group) error {
 		return fmt.Errorf("group with id %d not found", group.ID)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit group update: %w", err)
+	}
+
 	return nil
 }
 
-// DeleteGroup deletes a group from the database.
+// GroupHasSubgroups reports whether any group is nested under the group.
+func GroupHasSubgroups(db *sql.DB, id int) (bool, error) {
+	var exists bool
+	if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM groups WHERE parent_id = ?)", id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check subgroups: %w", err)
+	}
+	return exists, nil
+}
+
+// DeleteGroup deletes a group from the database. Its subgroups move up to the
+// deleted group's parent.
 func DeleteGroup(db *sql.DB, id int) error {
-	result, err := db.Exec("DELETE FROM groups WHERE id = ?", id)
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE groups SET parent_id = (SELECT parent_id FROM groups WHERE id = ?) WHERE parent_id = ?", id, id); err != nil {
+		return fmt.Errorf("failed to move subgroups: %w", err)
+	}
+
+	result, err := tx.Exec("DELETE FROM groups WHERE id = ?", id)
 	if err != nil {
 		return fmt.Errorf("failed to delete group: %w", err)
 	}
@@ -101,5 +199,9 @@ func DeleteGroup(db *sql.DB, id int) error {
 		return fmt.Errorf("group with id %d not found", id)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit group deletion: %w", err)
+	}
+
 	return nil
 }
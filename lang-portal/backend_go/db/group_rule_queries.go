@@ -0,0 +1,107 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"backend_go/models"
+)
+
+// GroupScope is the learner and moment smart group rules are evaluated for.
+// Reviews from AsOf on are ignored, so answers given during a study session do
+// not change which words the session covers. Static groups ignore the scope.
+type GroupScope struct {
+	UserID int
+	AsOf   time.Time
+}
+
+// SessionScope evaluates smart groups for a study session's learner as of the
+// session's start, falling back to now when the start cannot be read.
+func SessionScope(session *models.StudySession) GroupScope {
+	start, err := parseTimestamp(session.CreatedAt)
+	if err != nil {
+		start = time.Now().UTC()
+	}
+	return GroupScope{UserID: session.UserID, AsOf: start}
+}
+
+// daysBefore returns the moment the given number of days before t.
+func daysBefore(t time.Time, days int) time.Time {
+	return t.UTC().AddDate(0, 0, -days)
+}
+
+// ruleWhere returns the rule's conditions on the words table, aliased w, and
+// their arguments.
+func ruleWhere(rule *models.GroupRule, scope GroupScope) (string, []interface{}) {
+	conditions, args := WordFilter{
+		SourceLanguage: rule.SourceLanguage,
+		TargetLanguage: rule.TargetLanguage,
+		PartOfSpeech:   rule.PartOfSpeech,
+		Gender:         rule.Gender,
+	}.where()
+	add := func(condition string, conditionArgs ...interface{}) {
+		conditions += " AND " + condition
+		args = append(args, conditionArgs...)
+	}
+
+	for _, tag := range rule.Tags {
+		add("EXISTS (SELECT 1 FROM word_tags t WHERE t.word_id = w.id AND t.tag = ?)", tag)
+	}
+
+	// Each review condition looks at the learner's reviews of the word before AsOf
+	reviews := "FROM word_review_items r WHERE r.word_id = w.id AND r.user_id = ? AND julianday(r.created_at) < julianday(?)"
+	accuracy := "(SELECT AVG(CASE WHEN r.is_correct = 1 THEN 100.0 ELSE 0.0 END) " + reviews + ")"
+	if rule.MinAccuracy != nil {
+		add(accuracy+" >= ?", scope.UserID, scope.AsOf, *rule.MinAccuracy)
+	}
+	if rule.MaxAccuracy != nil {
+		add(accuracy+" <= ?", scope.UserID, scope.AsOf, *rule.MaxAccuracy)
+	}
+	if rule.WrongWithinDays > 0 {
+		add("EXISTS (SELECT 1 "+reviews+" AND r.is_correct = 0 AND julianday(r.created_at) >= julianday(?))",
+			scope.UserID, scope.AsOf, daysBefore(scope.AsOf, rule.WrongWithinDays))
+	}
+	if rule.ReviewedWithinDays > 0 {
+		add("EXISTS (SELECT 1 "+reviews+" AND julianday(r.created_at) >= julianday(?))",
+			scope.UserID, scope.AsOf, daysBefore(scope.AsOf, rule.ReviewedWithinDays))
+	}
+	if rule.NotReviewedForDays > 0 {
+		add("NOT EXISTS (SELECT 1 "+reviews+" AND julianday(r.created_at) >= julianday(?))",
+			scope.UserID, scope.AsOf, daysBefore(scope.AsOf, rule.NotReviewedForDays))
+	}
+	if rule.Due != nil {
+		due := "(NOT EXISTS (SELECT 1 " + reviews + " AND julianday(r.created_at) >= julianday(?))" +
+			" OR COALESCE((SELECT r.is_correct " + reviews + " ORDER BY julianday(r.created_at) DESC, r.id DESC LIMIT 1), 0) = 0)"
+		if !*rule.Due {
+			due = "NOT " + due
+		}
+		add(due, scope.UserID, scope.AsOf, daysBefore(scope.AsOf, models.DueAfterDays), scope.UserID, scope.AsOf)
+	}
+
+	return conditions, args
+}
+
+// groupMembers returns a query selecting the IDs of the words in a group, and its
+// arguments: the words matching a smart group's rule, or the words listed for a
+// static group or any of its subgroups.
+func groupMembers(db *sql.DB, groupID int, scope GroupScope) (string, []interface{}, error) {
+	var ruleJSON sql.NullString
+	err := db.QueryRow("SELECT rule FROM groups WHERE id = ?", groupID).Scan(&ruleJSON)
+	if err != nil && err != sql.ErrNoRows {
+		return "", nil, fmt.Errorf("failed to fetch group rule: %w", err)
+	}
+
+	if ruleJSON.Valid {
+		var rule models.GroupRule
+		if err := json.Unmarshal([]byte(ruleJSON.String), &rule); err != nil {
+			return "", nil, fmt.Errorf("invalid rule for group %d: %w", groupID, err)
+		}
+		conditions, args := ruleWhere(&rule, scope)
+		return "SELECT w.id FROM words w WHERE " + conditions, args, nil
+	}
+
+	return `WITH RECURSIVE ` + subgroupsCTE + `
+            SELECT word_id FROM words_groups WHERE group_id IN (SELECT id FROM subgroups)`, []interface{}{groupID}, nil
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupRuleReviewWindow(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+
+	_, err = db.Exec(`INSERT INTO groups (id, name, description, rule) VALUES (10, 'Recent', '', '{"reviewed_within_days": 7}')`)
+	require.NoError(t, err)
+
+	// Reviews are stored in another UTC offset: word 1 exactly at the start of the
+	// window, word 2 a second before it and word 3 exactly at AsOf, which is excluded
+	asOf := time.Date(2025, 2, 8, 10, 0, 0, 0, time.UTC)
+	brt := time.FixedZone("BRT", -3*60*60)
+	reviews := map[int]time.Time{
+		1: asOf.AddDate(0, 0, -7).In(brt),
+		2: asOf.AddDate(0, 0, -7).Add(-time.Second).In(brt),
+		3: asOf.In(brt),
+	}
+	for wordID, createdAt := range reviews {
+		_, err := db.Exec("INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at) VALUES (?, 1, 1, ?)",
+			wordID, createdAt)
+		require.NoError(t, err)
+	}
+
+	wordIDs, err := GetGroupWordIDs(db, 10, GroupScope{UserID: models.DefaultUserID, AsOf: asOf})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]bool{1: true}, wordIDs)
+}
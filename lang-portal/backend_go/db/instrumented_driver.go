@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// QueryObserver receives the duration of every statement run through an
+// instrumented driver. function names the function of this package that ran it,
+// such as "GetAllWords", or is "other" for statements issued elsewhere.
+type QueryObserver func(function string, duration time.Duration, err error)
+
+// OtherFunction labels statements that were not run by a function of this package.
+const OtherFunction = "other"
+
+// RegisterInstrumentedDriver registers, under name, a driver that wraps base and
+// reports each statement to observe. Queries are timed until their rows are closed,
+// so reading the results is included.
+func RegisterInstrumentedDriver(name string, base driver.Driver, observe QueryObserver) {
+	sql.Register(name, &instrumentedDriver{base: base, observe: observe})
+}
+
+// packagePrefix is how this package's functions start in stack traces.
+var packagePrefix = reflect.TypeOf(instrumentedDriver{}).PkgPath() + "."
+
+// callerFunction returns the innermost exported function of this package on the
+// stack, without its package path or any closure suffix. Unexported helpers and
+// the driver wrapper itself are skipped, so statements are attributed to the
+// function the rest of the application called.
+func callerFunction() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if name, ok := strings.CutPrefix(frame.Function, packagePrefix); ok && name != "" && unicode.IsUpper(rune(name[0])) {
+			name, _, _ = strings.Cut(name, ".")
+			return name
+		}
+		if !more {
+			return OtherFunction
+		}
+	}
+}
+
+type instrumentedDriver struct {
+	base    driver.Driver
+	observe QueryObserver
+}
+
+func (d *instrumentedDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := d.base.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn, observe: d.observe}, nil
+}
+
+// instrumentedConn times statements run directly on the connection or through
+// statements it prepares. Connections that lack the context interfaces fall back
+// to database/sql's prepared statement path.
+type instrumentedConn struct {
+	driver.Conn
+	observe QueryObserver
+}
+
+func (c *instrumentedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedStmt{Stmt: stmt, observe: c.observe}, nil
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.observe(callerFunction(), time.Since(start), err)
+	}
+	return result, err
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	return observeRows(c.observe, start, rows, err)
+}
+
+// instrumentedStmt times executions of a prepared statement.
+type instrumentedStmt struct {
+	driver.Stmt
+	observe QueryObserver
+}
+
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var result driver.Result
+	var err error
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			result, err = s.Stmt.Exec(values)
+		}
+	}
+	s.observe(callerFunction(), time.Since(start), err)
+	return result, err
+}
+
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	return observeRows(s.observe, start, rows, err)
+}
+
+// namedValues converts positional arguments for drivers without context support.
+func namedValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, driver.ErrSkip
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// observeRows reports a failed query now, or a successful one when its rows are closed.
+func observeRows(observe QueryObserver, start time.Time, rows driver.Rows, err error) (driver.Rows, error) {
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	function := callerFunction()
+	if err != nil {
+		observe(function, time.Since(start), err)
+		return nil, err
+	}
+	return &instrumentedRows{Rows: rows, observe: observe, function: function, start: start}, nil
+}
+
+// instrumentedRows reports its query when closed.
+type instrumentedRows struct {
+	driver.Rows
+	observe  QueryObserver
+	function string
+	start    time.Time
+}
+
+func (r *instrumentedRows) Close() error {
+	err := r.Rows.Close()
+	r.observe(r.function, time.Since(r.start), err)
+	return err
+}
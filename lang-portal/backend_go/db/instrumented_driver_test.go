@@ -0,0 +1,48 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"backend_go/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentedDriver(t *testing.T) {
+	conn, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, testutils.SeedTestDB(conn))
+
+	type observation struct {
+		function string
+		failed   bool
+	}
+	var observed []observation
+	RegisterInstrumentedDriver("sqlite3-instrumented-test", conn.Driver(), func(function string, duration time.Duration, err error) {
+		assert.Positive(t, duration)
+		observed = append(observed, observation{function, err != nil})
+	})
+
+	// The shared in-memory database stays alive while conn is open
+	instrumented, err := sql.Open("sqlite3-instrumented-test", "file:testdb?mode=memory&cache=shared")
+	require.NoError(t, err)
+	defer instrumented.Close()
+
+	words, err := GetAllWords(instrumented, WordFilter{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, words)
+	require.NoError(t, SetWordTags(instrumented, 1, []string{"greeting"}))
+	_, err = instrumented.Exec("SELECT * FROM missing_table")
+	assert.Error(t, err)
+
+	require.NotEmpty(t, observed)
+	assert.Equal(t, observation{"GetAllWords", false}, observed[0])
+	for _, o := range observed[1 : len(observed)-1] {
+		assert.Equal(t, observation{"SetWordTags", false}, o)
+	}
+	assert.True(t, observed[len(observed)-1].failed)
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend_go/models"
+)
+
+const wordMediaColumns = "id, word_id, kind, mime_type, size_bytes, content_hash, original_name, created_at"
+
+// scanWordMedia scans a row selected with wordMediaColumns.
+func scanWordMedia(row interface{ Scan(...interface{}) error }) (*models.WordMedia, error) {
+	var m models.WordMedia
+	if err := row.Scan(&m.ID, &m.WordID, &m.Kind, &m.MIMEType, &m.SizeBytes, &m.ContentHash, &m.OriginalName, &m.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// CreateWordMedia records a file attached to a word.
+func CreateWordMedia(db *sql.DB, m *models.WordMedia) (int, error) {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := db.Exec(`INSERT INTO word_media (word_id, kind, mime_type, size_bytes, content_hash, original_name, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		m.WordID, m.Kind, m.MIMEType, m.SizeBytes, m.ContentHash, m.OriginalName, m.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create word media: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	m.ID = int(id)
+	return int(id), nil
+}
+
+// GetWordMedia retrieves the files attached to a word, oldest first.
+func GetWordMedia(db *sql.DB, wordID int) ([]models.WordMedia, error) {
+	rows, err := db.Query("SELECT "+wordMediaColumns+" FROM word_media WHERE word_id = ? ORDER BY id", wordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query word media: %w", err)
+	}
+	defer rows.Close()
+
+	media := []models.WordMedia{}
+	for rows.Next() {
+		m, err := scanWordMedia(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan word media row: %w", err)
+		}
+		media = append(media, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word media rows: %w", err)
+	}
+
+	return media, nil
+}
+
+// GetWordMediaByID retrieves one attached file. It returns nil, nil when it does not exist.
+func GetWordMediaByID(db *sql.DB, id int) (*models.WordMedia, error) {
+	m, err := scanWordMedia(db.QueryRow("SELECT "+wordMediaColumns+" FROM word_media WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Media not found
+		}
+		return nil, fmt.Errorf("failed to scan word media row: %w", err)
+	}
+	return m, nil
+}
+
+// DeleteWordMediaByID removes one attached file's record.
+func DeleteWordMediaByID(db *sql.DB, id int) error {
+	if _, err := db.Exec("DELETE FROM word_media WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete word media: %w", err)
+	}
+	return nil
+}
+
+// DeleteWordMedia removes the records of every file attached to a word and
+// returns their content hashes so the files can be cleaned up.
+func DeleteWordMedia(db *sql.DB, wordID int) ([]string, error) {
+	media, err := GetWordMedia(db, wordID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec("DELETE FROM word_media WHERE word_id = ?", wordID); err != nil {
+		return nil, fmt.Errorf("failed to delete word media: %w", err)
+	}
+
+	hashes := make([]string, len(media))
+	for i, m := range media {
+		hashes[i] = m.ContentHash
+	}
+	return hashes, nil
+}
+
+// MediaHashInUse reports whether any word media record still refers to the content hash.
+func MediaHashInUse(db *sql.DB, hash string) (bool, error) {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM word_media WHERE content_hash = ?", hash).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to count word media: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetWordIDsWithoutMedia retrieves the IDs of words that have no media of the given kind.
+func GetWordIDsWithoutMedia(db *sql.DB, kind string) ([]int, error) {
+	rows, err := db.Query(`
+        SELECT w.id
+        FROM words w
+        WHERE NOT EXISTS (SELECT 1 FROM word_media m WHERE m.word_id = w.id AND m.kind = ?)
+        ORDER BY w.id`, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query words without media: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan word id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word rows: %w", err)
+	}
+
+	return ids, nil
+}
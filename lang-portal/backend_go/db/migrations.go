@@ -0,0 +1,66 @@
+package db
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationFiles holds the migrations this build expects to have been applied.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// PendingMigrations returns the names of the migrations that have not been
+// recorded in the migrations table by "mage db:migrate", in order.
+func PendingMigrations(db *sql.DB) ([]string, error) {
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	sort.Strings(names)
+
+	applied := map[int]bool{}
+	var exists bool
+	err = db.QueryRow("SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'migrations')").Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check migrations table: %w", err)
+	}
+	if exists {
+		rows, err := db.Query("SELECT id FROM migrations")
+		if err != nil {
+			return nil, fmt.Errorf("failed to query migrations: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				return nil, fmt.Errorf("failed to scan migration id: %w", err)
+			}
+			applied[id] = true
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating migration rows: %w", err)
+		}
+	}
+
+	pending := []string{}
+	for _, name := range names {
+		name = strings.TrimSuffix(strings.TrimPrefix(name, "migrations/"), ".sql")
+		// Numbered the same way the migrate target records them
+		id, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if err != nil {
+			continue
+		}
+		if !applied[id] {
+			pending = append(pending, name)
+		}
+	}
+
+	return pending, nil
+}
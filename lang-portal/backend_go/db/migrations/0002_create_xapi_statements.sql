@@ -0,0 +1,7 @@
+-- Create xapi_statements table linking xAPI statement IDs to the reviews they recorded
+CREATE TABLE xapi_statements (
+    id TEXT PRIMARY KEY,
+    word_review_item_id INTEGER NOT NULL UNIQUE,
+    actor TEXT NOT NULL,
+    FOREIGN KEY (word_review_item_id) REFERENCES word_review_items(id)
+);
@@ -0,0 +1,2 @@
+-- Record how long the learner took to answer, in milliseconds
+ALTER TABLE word_review_items ADD COLUMN response_ms INTEGER NULL;
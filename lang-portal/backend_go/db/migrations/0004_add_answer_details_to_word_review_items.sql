@@ -0,0 +1,4 @@
+-- Capture what the learner answered, the translation direction and the activity type
+ALTER TABLE word_review_items ADD COLUMN given_answer TEXT NULL;
+ALTER TABLE word_review_items ADD COLUMN direction TEXT NULL CHECK (direction IN ('en_pt', 'pt_en'));
+ALTER TABLE word_review_items ADD COLUMN activity_type TEXT NULL;
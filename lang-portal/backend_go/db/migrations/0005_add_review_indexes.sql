@@ -0,0 +1,6 @@
+-- Indexes backing the stats endpoints' date-range and per-word aggregations
+CREATE INDEX idx_word_review_items_created_at ON word_review_items(created_at);
+CREATE INDEX idx_word_review_items_word_id_created_at ON word_review_items(word_id, created_at);
+CREATE INDEX idx_word_review_items_study_session_id ON word_review_items(study_session_id);
+CREATE INDEX idx_words_groups_group_id_word_id ON words_groups(group_id, word_id);
+CREATE INDEX idx_study_sessions_group_id ON study_sessions(group_id);
@@ -0,0 +1,2 @@
+-- Name study activities so session listings can show the activity used
+ALTER TABLE study_activities ADD COLUMN name TEXT NULL;
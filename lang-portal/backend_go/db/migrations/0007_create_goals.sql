@@ -0,0 +1,22 @@
+-- Create goals table holding the learner's daily review target
+CREATE TABLE goals (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    daily_review_target INTEGER NOT NULL,
+    time_zone TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+INSERT INTO goals (id, daily_review_target, time_zone, created_at, updated_at)
+VALUES (1, 20, 'UTC', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+-- Create goal_events table recording days the daily target was missed
+CREATE TABLE goal_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_type TEXT NOT NULL,
+    day TEXT NOT NULL,
+    review_count INTEGER NOT NULL,
+    daily_review_target INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE (event_type, day)
+);
@@ -0,0 +1,49 @@
+-- Create users table; every existing session and review belongs to the default learner
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL UNIQUE,
+    display_name TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'learner',
+    created_at DATETIME NOT NULL
+);
+
+INSERT INTO users (id, username, display_name, role, created_at)
+VALUES (1, 'learner', 'Learner', 'learner', CURRENT_TIMESTAMP);
+
+ALTER TABLE study_sessions ADD COLUMN user_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE word_review_items ADD COLUMN user_id INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX idx_study_sessions_user_id_created_at ON study_sessions(user_id, created_at);
+CREATE INDEX idx_word_review_items_user_id_created_at ON word_review_items(user_id, created_at);
+
+-- Daily goals are kept per user
+CREATE TABLE user_goals (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id),
+    daily_review_target INTEGER NOT NULL,
+    time_zone TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+INSERT INTO user_goals (user_id, daily_review_target, time_zone, created_at, updated_at)
+SELECT 1, daily_review_target, time_zone, created_at, updated_at FROM goals WHERE id = 1;
+
+DROP TABLE goals;
+ALTER TABLE user_goals RENAME TO goals;
+
+CREATE TABLE user_goal_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    event_type TEXT NOT NULL,
+    day TEXT NOT NULL,
+    review_count INTEGER NOT NULL,
+    daily_review_target INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE (user_id, event_type, day)
+);
+
+INSERT INTO user_goal_events (id, user_id, event_type, day, review_count, daily_review_target, created_at)
+SELECT id, 1, event_type, day, review_count, daily_review_target, created_at FROM goal_events;
+
+DROP TABLE goal_events;
+ALTER TABLE user_goal_events RENAME TO goal_events;
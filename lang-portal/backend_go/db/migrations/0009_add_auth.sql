@@ -0,0 +1,17 @@
+-- Store bcrypt password hashes; users without one cannot log in with a password
+ALTER TABLE users ADD COLUMN password_hash TEXT NULL;
+
+-- Create auth_tokens table holding browser login sessions and personal API tokens.
+-- Only a SHA-256 hash of each token is stored.
+CREATE TABLE auth_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    kind TEXT NOT NULL CHECK (kind IN ('session', 'api')),
+    name TEXT NOT NULL DEFAULT '',
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NULL,
+    last_used_at DATETIME NULL
+);
+
+CREATE INDEX idx_auth_tokens_user_id ON auth_tokens(user_id);
@@ -0,0 +1,30 @@
+-- Create classes owned by a teacher and the learners enrolled in them
+CREATE TABLE classes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    teacher_id INTEGER NOT NULL REFERENCES users(id),
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_classes_teacher_id ON classes(teacher_id);
+
+CREATE TABLE class_members (
+    class_id INTEGER NOT NULL REFERENCES classes(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    joined_at DATETIME NOT NULL,
+    PRIMARY KEY (class_id, user_id)
+);
+
+-- Assignments ask a class to study a group before the due date until each learner
+-- has finished target_sessions sessions at target_accuracy percent or better
+CREATE TABLE assignments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    class_id INTEGER NOT NULL REFERENCES classes(id),
+    group_id INTEGER NOT NULL REFERENCES groups(id),
+    due_at DATETIME NOT NULL,
+    target_accuracy REAL NOT NULL DEFAULT 0,
+    target_sessions INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_assignments_class_id ON assignments(class_id);
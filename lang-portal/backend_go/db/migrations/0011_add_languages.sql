@@ -0,0 +1,19 @@
+-- Create languages table; language codes are ISO 639-1
+CREATE TABLE languages (
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+INSERT INTO languages (code, name) VALUES
+    ('en', 'English'),
+    ('pt', 'Portuguese'),
+    ('es', 'Spanish'),
+    ('ja', 'Japanese');
+
+-- Words become language pairs. Existing words are English to Portuguese.
+ALTER TABLE words RENAME COLUMN english TO source_text;
+ALTER TABLE words RENAME COLUMN portuguese TO target_text;
+ALTER TABLE words ADD COLUMN source_language TEXT NOT NULL DEFAULT 'en';
+ALTER TABLE words ADD COLUMN target_language TEXT NOT NULL DEFAULT 'pt';
+
+CREATE INDEX idx_words_language_pair ON words(source_language, target_language);
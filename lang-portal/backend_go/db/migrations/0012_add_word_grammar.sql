@@ -0,0 +1,4 @@
+-- Store structured grammar (gender, plural, conjugations, examples) as JSON
+ALTER TABLE words ADD COLUMN grammar TEXT NULL CHECK (grammar IS NULL OR json_valid(grammar));
+
+CREATE INDEX idx_words_gender ON words(json_extract(grammar, '$.gender'));
@@ -0,0 +1,15 @@
+-- Create word_media table for per-word audio and images. Files live on disk under
+-- their SHA-256 content hash, so identical uploads share one file.
+CREATE TABLE word_media (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    word_id INTEGER NOT NULL REFERENCES words(id),
+    kind TEXT NOT NULL CHECK (kind IN ('audio', 'image')),
+    mime_type TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    content_hash TEXT NOT NULL,
+    original_name TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_word_media_word_id ON word_media(word_id);
+CREATE INDEX idx_word_media_content_hash ON word_media(content_hash);
@@ -0,0 +1,10 @@
+-- Create sentences table for example sentences that show a word in context
+CREATE TABLE sentences (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    word_id INTEGER NOT NULL REFERENCES words(id),
+    text TEXT NOT NULL,
+    translation TEXT NOT NULL DEFAULT '',
+    origin TEXT NOT NULL CHECK (origin IN ('manual', 'import', 'llm')),
+    created_at DATETIME NOT NULL,
+    UNIQUE (word_id, text)
+);
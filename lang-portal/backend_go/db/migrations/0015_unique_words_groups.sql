@@ -0,0 +1,5 @@
+-- Remove repeated word/group memberships, keeping the oldest row, and prevent new ones
+DELETE FROM words_groups
+WHERE id NOT IN (SELECT MIN(id) FROM words_groups GROUP BY word_id, group_id);
+
+CREATE UNIQUE INDEX idx_words_groups_word_id_group_id ON words_groups(word_id, group_id);
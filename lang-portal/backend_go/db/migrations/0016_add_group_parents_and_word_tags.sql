@@ -0,0 +1,13 @@
+-- Let groups nest under a parent group
+ALTER TABLE groups ADD COLUMN parent_id INTEGER NULL REFERENCES groups(id);
+
+CREATE INDEX idx_groups_parent_id ON groups(parent_id);
+
+-- Free-form tags on words
+CREATE TABLE word_tags (
+    word_id INTEGER NOT NULL REFERENCES words(id),
+    tag TEXT NOT NULL,
+    PRIMARY KEY (word_id, tag)
+);
+
+CREATE INDEX idx_word_tags_tag ON word_tags(tag);
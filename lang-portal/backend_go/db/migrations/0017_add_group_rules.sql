@@ -0,0 +1,2 @@
+-- Smart groups store the rule selecting their words as JSON instead of listing them in words_groups
+ALTER TABLE groups ADD COLUMN rule TEXT NULL;
@@ -0,0 +1,13 @@
+-- Track whether a study session is still running, was finished by the learner, or was
+-- closed by the server after going idle
+ALTER TABLE study_sessions ADD COLUMN status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'completed', 'abandoned'));
+ALTER TABLE study_sessions ADD COLUMN ended_at DATETIME NULL;
+
+CREATE INDEX idx_study_sessions_status ON study_sessions(status);
+
+-- Sessions from before this migration could not be finished; treat them as completed
+-- at their last review
+UPDATE study_sessions
+SET status = 'completed',
+    ended_at = COALESCE((SELECT MAX(created_at) FROM word_review_items WHERE study_session_id = study_sessions.id), created_at);
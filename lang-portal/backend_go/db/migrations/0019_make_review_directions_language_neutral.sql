@@ -0,0 +1,9 @@
+-- Review directions name the side of the word's language pair that was asked,
+-- so they hold for any pair: existing English to Portuguese reviews go forward
+ALTER TABLE word_review_items ADD COLUMN review_direction TEXT NULL CHECK (review_direction IN ('forward', 'reverse'));
+
+UPDATE word_review_items
+SET review_direction = CASE direction WHEN 'en_pt' THEN 'forward' WHEN 'pt_en' THEN 'reverse' END;
+
+ALTER TABLE word_review_items DROP COLUMN direction;
+ALTER TABLE word_review_items RENAME COLUMN review_direction TO direction;
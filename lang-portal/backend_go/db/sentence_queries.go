@@ -0,0 +1,119 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend_go/models"
+)
+
+const sentenceColumns = "s.id, s.word_id, s.text, s.translation, s.origin, s.created_at"
+
+// scanSentence scans a row selected with sentenceColumns.
+func scanSentence(row interface{ Scan(...interface{}) error }) (*models.Sentence, error) {
+	var s models.Sentence
+	if err := row.Scan(&s.ID, &s.WordID, &s.Text, &s.Translation, &s.Origin, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// querySentences runs a query selecting sentenceColumns.
+func querySentences(db *sql.DB, query string, args ...interface{}) ([]models.Sentence, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sentences: %w", err)
+	}
+	defer rows.Close()
+
+	sentences := []models.Sentence{}
+	for rows.Next() {
+		s, err := scanSentence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sentence row: %w", err)
+		}
+		sentences = append(sentences, *s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sentence rows: %w", err)
+	}
+
+	return sentences, nil
+}
+
+// CreateSentence stores an example sentence for a word. It returns false, and
+// leaves the sentence's ID unset, when the word already has the same sentence.
+func CreateSentence(db *sql.DB, s *models.Sentence) (bool, error) {
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := db.Exec(`INSERT OR IGNORE INTO sentences (word_id, text, translation, origin, created_at)
+		VALUES (?, ?, ?, ?, ?)`, s.WordID, s.Text, s.Translation, s.Origin, s.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create sentence: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	s.ID = int(id)
+	return true, nil
+}
+
+// GetWordSentences retrieves a word's example sentences, oldest first.
+func GetWordSentences(db *sql.DB, wordID int) ([]models.Sentence, error) {
+	return querySentences(db, "SELECT "+sentenceColumns+" FROM sentences s WHERE s.word_id = ? ORDER BY s.id", wordID)
+}
+
+// GetGroupSentences retrieves the example sentences of every word in a group,
+// evaluating smart groups for scope.
+func GetGroupSentences(db *sql.DB, groupID int, scope GroupScope) ([]models.Sentence, error) {
+	members, args, err := groupMembers(db, groupID, scope)
+	if err != nil {
+		return nil, err
+	}
+	return querySentences(db, `
+        SELECT `+sentenceColumns+`
+        FROM sentences s
+        WHERE s.word_id IN (`+members+`)
+        ORDER BY s.id`, args...)
+}
+
+// GetSentenceByID retrieves a sentence. It returns nil, nil when it does not exist.
+func GetSentenceByID(db *sql.DB, id int) (*models.Sentence, error) {
+	s, err := scanSentence(db.QueryRow("SELECT "+sentenceColumns+" FROM sentences s WHERE s.id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Sentence not found
+		}
+		return nil, fmt.Errorf("failed to scan sentence row: %w", err)
+	}
+	return s, nil
+}
+
+// DeleteSentence deletes a sentence.
+func DeleteSentence(db *sql.DB, id int) error {
+	if _, err := db.Exec("DELETE FROM sentences WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete sentence: %w", err)
+	}
+	return nil
+}
+
+// DeleteWordSentences deletes every example sentence of a word.
+func DeleteWordSentences(db *sql.DB, wordID int) error {
+	if _, err := db.Exec("DELETE FROM sentences WHERE word_id = ?", wordID); err != nil {
+		return fmt.Errorf("failed to delete word sentences: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend_go/models"
+)
+
+// GetSessionAnswers retrieves the answers recorded in a study session with their
+// words, in the order they were given.
+func GetSessionAnswers(db *sql.DB, sessionID int) ([]models.SessionAnswer, error) {
+	rows, err := db.Query(`
+        SELECT
+            wri.id,
+            w.id,
+            w.source_text,
+            w.target_text,
+            w.parts,
+            wri.is_correct,
+            COALESCE(wri.given_answer, ''),
+            COALESCE(wri.direction, ''),
+            COALESCE(wri.activity_type, ''),
+            wri.created_at,
+            wri.response_ms
+        FROM word_review_items wri
+        JOIN words w ON wri.word_id = w.id
+        WHERE wri.study_session_id = ?
+        ORDER BY wri.created_at, wri.id`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session answers: %w", err)
+	}
+	defer rows.Close()
+
+	answers := []models.SessionAnswer{}
+	for rows.Next() {
+		var answer models.SessionAnswer
+		if err := rows.Scan(&answer.ReviewID, &answer.WordID, &answer.SourceText, &answer.TargetText, &answer.Parts,
+			&answer.Correct, &answer.GivenAnswer, &answer.Direction, &answer.ActivityType, &answer.AnsweredAt, &answer.ResponseMS); err != nil {
+			return nil, fmt.Errorf("failed to scan session answer: %w", err)
+		}
+		answers = append(answers, answer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating session answer rows: %w", err)
+	}
+
+	return answers, nil
+}
+
+// GetPriorOutcomes returns, for each word answered in a study session, whether
+// the learner's latest answer to it in an earlier session was correct. Words
+// never answered before the session are absent.
+func GetPriorOutcomes(db *sql.DB, userID, sessionID int) (map[int]bool, error) {
+	rows, err := db.Query(`
+        WITH prior AS (
+            SELECT
+                word_id,
+                is_correct,
+                ROW_NUMBER() OVER (PARTITION BY word_id ORDER BY created_at DESC, id DESC) as recency
+            FROM word_review_items
+            WHERE user_id = ?
+              AND study_session_id != ?
+              AND created_at < (SELECT MIN(created_at) FROM word_review_items WHERE study_session_id = ?)
+              AND word_id IN (SELECT word_id FROM word_review_items WHERE study_session_id = ?)
+        )
+        SELECT word_id, is_correct FROM prior WHERE recency = 1`, userID, sessionID, sessionID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prior outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	outcomes := make(map[int]bool)
+	for rows.Next() {
+		var wordID int
+		var correct bool
+		if err := rows.Scan(&wordID, &correct); err != nil {
+			return nil, fmt.Errorf("failed to scan prior outcome: %w", err)
+		}
+		outcomes[wordID] = correct
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating prior outcome rows: %w", err)
+	}
+
+	return outcomes, nil
+}
+
+// GetLastReviewTimes returns when the user last reviewed each word they have reviewed.
+func GetLastReviewTimes(db *sql.DB, userID int) (map[int]time.Time, error) {
+	rows, err := db.Query("SELECT word_id, MAX(created_at) FROM word_review_items WHERE user_id = ? GROUP BY word_id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last review times: %w", err)
+	}
+	defer rows.Close()
+
+	times := make(map[int]time.Time)
+	for rows.Next() {
+		var wordID int
+		var last string
+		if err := rows.Scan(&wordID, &last); err != nil {
+			return nil, fmt.Errorf("failed to scan last review time: %w", err)
+		}
+		at, err := parseTimestamp(last)
+		if err != nil {
+			return nil, err
+		}
+		times[wordID] = at
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating last review time rows: %w", err)
+	}
+
+	return times, nil
+}
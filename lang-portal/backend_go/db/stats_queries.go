@@ -0,0 +1,468 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"backend_go/models"
+)
+
+// wordLatencyStatsSelect aggregates review counts and response times per word.
+const wordLatencyStatsSelect = `
+        SELECT
+            w.id,
+            w.source_text,
+            w.target_text,
+            COUNT(wri.id) as review_count,
+            COALESCE(SUM(CASE WHEN wri.is_correct = 1 THEN 1 ELSE 0 END), 0) as correct_count,
+            ROUND(AVG(wri.response_ms), 1) as avg_response_ms,
+            ROUND(AVG(CASE WHEN wri.is_correct = 1 THEN wri.response_ms END), 1) as avg_correct_response_ms,
+            ROUND(AVG(CASE WHEN wri.is_correct = 0 THEN wri.response_ms END), 1) as avg_incorrect_response_ms
+        FROM words w
+        JOIN word_review_items wri ON w.id = wri.word_id AND wri.user_id = ?`
+
+// GetWordLatencyStats retrieves per-word answer latency for the words a user reviewed
+// with pagination, slowest words first.
+func GetWordLatencyStats(db *sql.DB, userID, page, limit int) ([]models.WordLatencyStats, int, error) {
+	offset := (page - 1) * limit
+
+	var totalItems int
+	err := db.QueryRow(`SELECT COUNT(DISTINCT word_id) FROM word_review_items WHERE user_id = ?`, userID).Scan(&totalItems)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting reviewed words: %w", err)
+	}
+
+	query := wordLatencyStatsSelect + `
+        GROUP BY w.id, w.source_text, w.target_text
+        ORDER BY avg_response_ms IS NULL, avg_response_ms DESC, w.id
+        LIMIT ? OFFSET ?`
+
+	rows, err := db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error querying word latency stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.WordLatencyStats
+	for rows.Next() {
+		var stat models.WordLatencyStats
+		if err := scanWordLatencyStats(rows, &stat); err != nil {
+			return nil, 0, fmt.Errorf("error scanning word latency row: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, totalItems, rows.Err()
+}
+
+// GetWordAnswerStats retrieves latency and the most common wrong answers for a word
+// from a user's reviews. It returns nil if the user has never reviewed the word.
+func GetWordAnswerStats(db *sql.DB, userID, wordID, wrongAnswerLimit int) (*models.WordAnswerStats, error) {
+	row := db.QueryRow(wordLatencyStatsSelect+`
+        WHERE w.id = ?
+        GROUP BY w.id, w.source_text, w.target_text`, userID, wordID)
+
+	var stats models.WordAnswerStats
+	if err := scanWordLatencyStats(row, &stats.WordLatencyStats); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan word answer stats: %w", err)
+	}
+
+	// Answers are compared case-insensitively so "Ola" and "ola" count together
+	rows, err := db.Query(`
+        SELECT LOWER(TRIM(given_answer)) as answer, COUNT(*) as answer_count
+        FROM word_review_items
+        WHERE user_id = ? AND word_id = ? AND is_correct = 0 AND TRIM(COALESCE(given_answer, '')) <> ''
+        GROUP BY answer
+        ORDER BY answer_count DESC, answer
+        LIMIT ?`, userID, wordID, wrongAnswerLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wrong answers: %w", err)
+	}
+	defer rows.Close()
+
+	stats.CommonWrongAnswers = []models.WrongAnswerCount{}
+	for rows.Next() {
+		var answer models.WrongAnswerCount
+		if err := rows.Scan(&answer.Answer, &answer.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan wrong answer row: %w", err)
+		}
+		stats.CommonWrongAnswers = append(stats.CommonWrongAnswers, answer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating wrong answer rows: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWordLatencyStats(row rowScanner, stat *models.WordLatencyStats) error {
+	return row.Scan(
+		&stat.WordID,
+		&stat.English,
+		&stat.Portuguese,
+		&stat.ReviewCount,
+		&stat.CorrectCount,
+		&stat.AvgResponseMS,
+		&stat.AvgCorrectResponseMS,
+		&stat.AvgIncorrectResponseMS,
+	)
+}
+
+// DateRange limits stats to reviews created between From and To, both inclusive
+// dates formatted as YYYY-MM-DD. Empty bounds are open.
+type DateRange struct {
+	From string
+	To   string
+}
+
+// where returns SQL conditions on column for the range, written as plain
+// comparisons so the created_at indexes can be used.
+func (r DateRange) where(column string) (string, []interface{}) {
+	conditions := "1 = 1"
+	args := []interface{}{}
+
+	if r.From != "" {
+		conditions += " AND " + column + " >= ?"
+		args = append(args, r.From)
+	}
+	if r.To != "" {
+		// Timestamps on the last day sort after the bare date, so compare against the next day
+		conditions += " AND " + column + " < date(?, '+1 day')"
+		args = append(args, r.To)
+	}
+
+	return conditions, args
+}
+
+// reviewFilter returns SQL conditions selecting a user's reviews in the range.
+// alias is the word_review_items table alias, or empty when the table is not aliased.
+func reviewFilter(userID int, dateRange DateRange, alias string) (string, []interface{}) {
+	if alias != "" {
+		alias += "."
+	}
+	conditions, args := dateRange.where(alias + "created_at")
+	return alias + "user_id = ? AND " + conditions, append([]interface{}{userID}, args...)
+}
+
+// GetAccuracyTimeSeries retrieves a user's review accuracy grouped by day or by week.
+// Weekly periods are labelled with the date of the Monday they start on.
+func GetAccuracyTimeSeries(db *sql.DB, userID int, interval string, dateRange DateRange) ([]models.AccuracyPoint, error) {
+	period := "date(created_at)"
+	if interval == "week" {
+		period = "date(created_at, 'weekday 0', '-6 days')"
+	}
+
+	conditions, args := reviewFilter(userID, dateRange, "")
+	query := `
+        SELECT
+            ` + period + ` as period,
+            COUNT(*) as review_count,
+            SUM(CASE WHEN is_correct = 1 THEN 1 ELSE 0 END) as correct_count,
+            SUM(CASE WHEN is_correct = 0 THEN 1 ELSE 0 END) as incorrect_count,
+            ROUND(AVG(CASE WHEN is_correct = 1 THEN 100.0 ELSE 0.0 END), 1) as accuracy
+        FROM word_review_items
+        WHERE ` + conditions + `
+        GROUP BY period
+        ORDER BY period`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying accuracy time series: %w", err)
+	}
+	defer rows.Close()
+
+	points := []models.AccuracyPoint{}
+	for rows.Next() {
+		var point models.AccuracyPoint
+		if err := rows.Scan(&point.Period, &point.ReviewCount, &point.CorrectCount, &point.IncorrectCount, &point.Accuracy); err != nil {
+			return nil, fmt.Errorf("error scanning accuracy row: %w", err)
+		}
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
+
+// GetMostMissedWords retrieves the words a user answered incorrectly most often,
+// ignoring words with fewer than minReviews reviews in the range.
+func GetMostMissedWords(db *sql.DB, userID int, dateRange DateRange, minReviews, limit int) ([]models.MissedWord, error) {
+	conditions, args := reviewFilter(userID, dateRange, "wri")
+	query := `
+        SELECT
+            w.id,
+            w.source_text,
+            w.target_text,
+            COUNT(*) as review_count,
+            SUM(CASE WHEN wri.is_correct = 0 THEN 1 ELSE 0 END) as incorrect_count,
+            ROUND(AVG(CASE WHEN wri.is_correct = 0 THEN 100.0 ELSE 0.0 END), 1) as error_rate
+        FROM word_review_items wri
+        JOIN words w ON w.id = wri.word_id
+        WHERE ` + conditions + `
+        GROUP BY w.id, w.source_text, w.target_text
+        HAVING review_count >= ? AND incorrect_count > 0
+        ORDER BY incorrect_count DESC, error_rate DESC, w.id
+        LIMIT ?`
+	args = append(args, minReviews, limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying most missed words: %w", err)
+	}
+	defer rows.Close()
+
+	words := []models.MissedWord{}
+	for rows.Next() {
+		var word models.MissedWord
+		if err := rows.Scan(&word.WordID, &word.English, &word.Portuguese, &word.ReviewCount, &word.IncorrectCount, &word.ErrorRate); err != nil {
+			return nil, fmt.Errorf("error scanning missed word row: %w", err)
+		}
+		words = append(words, word)
+	}
+
+	return words, rows.Err()
+}
+
+// GetGroupMastery retrieves, for every group, how many of its words, including
+// those of its subgroups or matching its rule, have the user's latest streak
+// reviews in the range all answered correctly.
+func GetGroupMastery(db *sql.DB, userID int, dateRange DateRange, streak int) ([]models.GroupMastery, error) {
+	groups, err := GetAllGroups(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Smart groups add their matching words to the static memberships
+	scope := GroupScope{UserID: userID, AsOf: time.Now().UTC()}
+	smartMembers := ""
+	var smartArgs []interface{}
+	for _, group := range groups {
+		if group.Rule == nil {
+			continue
+		}
+		ruleConditions, ruleArgs := ruleWhere(group.Rule, scope)
+		smartMembers += `
+            UNION ALL
+            SELECT ?, w.id FROM words w WHERE ` + ruleConditions
+		smartArgs = append(append(smartArgs, group.ID), ruleArgs...)
+	}
+
+	conditions, args := reviewFilter(userID, dateRange, "")
+	query := `
+        WITH RECURSIVE ranked AS (
+            SELECT
+                word_id,
+                is_correct,
+                ROW_NUMBER() OVER (PARTITION BY word_id ORDER BY created_at DESC, id DESC) as recency
+            FROM word_review_items
+            WHERE ` + conditions + `
+        ),
+        mastered AS (
+            SELECT word_id
+            FROM ranked
+            WHERE recency <= ?
+            GROUP BY word_id
+            HAVING COUNT(*) = ? AND SUM(CASE WHEN is_correct = 1 THEN 1 ELSE 0 END) = ?
+        ),
+        tree(ancestor_id, group_id) AS (
+            SELECT id, id FROM groups
+            UNION
+            SELECT t.ancestor_id, g.id FROM groups g JOIN tree t ON g.parent_id = t.group_id
+        ),
+        members(group_id, word_id) AS (
+            SELECT t.ancestor_id, wg.word_id
+            FROM tree t
+            JOIN groups g ON g.id = t.ancestor_id AND g.rule IS NULL
+            JOIN words_groups wg ON wg.group_id = t.group_id` + smartMembers + `
+        )
+        SELECT
+            g.id,
+            g.name,
+            COUNT(DISTINCT mb.word_id) as total_words,
+            COUNT(DISTINCT m.word_id) as mastered_words
+        FROM groups g
+        LEFT JOIN members mb ON mb.group_id = g.id
+        LEFT JOIN mastered m ON m.word_id = mb.word_id
+        GROUP BY g.id, g.name
+        ORDER BY g.id`
+	args = append(append(args, streak, streak, streak), smartArgs...)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying group mastery: %w", err)
+	}
+	defer rows.Close()
+
+	mastery := []models.GroupMastery{}
+	for rows.Next() {
+		var group models.GroupMastery
+		if err := rows.Scan(&group.GroupID, &group.Name, &group.TotalWords, &group.MasteredWords); err != nil {
+			return nil, fmt.Errorf("error scanning group mastery row: %w", err)
+		}
+		if group.TotalWords > 0 {
+			group.MasteryPercentage = math.Round(float64(group.MasteredWords)*1000/float64(group.TotalWords)) / 10
+		}
+		mastery = append(mastery, group)
+	}
+
+	return mastery, rows.Err()
+}
+
+// GetStudyTimePerDay retrieves the time a user spent studying per day. A session's
+// time on a day is the span between its first and last review on that day.
+func GetStudyTimePerDay(db *sql.DB, userID int, dateRange DateRange) ([]models.StudyTimeDay, error) {
+	conditions, args := reviewFilter(userID, dateRange, "")
+	query := `
+        WITH session_days AS (
+            SELECT
+                date(created_at) as day,
+                study_session_id,
+                COUNT(*) as review_count,
+                (julianday(MAX(created_at)) - julianday(MIN(created_at))) * 24 * 60 as minutes
+            FROM word_review_items
+            WHERE ` + conditions + `
+            GROUP BY day, study_session_id
+        )
+        SELECT
+            day,
+            COUNT(*) as session_count,
+            SUM(review_count) as review_count,
+            ROUND(SUM(minutes), 1) as study_minutes
+        FROM session_days
+        GROUP BY day
+        ORDER BY day`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying study time: %w", err)
+	}
+	defer rows.Close()
+
+	days := []models.StudyTimeDay{}
+	for rows.Next() {
+		var day models.StudyTimeDay
+		if err := rows.Scan(&day.Date, &day.SessionCount, &day.ReviewCount, &day.StudyMinutes); err != nil {
+			return nil, fmt.Errorf("error scanning study time row: %w", err)
+		}
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
+
+// GetQuickStats retrieves a user's review and session totals for the dashboard. Streaks are
+// left at zero; they depend on the learner's time zone and are computed by the goals package.
+func GetQuickStats(db *sql.DB, userID int) (*models.QuickStats, error) {
+	var stats models.QuickStats
+	var lastReview sql.NullString
+
+	err := db.QueryRow(`
+        SELECT
+            COALESCE(ROUND(AVG(CASE WHEN is_correct = 1 THEN 100.0 ELSE 0.0 END), 1), 0),
+            COUNT(DISTINCT word_id),
+            COUNT(DISTINCT CASE WHEN is_correct = 1 THEN word_id END),
+            MAX(created_at)
+        FROM word_review_items
+        WHERE user_id = ?`, userID).Scan(&stats.SuccessRate, &stats.TotalWordsStudied, &stats.TotalWordsCorrect, &lastReview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan review totals: %w", err)
+	}
+
+	err = db.QueryRow(`
+        SELECT
+            COUNT(*),
+            COUNT(DISTINCT CASE WHEN julianday(created_at) >= julianday('now', '-30 days') THEN group_id END)
+        FROM study_sessions
+        WHERE user_id = ?`, userID).Scan(&stats.TotalStudySessions, &stats.TotalActiveGroups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan study session totals: %w", err)
+	}
+
+	if lastReview.Valid {
+		// MAX() returns the raw stored text, so parse it as the driver would
+		lastStudyDate, err := parseTimestamp(lastReview.String)
+		if err != nil {
+			return nil, err
+		}
+		stats.LastStudyDate = &lastStudyDate
+	}
+
+	return &stats, nil
+}
+
+// GetLearnerProgress retrieves review and session totals in the range for every learner.
+func GetLearnerProgress(db *sql.DB, dateRange DateRange) ([]models.LearnerProgress, error) {
+	sessionConditions, sessionArgs := dateRange.where("ss.created_at")
+	reviewConditions, reviewArgs := dateRange.where("wri.created_at")
+	query := `
+        SELECT
+            u.id,
+            u.username,
+            u.display_name,
+            (SELECT COUNT(*) FROM study_sessions ss WHERE ss.user_id = u.id AND ` + sessionConditions + `) as study_sessions,
+            COUNT(wri.id) as review_count,
+            COALESCE(SUM(CASE WHEN wri.is_correct = 1 THEN 1 ELSE 0 END), 0) as correct_count,
+            COUNT(DISTINCT wri.word_id) as words_studied,
+            COALESCE(ROUND(AVG(CASE WHEN wri.is_correct = 1 THEN 100.0 WHEN wri.is_correct = 0 THEN 0.0 END), 1), 0) as accuracy,
+            MAX(wri.created_at) as last_review
+        FROM users u
+        LEFT JOIN word_review_items wri ON wri.user_id = u.id AND ` + reviewConditions + `
+        WHERE u.role = ?
+        GROUP BY u.id, u.username, u.display_name
+        ORDER BY u.id`
+	args := append(append(sessionArgs, reviewArgs...), models.RoleLearner)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying learner progress: %w", err)
+	}
+	defer rows.Close()
+
+	learners := []models.LearnerProgress{}
+	for rows.Next() {
+		var learner models.LearnerProgress
+		var lastReview sql.NullString
+		if err := rows.Scan(&learner.UserID, &learner.Username, &learner.DisplayName, &learner.StudySessions,
+			&learner.ReviewCount, &learner.CorrectCount, &learner.WordsStudied, &learner.Accuracy, &lastReview); err != nil {
+			return nil, fmt.Errorf("error scanning learner progress row: %w", err)
+		}
+		if lastReview.Valid {
+			lastStudyDate, err := parseTimestamp(lastReview.String)
+			if err != nil {
+				return nil, err
+			}
+			learner.LastStudyDate = &lastStudyDate
+		}
+		learners = append(learners, learner)
+	}
+
+	return learners, rows.Err()
+}
+
+// parseTimestamp parses a DATETIME value in one of the formats SQLite and the driver write.
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02T15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05Z",
+		"2006-01-02",
+	} {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", value)
+}
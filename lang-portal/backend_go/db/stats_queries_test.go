@@ -0,0 +1,97 @@
+package db
+
+import (
+	"testing"
+
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWordAnswerStatsIntegration(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO words (id, source_text, target_text, parts) VALUES (1, 'thank you', 'obrigado', 'phrase');
+		INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at, response_ms, given_answer, direction) VALUES
+			(1, 1, 1, CURRENT_TIMESTAMP, 1000, 'obrigado', 'forward'),
+			(1, 1, 0, CURRENT_TIMESTAMP, 3000, 'Obrigada', 'forward'),
+			(1, 1, 0, CURRENT_TIMESTAMP, 5000, 'obrigada ', 'forward'),
+			(1, 1, 0, CURRENT_TIMESTAMP, NULL, 'valeu', 'forward')`)
+	require.NoError(t, err)
+
+	stats, err := GetWordAnswerStats(db, models.DefaultUserID, 1, 5)
+	require.NoError(t, err)
+	require.NotNil(t, stats)
+
+	assert.Equal(t, 4, stats.ReviewCount)
+	assert.Equal(t, 1, stats.CorrectCount)
+	require.NotNil(t, stats.AvgResponseMS)
+	assert.Equal(t, 3000.0, *stats.AvgResponseMS)
+	assert.Equal(t, 1000.0, *stats.AvgCorrectResponseMS)
+	assert.Equal(t, 4000.0, *stats.AvgIncorrectResponseMS)
+
+	require.Len(t, stats.CommonWrongAnswers, 2)
+	assert.Equal(t, "obrigada", stats.CommonWrongAnswers[0].Answer)
+	assert.Equal(t, 2, stats.CommonWrongAnswers[0].Count)
+
+	missing, err := GetWordAnswerStats(db, models.DefaultUserID, 2, 5)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestLearningAnalyticsIntegration(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO groups (id, name, description) VALUES (1, 'Basics', '');
+		INSERT INTO words (id, source_text, target_text, parts) VALUES
+			(1, 'hello', 'olá', 'interjection'),
+			(2, 'goodbye', 'adeus', 'interjection');
+		INSERT INTO words_groups (word_id, group_id) VALUES (1, 1), (2, 1);
+		INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at) VALUES
+			(1, 1, 1, '2025-02-03 10:00:00'),
+			(1, 1, 1, '2025-02-03 10:05:00'),
+			(2, 1, 0, '2025-02-03 10:10:00'),
+			(1, 2, 1, '2025-02-10 09:00:00'),
+			(2, 2, 0, '2025-02-10 09:20:00'),
+			(2, 2, 1, '2025-02-10 09:30:00')`)
+	require.NoError(t, err)
+
+	daily, err := GetAccuracyTimeSeries(db, models.DefaultUserID, "day", DateRange{})
+	require.NoError(t, err)
+	require.Len(t, daily, 2)
+	assert.Equal(t, "2025-02-03", daily[0].Period)
+	assert.Equal(t, 66.7, daily[0].Accuracy)
+
+	weekly, err := GetAccuracyTimeSeries(db, models.DefaultUserID, "week", DateRange{From: "2025-02-09"})
+	require.NoError(t, err)
+	require.Len(t, weekly, 1)
+	assert.Equal(t, "2025-02-10", weekly[0].Period)
+	assert.Equal(t, 3, weekly[0].ReviewCount)
+
+	missed, err := GetMostMissedWords(db, models.DefaultUserID, DateRange{To: "2025-02-10"}, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, missed, 1)
+	assert.Equal(t, 2, missed[0].WordID)
+	assert.Equal(t, 2, missed[0].IncorrectCount)
+
+	mastery, err := GetGroupMastery(db, models.DefaultUserID, DateRange{}, 3)
+	require.NoError(t, err)
+	require.Len(t, mastery, 1)
+	assert.Equal(t, 2, mastery[0].TotalWords)
+	assert.Equal(t, 1, mastery[0].MasteredWords)
+	assert.Equal(t, 50.0, mastery[0].MasteryPercentage)
+
+	studyTime, err := GetStudyTimePerDay(db, models.DefaultUserID, DateRange{From: "2025-02-03", To: "2025-02-03"})
+	require.NoError(t, err)
+	require.Len(t, studyTime, 1)
+	assert.Equal(t, 10.0, studyTime[0].StudyMinutes)
+	assert.Equal(t, 3, studyTime[0].ReviewCount)
+}
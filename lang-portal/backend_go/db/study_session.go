@@ -26,7 +26,7 @@ func GetStudySessionWords(db *sql.DB, sessionID, page, limit int) ([]models.Word
 
 	// Then get paginated words
 	query := `
-        SELECT DISTINCT w.*
+        SELECT DISTINCT w.id, w.source_language, w.target_language, w.source_text, w.target_text, w.parts, w.grammar
         FROM words w
         JOIN word_review_items wri ON w.id = wri.word_id
         WHERE wri.study_session_id = ?
@@ -41,8 +41,7 @@ func GetStudySessionWords(db *sql.DB, sessionID, page, limit int) ([]models.Word
 
 	var words []models.Word
 	for rows.Next() {
-		var word models.Word
-		err := rows.Scan(&word.ID, &word.English, &word.Portuguese, &word.Parts)
+		word, err := scanWord(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("error scanning word row: %v", err)
 		}
@@ -70,7 +69,8 @@ func GetStudySessionWordsRaw(db *sql.DB, sessionID, page, limit int) ([]models.W
 
 	// Then get paginated word review items
 	query := `
-        SELECT id, word_id, study_session_id, correct, created_at
+        SELECT id, user_id, word_id, study_session_id, is_correct, created_at, response_ms,
+               COALESCE(given_answer, ''), COALESCE(direction, ''), COALESCE(activity_type, '')
         FROM word_review_items
         WHERE study_session_id = ?
         ORDER BY created_at
@@ -85,7 +85,8 @@ func GetStudySessionWordsRaw(db *sql.DB, sessionID, page, limit int) ([]models.W
 	var reviews []models.WordReviewItem
 	for rows.Next() {
 		var review models.WordReviewItem
-		err := rows.Scan(&review.ID, &review.WordID, &review.StudySessionID, &review.Correct, &review.CreatedAt)
+		err := rows.Scan(&review.ID, &review.UserID, &review.WordID, &review.StudySessionID, &review.Correct, &review.CreatedAt,
+			&review.ResponseMS, &review.GivenAnswer, &review.Direction, &review.ActivityType)
 		if err != nil {
 			return nil, 0, fmt.Errorf("error scanning word review item row: %v", err)
 		}
@@ -95,8 +96,8 @@ func GetStudySessionWordsRaw(db *sql.DB, sessionID, page, limit int) ([]models.W
 	return reviews, totalItems, nil
 }
 
-// GetWordGroupStudySessions retrieves study sessions for a word group with pagination
-func GetWordGroupStudySessions(db *sql.DB, groupID, page, limit int) ([]models.StudySession, int, error) {
+// GetWordGroupStudySessions retrieves a user's study sessions for a word group with pagination
+func GetWordGroupStudySessions(db *sql.DB, userID, groupID, page, limit int) ([]models.StudySession, int, error) {
 	offset := (page - 1) * limit
 
 	// First, get total count
@@ -106,24 +107,27 @@ func GetWordGroupStudySessions(db *sql.DB, groupID, page, limit int) ([]models.S
         FROM study_sessions ss
         JOIN word_review_items wri ON ss.id = wri.study_session_id
         JOIN words_groups wg ON wg.word_id = wri.word_id
-        WHERE wg.group_id = ?
+        WHERE wg.group_id = ? AND ss.user_id = ?
     `
-	err := db.QueryRow(countQuery, groupID).Scan(&totalItems)
+	err := db.QueryRow(countQuery, groupID, userID).Scan(&totalItems)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error counting group study sessions: %v", err)
 	}
 
 	// Then get paginated study sessions
 	query := `
-        SELECT DISTINCT ss.*
-        FROM study_sessions ss
-        JOIN word_review_items wri ON ss.id = wri.study_session_id
-        JOIN words_groups wg ON wg.word_id = wri.word_id
-        WHERE wg.group_id = ?
-        ORDER BY ss.started_at DESC
+        SELECT ` + studySessionColumns + `
+        FROM study_sessions
+        WHERE id IN (
+            SELECT wri.study_session_id
+            FROM word_review_items wri
+            JOIN words_groups wg ON wg.word_id = wri.word_id
+            WHERE wg.group_id = ?
+        ) AND user_id = ?
+        ORDER BY created_at DESC
         LIMIT ? OFFSET ?
     `
-	rows, err := db.Query(query, groupID, limit, offset)
+	rows, err := db.Query(query, groupID, userID, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error querying group study sessions: %v", err)
 	}
@@ -131,8 +135,7 @@ func GetWordGroupStudySessions(db *sql.DB, groupID, page, limit int) ([]models.S
 
 	var sessions []models.StudySession
 	for rows.Next() {
-		var session models.StudySession
-		err := rows.Scan(&session.ID, &session.StartedAt, &session.EndedAt)
+		session, err := scanStudySession(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("error scanning study session row: %v", err)
 		}
@@ -142,8 +145,8 @@ func GetWordGroupStudySessions(db *sql.DB, groupID, page, limit int) ([]models.S
 	return sessions, totalItems, nil
 }
 
-// GetWordGroupStudySessionsRaw retrieves raw study session data for a word group with pagination
-func GetWordGroupStudySessionsRaw(db *sql.DB, groupID, page, limit int) ([]models.StudyActivity, int, error) {
+// GetWordGroupStudySessionsRaw retrieves raw study session data for a user's reviews of a word group with pagination
+func GetWordGroupStudySessionsRaw(db *sql.DB, userID, groupID, page, limit int) ([]models.StudyActivity, int, error) {
 	offset := (page - 1) * limit
 
 	// First, get total count
@@ -153,24 +156,24 @@ func GetWordGroupStudySessionsRaw(db *sql.DB, groupID, page, limit int) ([]model
         FROM study_activities sa
         JOIN word_review_items wri ON sa.study_session_id = wri.study_session_id
         JOIN words_groups wg ON wg.word_id = wri.word_id
-        WHERE wg.group_id = ?
+        WHERE wg.group_id = ? AND wri.user_id = ?
     `
-	err := db.QueryRow(countQuery, groupID).Scan(&totalItems)
+	err := db.QueryRow(countQuery, groupID, userID).Scan(&totalItems)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error counting group study activities: %v", err)
 	}
 
 	// Then get paginated study activities
 	query := `
-        SELECT sa.*
+        SELECT sa.id, sa.study_session_id, COALESCE(sa.name, ''), sa.group_id, sa.created_at
         FROM study_activities sa
         JOIN word_review_items wri ON sa.study_session_id = wri.study_session_id
         JOIN words_groups wg ON wg.word_id = wri.word_id
-        WHERE wg.group_id = ?
+        WHERE wg.group_id = ? AND wri.user_id = ?
         ORDER BY sa.created_at
         LIMIT ? OFFSET ?
     `
-	rows, err := db.Query(query, groupID, limit, offset)
+	rows, err := db.Query(query, groupID, userID, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error querying group study activities: %v", err)
 	}
@@ -179,7 +182,7 @@ func GetWordGroupStudySessionsRaw(db *sql.DB, groupID, page, limit int) ([]model
 	var activities []models.StudyActivity
 	for rows.Next() {
 		var activity models.StudyActivity
-		err := rows.Scan(&activity.ID, &activity.StudySessionID, &activity.ActivityType, &activity.CreatedAt)
+		err := rows.Scan(&activity.ID, &activity.StudySessionID, &activity.ActivityType, &activity.GroupID, &activity.CreatedAt)
 		if err != nil {
 			return nil, 0, fmt.Errorf("error scanning study activity row: %v", err)
 		}
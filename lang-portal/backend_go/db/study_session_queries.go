@@ -4,13 +4,25 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"time"
 
 	"backend_go/models" // Import your models package
 )
 
-// GetAllStudySessions retrieves all study sessions from the database.
-func GetAllStudySessions(db *sql.DB) ([]models.StudySession, error) {
-	rows, err := db.Query("SELECT id, group_id, created_at, study_activity_id FROM study_sessions")
+// studySessionColumns lists the columns scanStudySession expects, in order.
+const studySessionColumns = "id, user_id, group_id, created_at, study_activity_id, status, ended_at"
+
+// scanStudySession scans a row selected with studySessionColumns.
+func scanStudySession(row interface{ Scan(...interface{}) error }) (models.StudySession, error) {
+	var studySession models.StudySession
+	err := row.Scan(&studySession.ID, &studySession.UserID, &studySession.GroupID, &studySession.CreatedAt, &studySession.StudyActivityID,
+		&studySession.Status, &studySession.EndedAt)
+	return studySession, err
+}
+
+// GetAllStudySessions retrieves all study sessions belonging to a user.
+func GetAllStudySessions(db *sql.DB, userID int) ([]models.StudySession, error) {
+	rows, err := db.Query("SELECT "+studySessionColumns+" FROM study_sessions WHERE user_id = ?", userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query study sessions: %w", err)
 	}
@@ -18,8 +30,8 @@ func GetAllStudySessions(db *sql.DB) ([]models.StudySession, error) {
 
 	var studySessions []models.StudySession
 	for rows.Next() {
-		var studySession models.StudySession
-		if err := rows.Scan(&studySession.ID, &studySession.GroupID, &studySession.CreatedAt, &studySession.StudyActivityID); err != nil {
+		studySession, err := scanStudySession(rows)
+		if err != nil {
 			log.Println("Error scanning study session row:", err)
 			continue
 		}
@@ -35,10 +47,7 @@ func GetAllStudySessions(db *sql.DB) ([]models.StudySession, error) {
 
 // GetStudySessionByID retrieves a study session from the database by its ID.
 func GetStudySessionByID(db *sql.DB, id int) (*models.StudySession, error) {
-	row := db.QueryRow("SELECT id, group_id, created_at, study_activity_id FROM study_sessions WHERE id = ?", id)
-
-	var studySession models.StudySession
-	err := row.Scan(&studySession.ID, &studySession.GroupID, &studySession.CreatedAt, &studySession.StudyActivityID)
+	studySession, err := scanStudySession(db.QueryRow("SELECT "+studySessionColumns+" FROM study_sessions WHERE id = ?", id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Study session not found
@@ -50,9 +59,19 @@ func GetStudySessionByID(db *sql.DB, id int) (*models.StudySession, error) {
 }
 
 // CreateStudySession creates a new study session in the database.
+// Sessions without a user belong to the default user.
 func CreateStudySession(db *sql.DB, studySession *models.StudySession) (int, error) {
-	result, err := db.Exec("INSERT INTO study_sessions (group_id, created_at, study_activity_id) VALUES (?, ?, ?)",
-		studySession.GroupID, studySession.CreatedAt, studySession.StudyActivityID)
+	if studySession.CreatedAt == "" {
+		studySession.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	}
+	if studySession.UserID == 0 {
+		studySession.UserID = models.DefaultUserID
+	}
+	studySession.Status = models.StudySessionActive
+	studySession.EndedAt = nil
+
+	result, err := db.Exec("INSERT INTO study_sessions (user_id, group_id, created_at, study_activity_id) VALUES (?, ?, ?, ?)",
+		studySession.UserID, studySession.GroupID, studySession.CreatedAt, studySession.StudyActivityID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create study session: %w", err)
 	}
@@ -65,10 +84,56 @@ func CreateStudySession(db *sql.DB, studySession *models.StudySession) (int, err
 	return int(id), nil
 }
 
-// UpdateStudySession updates an existing study session in the database.
+// CompleteStudySession marks a user's study session as finished by the learner at
+// endedAt. A session that was already closed as abandoned keeps its end time, so the
+// idle period is not counted. It returns false when the session was already
+// completed or does not exist.
+func CompleteStudySession(db *sql.DB, id, userID int, endedAt time.Time) (bool, error) {
+	result, err := db.Exec(`UPDATE study_sessions
+		SET status = ?, ended_at = CASE WHEN status = ? THEN ended_at ELSE ? END
+		WHERE id = ? AND user_id = ? AND status != ?`,
+		models.StudySessionCompleted, models.StudySessionAbandoned, endedAt, id, userID, models.StudySessionCompleted)
+	if err != nil {
+		return false, fmt.Errorf("failed to complete study session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// lastActivitySQL is when a study session was last used: its latest review, or its
+// creation when it has none.
+const lastActivitySQL = `COALESCE((SELECT MAX(created_at) FROM word_review_items WHERE study_session_id = study_sessions.id), study_sessions.created_at)`
+
+// AbandonIdleStudySessions closes every active study session that has not been used
+// since idleSince, ending it at its last review. It returns how many were closed.
+func AbandonIdleStudySessions(db *sql.DB, idleSince time.Time) (int, error) {
+	result, err := db.Exec(`UPDATE study_sessions
+		SET status = ?, ended_at = `+lastActivitySQL+`
+		WHERE status = ? AND julianday(`+lastActivitySQL+`) < julianday(?)`,
+		models.StudySessionAbandoned, models.StudySessionActive, idleSince.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to close idle study sessions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rowsAffected), nil
+}
+
+// reopenStudySessionSQL reactivates an abandoned session that received a new review:
+// the learner came back, so the session was not abandoned after all.
+const reopenStudySessionSQL = `UPDATE study_sessions SET status = 'active', ended_at = NULL WHERE id = ? AND status = 'abandoned'`
+
+// UpdateStudySession updates an existing study session owned by studySession.UserID.
 func UpdateStudySession(db *sql.DB, studySession *models.StudySession) error {
-	result, err := db.Exec("UPDATE study_sessions SET group_id = ?, created_at = ?, study_activity_id = ? WHERE id = ?",
-		studySession.GroupID, studySession.CreatedAt, studySession.StudyActivityID, studySession.ID)
+	result, err := db.Exec("UPDATE study_sessions SET group_id = ?, created_at = ?, study_activity_id = ? WHERE id = ? AND user_id = ?",
+		studySession.GroupID, studySession.CreatedAt, studySession.StudyActivityID, studySession.ID, studySession.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to update study session: %w", err)
 	}
@@ -85,9 +150,9 @@ func UpdateStudySession(db *sql.DB, studySession *models.StudySession) error {
 	return nil
 }
 
-// DeleteStudySession deletes a study session from the database.
-func DeleteStudySession(db *sql.DB, id int) error {
-	result, err := db.Exec("DELETE FROM study_sessions WHERE id = ?", id)
+// DeleteStudySession deletes a study session owned by a user from the database.
+func DeleteStudySession(db *sql.DB, id, userID int) error {
+	result, err := db.Exec("DELETE FROM study_sessions WHERE id = ? AND user_id = ?", id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete study session: %w", err)
 	}
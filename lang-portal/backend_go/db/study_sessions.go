@@ -2,16 +2,13 @@ package db
 
 import (
 	"database/sql"
-	"time"
+
+	"backend_go/models"
 )
 
-func FetchStudySessionWords(db *sql.DB, sessionID, page, limit int) ([]struct {
-	ID             int    `json:"id"`
-	English        string `json:"english"`
-	Portuguese     string `json:"portuguese"`
-	CorrectCount   int    `json:"correct_count"`
-	IncorrectCount int    `json:"incorrect_count"`
-}, int, error) {
+// FetchStudySessionWords retrieves the words reviewed in a study session with their
+// correct and incorrect counts, with pagination.
+func FetchStudySessionWords(db *sql.DB, sessionID, page, limit int) ([]models.SessionWordStats, int, error) {
 	offset := (page - 1) * limit
 
 	// First get total count
@@ -29,14 +26,14 @@ func FetchStudySessionWords(db *sql.DB, sessionID, page, limit int) ([]struct {
 	query := `
         SELECT 
             w.id,
-            w.english,
-            w.portuguese,
+            w.source_text,
+            w.target_text,
             SUM(CASE WHEN wri.is_correct = 1 THEN 1 ELSE 0 END) as correct_count,
             SUM(CASE WHEN wri.is_correct = 0 THEN 1 ELSE 0 END) as incorrect_count
         FROM words w
         JOIN word_review_items wri ON w.id = wri.word_id
         WHERE wri.study_session_id = ?
-        GROUP BY w.id, w.english, w.portuguese
+        GROUP BY w.id, w.source_text, w.target_text
         ORDER BY w.id
         LIMIT ? OFFSET ?`
 
@@ -46,22 +43,9 @@ func FetchStudySessionWords(db *sql.DB, sessionID, page, limit int) ([]struct {
 	}
 	defer rows.Close()
 
-	var words []struct {
-		ID             int    `json:"id"`
-		English        string `json:"english"`
-		Portuguese     string `json:"portuguese"`
-		CorrectCount   int    `json:"correct_count"`
-		IncorrectCount int    `json:"incorrect_count"`
-	}
-
+	var words []models.SessionWordStats
 	for rows.Next() {
-		var word struct {
-			ID             int    `json:"id"`
-			English        string `json:"english"`
-			Portuguese     string `json:"portuguese"`
-			CorrectCount   int    `json:"correct_count"`
-			IncorrectCount int    `json:"incorrect_count"`
-		}
+		var word models.SessionWordStats
 		if err := rows.Scan(&word.ID, &word.English, &word.Portuguese, &word.CorrectCount, &word.IncorrectCount); err != nil {
 			return nil, 0, err
 		}
@@ -71,16 +55,10 @@ func FetchStudySessionWords(db *sql.DB, sessionID, page, limit int) ([]struct {
 	return words, totalItems, rows.Err()
 }
 
-func FetchWordGroupStudySessions(db *sql.DB, groupID, page, limit int) ([]struct {
-	ID              int       `json:"id"`
-	CreatedAt       time.Time `json:"created_at"`
-	ActivityName    string    `json:"activity_name"`
-	TotalWords      int       `json:"total_words"`
-	CorrectCount    int       `json:"correct_count"`
-	IncorrectCount  int       `json:"incorrect_count"`
-	SuccessRate     float64   `json:"success_rate"`
-	DurationMinutes int       `json:"duration_minutes"`
-}, int, error) {
+// FetchWordGroupStudySessions retrieves a user's study sessions for a group with review
+// counts, success rate and duration, newest first, with pagination. The duration runs
+// from the first review to the session's end, or to its last review while it is active.
+func FetchWordGroupStudySessions(db *sql.DB, userID, groupID, page, limit int) ([]models.GroupStudySessionSummary, int, error) {
 	offset := (page - 1) * limit
 
 	// First get total count
@@ -88,7 +66,7 @@ func FetchWordGroupStudySessions(db *sql.DB, groupID, page, limit int) ([]struct
 	err := db.QueryRow(`
         SELECT COUNT(DISTINCT ss.id)
         FROM study_sessions ss
-        WHERE ss.group_id = ?`, groupID).Scan(&totalItems)
+        WHERE ss.group_id = ? AND ss.user_id = ?`, groupID, userID).Scan(&totalItems)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -98,48 +76,30 @@ func FetchWordGroupStudySessions(db *sql.DB, groupID, page, limit int) ([]struct
         SELECT 
             ss.id,
             ss.created_at,
-            sa.name as activity_name,
+            COALESCE(sa.name, '') as activity_name,
             COUNT(DISTINCT wri.word_id) as total_words,
             SUM(CASE WHEN wri.is_correct = 1 THEN 1 ELSE 0 END) as correct_count,
             SUM(CASE WHEN wri.is_correct = 0 THEN 1 ELSE 0 END) as incorrect_count,
-            ROUND(AVG(CASE WHEN wri.is_correct = 1 THEN 100.0 ELSE 0.0 END), 1) as success_rate,
-            ROUND((julianday(MAX(wri.created_at)) - julianday(MIN(wri.created_at))) * 24 * 60) as duration_minutes
+            COALESCE(ROUND(AVG(CASE WHEN wri.is_correct = 1 THEN 100.0 WHEN wri.is_correct = 0 THEN 0.0 END), 1), 0) as success_rate,
+            CAST(COALESCE(ROUND((julianday(COALESCE(ss.ended_at, MAX(wri.created_at))) - julianday(MIN(wri.created_at))) * 24 * 60), 0) AS INTEGER) as duration_minutes,
+            ss.status
         FROM study_sessions ss
-        JOIN study_activities sa ON ss.study_activity_id = sa.id
+        LEFT JOIN study_activities sa ON ss.study_activity_id = sa.id
         LEFT JOIN word_review_items wri ON ss.id = wri.study_session_id
-        WHERE ss.group_id = ?
-        GROUP BY ss.id, ss.created_at, sa.name
+        WHERE ss.group_id = ? AND ss.user_id = ?
+        GROUP BY ss.id, ss.created_at, sa.name, ss.ended_at, ss.status
         ORDER BY ss.created_at DESC
         LIMIT ? OFFSET ?`
 
-	rows, err := db.Query(query, groupID, limit, offset)
+	rows, err := db.Query(query, groupID, userID, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var sessions []struct {
-		ID              int       `json:"id"`
-		CreatedAt       time.Time `json:"created_at"`
-		ActivityName    string    `json:"activity_name"`
-		TotalWords      int       `json:"total_words"`
-		CorrectCount    int       `json:"correct_count"`
-		IncorrectCount  int       `json:"incorrect_count"`
-		SuccessRate     float64   `json:"success_rate"`
-		DurationMinutes int       `json:"duration_minutes"`
-	}
-
+	var sessions []models.GroupStudySessionSummary
 	for rows.Next() {
-		var session struct {
-			ID              int       `json:"id"`
-			CreatedAt       time.Time `json:"created_at"`
-			ActivityName    string    `json:"activity_name"`
-			TotalWords      int       `json:"total_words"`
-			CorrectCount    int       `json:"correct_count"`
-			IncorrectCount  int       `json:"incorrect_count"`
-			SuccessRate     float64   `json:"success_rate"`
-			DurationMinutes int       `json:"duration_minutes"`
-		}
+		var session models.GroupStudySessionSummary
 		if err := rows.Scan(
 			&session.ID,
 			&session.CreatedAt,
@@ -149,6 +109,7 @@ func FetchWordGroupStudySessions(db *sql.DB, groupID, page, limit int) ([]struct
 			&session.IncorrectCount,
 			&session.SuccessRate,
 			&session.DurationMinutes,
+			&session.Status,
 		); err != nil {
 			return nil, 0, err
 		}
@@ -158,16 +119,9 @@ func FetchWordGroupStudySessions(db *sql.DB, groupID, page, limit int) ([]struct
 	return sessions, totalItems, rows.Err()
 }
 
-func GetWordGroupStudySessionsDetails(db *sql.DB, groupID, page, limit int) ([]struct {
-	ID              int       `json:"id"`
-	CreatedAt       time.Time `json:"created_at"`
-	ActivityName    string    `json:"activity_name"`
-	WordID          int       `json:"word_id"`
-	English         string    `json:"english"`
-	Portuguese      string    `json:"portuguese"`
-	IsCorrect       bool      `json:"is_correct"`
-	ReviewCreatedAt time.Time `json:"review_created_at"`
-}, int, error) {
+// GetWordGroupStudySessionsDetails retrieves every review made in a user's study sessions
+// for a group together with the session and word it belongs to, with pagination.
+func GetWordGroupStudySessionsDetails(db *sql.DB, userID, groupID, page, limit int) ([]models.GroupStudySessionReview, int, error) {
 	offset := (page - 1) * limit
 
 	// First get total count
@@ -176,7 +130,7 @@ func GetWordGroupStudySessionsDetails(db *sql.DB, groupID, page, limit int) ([]s
         SELECT COUNT(*)
         FROM study_sessions ss
         JOIN word_review_items wri ON ss.id = wri.study_session_id
-        WHERE ss.group_id = ?`, groupID).Scan(&totalItems)
+        WHERE ss.group_id = ? AND ss.user_id = ?`, groupID, userID).Scan(&totalItems)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -186,48 +140,29 @@ func GetWordGroupStudySessionsDetails(db *sql.DB, groupID, page, limit int) ([]s
         SELECT 
             ss.id,
             ss.created_at,
-            sa.name as activity_name,
+            COALESCE(sa.name, '') as activity_name,
             w.id as word_id,
-            w.english,
-            w.portuguese,
+            w.source_text,
+            w.target_text,
             wri.is_correct,
             wri.created_at as review_created_at
         FROM study_sessions ss
-        JOIN study_activities sa ON ss.study_activity_id = sa.id
+        LEFT JOIN study_activities sa ON ss.study_activity_id = sa.id
         JOIN word_review_items wri ON ss.id = wri.study_session_id
         JOIN words w ON wri.word_id = w.id
-        WHERE ss.group_id = ?
+        WHERE ss.group_id = ? AND ss.user_id = ?
         ORDER BY ss.created_at DESC, wri.created_at ASC
         LIMIT ? OFFSET ?`
 
-	rows, err := db.Query(query, groupID, limit, offset)
+	rows, err := db.Query(query, groupID, userID, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var reviews []struct {
-		ID              int       `json:"id"`
-		CreatedAt       time.Time `json:"created_at"`
-		ActivityName    string    `json:"activity_name"`
-		WordID          int       `json:"word_id"`
-		English         string    `json:"english"`
-		Portuguese      string    `json:"portuguese"`
-		IsCorrect       bool      `json:"is_correct"`
-		ReviewCreatedAt time.Time `json:"review_created_at"`
-	}
-
+	var reviews []models.GroupStudySessionReview
 	for rows.Next() {
-		var review struct {
-			ID              int       `json:"id"`
-			CreatedAt       time.Time `json:"created_at"`
-			ActivityName    string    `json:"activity_name"`
-			WordID          int       `json:"word_id"`
-			English         string    `json:"english"`
-			Portuguese      string    `json:"portuguese"`
-			IsCorrect       bool      `json:"is_correct"`
-			ReviewCreatedAt time.Time `json:"review_created_at"`
-		}
+		var review models.GroupStudySessionReview
 		if err := rows.Scan(
 			&review.ID,
 			&review.CreatedAt,
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"testing"
+
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudySessionAggregatesIntegration(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+
+	_, err = db.Exec(`
+		INSERT INTO words_groups (word_id, group_id) VALUES (1, 1), (2, 1), (3, 1);
+		INSERT INTO study_activities (id, study_session_id, group_id, created_at, name) VALUES (1, 1, 1, '2025-02-01 09:00:00', 'Flashcards');
+		INSERT INTO study_sessions (id, group_id, created_at, study_activity_id) VALUES
+			(1, 1, '2025-02-01 10:00:00', 1),
+			(2, 1, '2025-02-02 10:00:00', 1);
+		INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at) VALUES
+			(1, 1, 1, '2025-02-01 10:00:00'),
+			(1, 1, 0, '2025-02-01 10:04:00'),
+			(2, 1, 1, '2025-02-01 10:12:00'),
+			(3, 1, 1, '2025-02-01 10:15:00')`)
+	require.NoError(t, err)
+
+	t.Run("FetchStudySessionWords", func(t *testing.T) {
+		words, total, err := FetchStudySessionWords(db, 1, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		require.Len(t, words, 2)
+		assert.Equal(t, "hello", words[0].English)
+		assert.Equal(t, 1, words[0].CorrectCount)
+		assert.Equal(t, 1, words[0].IncorrectCount)
+	})
+
+	t.Run("FetchWordGroupStudySessions", func(t *testing.T) {
+		sessions, total, err := FetchWordGroupStudySessions(db, models.DefaultUserID, 1, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, sessions, 2)
+
+		// Newest first; the second session has no reviews yet
+		assert.Equal(t, 2, sessions[0].ID)
+		assert.Equal(t, 0, sessions[0].TotalWords)
+		assert.Equal(t, 0, sessions[0].DurationMinutes)
+
+		assert.Equal(t, "Flashcards", sessions[1].ActivityName)
+		assert.Equal(t, 3, sessions[1].TotalWords)
+		assert.Equal(t, 3, sessions[1].CorrectCount)
+		assert.Equal(t, 1, sessions[1].IncorrectCount)
+		assert.Equal(t, 75.0, sessions[1].SuccessRate)
+		assert.Equal(t, 15, sessions[1].DurationMinutes)
+	})
+
+	t.Run("GetWordGroupStudySessionsDetails", func(t *testing.T) {
+		reviews, total, err := GetWordGroupStudySessionsDetails(db, models.DefaultUserID, 1, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		require.Len(t, reviews, 4)
+		assert.Equal(t, "hello", reviews[0].English)
+		assert.True(t, reviews[0].IsCorrect)
+		assert.False(t, reviews[1].IsCorrect)
+		assert.Equal(t, "thank you", reviews[3].English)
+	})
+}
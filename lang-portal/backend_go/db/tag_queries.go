@@ -0,0 +1,87 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"backend_go/models"
+)
+
+// GetWordTags retrieves a word's tags in alphabetical order.
+func GetWordTags(db *sql.DB, wordID int) ([]string, error) {
+	rows, err := db.Query("SELECT tag FROM word_tags WHERE word_id = ? ORDER BY tag", wordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query word tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan word tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word tag rows: %w", err)
+	}
+
+	return tags, nil
+}
+
+// SetWordTags replaces a word's tags in one transaction.
+func SetWordTags(db *sql.DB, wordID int, tags []string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM word_tags WHERE word_id = ?", wordID); err != nil {
+		return fmt.Errorf("failed to clear word tags: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO word_tags (word_id, tag) VALUES (?, ?)", wordID, tag); err != nil {
+			return fmt.Errorf("failed to add word tag: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit word tags: %w", err)
+	}
+	return nil
+}
+
+// DeleteWordTags removes every tag from a word.
+func DeleteWordTags(db *sql.DB, wordID int) error {
+	if _, err := db.Exec("DELETE FROM word_tags WHERE word_id = ?", wordID); err != nil {
+		return fmt.Errorf("failed to delete word tags: %w", err)
+	}
+	return nil
+}
+
+// GetTags retrieves every tag in use with the number of words carrying it.
+func GetTags(db *sql.DB) ([]models.TagCount, error) {
+	rows, err := db.Query("SELECT tag, COUNT(*) FROM word_tags GROUP BY tag ORDER BY tag")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.TagCount{}
+	for rows.Next() {
+		var tag models.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Words); err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tag rows: %w", err)
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend_go/models"
+)
+
+// GetAllUsers retrieves every user ordered by ID.
+func GetAllUsers(db *sql.DB) ([]models.User, error) {
+	rows, err := db.Query("SELECT id, username, display_name, role, created_at FROM users ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.DisplayName, &user.Role, &user.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user rows: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUserByID retrieves a user from the database by its ID.
+func GetUserByID(db *sql.DB, id int) (*models.User, error) {
+	row := db.QueryRow("SELECT id, username, display_name, role, created_at FROM users WHERE id = ?", id)
+
+	var user models.User
+	err := row.Scan(&user.ID, &user.Username, &user.DisplayName, &user.Role, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // User not found
+		}
+		return nil, fmt.Errorf("failed to scan user row: %w", err)
+	}
+
+	return &user, nil
+}
+
+// UsernameExists reports whether a user with the given username exists.
+func UsernameExists(db *sql.DB, username string) (bool, error) {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users WHERE username = ?", username).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check username: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CreateUser creates a user together with a default daily goal. PasswordHash may be
+// empty for users that only authenticate with API tokens.
+func CreateUser(db *sql.DB, user *models.User) (int, error) {
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now().UTC()
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO users (username, display_name, role, created_at, password_hash) VALUES (?, ?, ?, ?, ?)",
+		user.Username, user.DisplayName, user.Role, user.CreatedAt, nullIfEmpty(user.PasswordHash))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO goals (user_id, daily_review_target, time_zone, created_at, updated_at)
+		VALUES (?, 20, 'UTC', ?, ?)`, id, user.CreatedAt, user.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create default goal: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit user: %w", err)
+	}
+
+	user.ID = int(id)
+	return int(id), nil
+}
+
+// GetUserByUsername retrieves a user, including its password hash, by username.
+func GetUserByUsername(db *sql.DB, username string) (*models.User, error) {
+	row := db.QueryRow(`SELECT id, username, display_name, role, created_at, COALESCE(password_hash, '')
+		FROM users WHERE username = ?`, username)
+
+	var user models.User
+	err := row.Scan(&user.ID, &user.Username, &user.DisplayName, &user.Role, &user.CreatedAt, &user.PasswordHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // User not found
+		}
+		return nil, fmt.Errorf("failed to scan user row: %w", err)
+	}
+
+	return &user, nil
+}
+
+// GetUserPasswordHash retrieves a user's password hash, empty if none is set.
+func GetUserPasswordHash(db *sql.DB, userID int) (string, error) {
+	var hash string
+	err := db.QueryRow("SELECT COALESCE(password_hash, '') FROM users WHERE id = ?", userID).Scan(&hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get password hash: %w", err)
+	}
+	return hash, nil
+}
+
+// UpdateUserPassword replaces a user's password hash.
+func UpdateUserPassword(db *sql.DB, userID int, passwordHash string) error {
+	result, err := db.Exec("UPDATE users SET password_hash = ? WHERE id = ?", passwordHash, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user with id %d not found", userID)
+	}
+
+	return nil
+}
+
+// CountUsersWithRole returns how many users have the given role.
+func CountUsersWithRole(db *sql.DB, role string) (int, error) {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users WHERE role = ?", role).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
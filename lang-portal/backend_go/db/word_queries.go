@@ -2,15 +2,89 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 
 	"backend_go/models" // Import your models package
 )
 
-// GetAllWords retrieves all words from the database.
-func GetAllWords(db *sql.DB) ([]models.Word, error) {
-	rows, err := db.Query("SELECT id, english, portuguese, parts FROM words")
+// wordColumns lists the columns scanWord expects, in order.
+const wordColumns = "id, source_language, target_language, source_text, target_text, parts, grammar"
+
+// scanWord scans a row selected with wordColumns and mirrors the pair into the
+// legacy English and Portuguese fields.
+func scanWord(row interface{ Scan(...interface{}) error }) (models.Word, error) {
+	var word models.Word
+	var grammarJSON sql.NullString
+	if err := row.Scan(&word.ID, &word.SourceLanguage, &word.TargetLanguage, &word.SourceText, &word.TargetText,
+		&word.Parts, &grammarJSON); err != nil {
+		return word, err
+	}
+	word.English, word.Portuguese = word.SourceText, word.TargetText
+
+	if grammarJSON.Valid {
+		word.Grammar = &models.Grammar{}
+		if err := json.Unmarshal([]byte(grammarJSON.String), word.Grammar); err != nil {
+			return word, fmt.Errorf("invalid grammar for word %d: %w", word.ID, err)
+		}
+	}
+	return word, nil
+}
+
+// grammarValue encodes a word's grammar for the grammar column.
+func grammarValue(grammar *models.Grammar) (sql.NullString, error) {
+	if grammar == nil {
+		return sql.NullString{}, nil
+	}
+	encoded, err := json.Marshal(grammar)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to encode grammar: %w", err)
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
+
+// WordFilter selects words; zero fields match every word.
+type WordFilter struct {
+	SourceLanguage string
+	TargetLanguage string
+	PartOfSpeech   string
+	Gender         string
+	Tag            string
+}
+
+// where returns the filter's conditions on the words table, aliased w, and their arguments.
+func (filter WordFilter) where() (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	args := []interface{}{}
+	if filter.SourceLanguage != "" {
+		conditions = append(conditions, "w.source_language = ?")
+		args = append(args, filter.SourceLanguage)
+	}
+	if filter.TargetLanguage != "" {
+		conditions = append(conditions, "w.target_language = ?")
+		args = append(args, filter.TargetLanguage)
+	}
+	if filter.PartOfSpeech != "" {
+		conditions = append(conditions, "LOWER(TRIM(w.parts)) = LOWER(?)")
+		args = append(args, filter.PartOfSpeech)
+	}
+	if filter.Gender != "" {
+		conditions = append(conditions, "json_extract(w.grammar, '$.gender') = ?")
+		args = append(args, filter.Gender)
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM word_tags t WHERE t.word_id = w.id AND t.tag = ?)")
+		args = append(args, filter.Tag)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// GetAllWords retrieves all words matching the filter from the database.
+func GetAllWords(db *sql.DB, filter WordFilter) ([]models.Word, error) {
+	conditions, args := filter.where()
+	rows, err := db.Query("SELECT "+wordColumns+" FROM words w WHERE "+conditions+" ORDER BY id", args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query words: %w", err)
 	}
@@ -18,8 +92,8 @@ func GetAllWords(db *sql.DB) ([]models.Word, error) {
 
 	var words []models.Word
 	for rows.Next() {
-		var word models.Word
-		if err := rows.Scan(&word.ID, &word.English, &word.Portuguese, &word.Parts); err != nil {
+		word, err := scanWord(rows)
+		if err != nil {
 			log.Println("Error scanning word row:", err)
 			continue
 		}
@@ -33,12 +107,45 @@ func GetAllWords(db *sql.DB) ([]models.Word, error) {
 	return words, nil
 }
 
+// GetGroupWords retrieves the words matching the filter in a group, ordered by ID.
+// A static group includes the words of its subgroups; a smart group's rule is
+// evaluated for scope.
+func GetGroupWords(db *sql.DB, groupID int, scope GroupScope, filter WordFilter) ([]models.Word, error) {
+	members, args, err := groupMembers(db, groupID, scope)
+	if err != nil {
+		return nil, err
+	}
+	conditions, filterArgs := filter.where()
+	rows, err := db.Query(`
+        SELECT `+wordColumns+`
+        FROM words w
+        WHERE w.id IN (`+members+`)
+          AND `+conditions+`
+        ORDER BY w.id`, append(args, filterArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query group words: %w", err)
+	}
+	defer rows.Close()
+
+	words := []models.Word{}
+	for rows.Next() {
+		word, err := scanWord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan word row: %w", err)
+		}
+		words = append(words, word)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word rows: %w", err)
+	}
+
+	return words, nil
+}
+
 // GetWordByID retrieves a word from the database by its ID.
 func GetWordByID(db *sql.DB, id int) (*models.Word, error) {
-	row := db.QueryRow("SELECT id, english, portuguese, parts FROM words WHERE id = ?", id)
-
-	var word models.Word
-	err := row.Scan(&word.ID, &word.English, &word.Portuguese, &word.Parts)
+	word, err := scanWord(db.QueryRow("SELECT "+wordColumns+" FROM words WHERE id = ?", id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Word not found
@@ -49,10 +156,18 @@ func GetWordByID(db *sql.DB, id int) (*models.Word, error) {
 	return &word, nil
 }
 
-// CreateWord creates a new word in the database.
+// CreateWord creates a new word in the database. Words without languages are
+// English to Portuguese.
 func CreateWord(db *sql.DB, word *models.Word) (int, error) {
-	result, err := db.Exec("INSERT INTO words (english, portuguese, parts) VALUES (?, ?, ?)",
-		word.English, word.Portuguese, word.Parts)
+	DefaultLanguagePair(word)
+	grammar, err := grammarValue(word.Grammar)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := db.Exec(`INSERT INTO words (source_language, target_language, source_text, target_text, parts, grammar)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		word.SourceLanguage, word.TargetLanguage, word.SourceText, word.TargetText, word.Parts, grammar)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create word: %w", err)
 	}
@@ -67,8 +182,16 @@ func CreateWord(db *sql.DB, word *models.Word) (int, error) {
 
 // UpdateWord updates an existing word in the database.
 func UpdateWord(db *sql.DB, word *models.Word) error {
-	result, err := db.Exec("UPDATE words SET english = ?, portuguese = ?, parts = ? WHERE id = ?",
-		word.English, word.Portuguese, word.Parts, word.ID)
+	DefaultLanguagePair(word)
+	grammar, err := grammarValue(word.Grammar)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.Exec(`UPDATE words SET source_language = ?, target_language = ?, source_text = ?, target_text = ?,
+		parts = ?, grammar = ?
+		WHERE id = ?`,
+		word.SourceLanguage, word.TargetLanguage, word.SourceText, word.TargetText, word.Parts, grammar, word.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update word: %w", err)
 	}
@@ -85,6 +208,25 @@ func UpdateWord(db *sql.DB, word *models.Word) error {
 	return nil
 }
 
+// DefaultLanguagePair fills a word's pair from the legacy English and Portuguese
+// fields and defaults its languages. A legacy field that is set wins over the pair;
+// handlers reconcile the two against the stored word before calling it.
+func DefaultLanguagePair(word *models.Word) {
+	if word.English != "" {
+		word.SourceText = word.English
+	}
+	if word.Portuguese != "" {
+		word.TargetText = word.Portuguese
+	}
+	if word.SourceLanguage == "" {
+		word.SourceLanguage = models.DefaultSourceLanguage
+	}
+	if word.TargetLanguage == "" {
+		word.TargetLanguage = models.DefaultTargetLanguage
+	}
+	word.English, word.Portuguese = word.SourceText, word.TargetText
+}
+
 // DeleteWord deletes a word from the database.
 func DeleteWord(db *sql.DB, id int) error {
 	result, err := db.Exec("DELETE FROM words WHERE id = ?", id)
@@ -103,3 +245,118 @@ func DeleteWord(db *sql.DB, id int) error {
 
 	return nil
 }
+
+// MergeWords moves the groups, reviews, example sentences and media of the
+// duplicate word to the survivor and deletes the duplicate, all in one
+// transaction. Group memberships and sentences the survivor already has are
+// dropped rather than repeated.
+func MergeWords(db *sql.DB, survivorID, duplicateID int) (*models.MergeResult, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var result models.MergeResult
+	moves := []struct {
+		query string
+		args  []interface{}
+		count *int
+	}{
+		{`UPDATE words_groups SET word_id = ? WHERE word_id = ?
+			AND group_id NOT IN (SELECT group_id FROM words_groups WHERE word_id = ?)`,
+			[]interface{}{survivorID, duplicateID, survivorID}, &result.Groups},
+		{"UPDATE word_review_items SET word_id = ? WHERE word_id = ?",
+			[]interface{}{survivorID, duplicateID}, &result.Reviews},
+		{`UPDATE sentences SET word_id = ? WHERE word_id = ?
+			AND text NOT IN (SELECT text FROM sentences WHERE word_id = ?)`,
+			[]interface{}{survivorID, duplicateID, survivorID}, &result.Sentences},
+		{"UPDATE word_media SET word_id = ? WHERE word_id = ?",
+			[]interface{}{survivorID, duplicateID}, &result.Media},
+		{`UPDATE word_tags SET word_id = ? WHERE word_id = ?
+			AND tag NOT IN (SELECT tag FROM word_tags WHERE word_id = ?)`,
+			[]interface{}{survivorID, duplicateID, survivorID}, &result.Tags},
+	}
+	for _, move := range moves {
+		moved, err := tx.Exec(move.query, move.args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge words: %w", err)
+		}
+		n, err := moved.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		*move.count = int(n)
+	}
+
+	for _, query := range []string{
+		"DELETE FROM words_groups WHERE word_id = ?",
+		"DELETE FROM sentences WHERE word_id = ?",
+		"DELETE FROM word_tags WHERE word_id = ?",
+		"DELETE FROM words WHERE id = ?",
+	} {
+		if _, err := tx.Exec(query, duplicateID); err != nil {
+			return nil, fmt.Errorf("failed to delete duplicate word: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit word merge: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetLanguages retrieves every language ordered by code.
+func GetLanguages(db *sql.DB) ([]models.Language, error) {
+	rows, err := db.Query("SELECT code, name FROM languages ORDER BY code")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query languages: %w", err)
+	}
+	defer rows.Close()
+
+	languages := []models.Language{}
+	for rows.Next() {
+		var language models.Language
+		if err := rows.Scan(&language.Code, &language.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan language row: %w", err)
+		}
+		languages = append(languages, language)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating language rows: %w", err)
+	}
+
+	return languages, nil
+}
+
+// LanguageExists reports whether a language with the given code exists.
+func LanguageExists(db *sql.DB, code string) (bool, error) {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM languages WHERE code = ?", code).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check language: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetLanguage retrieves a language by code. It returns nil, nil when it does not exist.
+func GetLanguage(db *sql.DB, code string) (*models.Language, error) {
+	var language models.Language
+	err := db.QueryRow("SELECT code, name FROM languages WHERE code = ?", code).Scan(&language.Code, &language.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Language not found
+		}
+		return nil, fmt.Errorf("failed to scan language row: %w", err)
+	}
+	return &language, nil
+}
+
+// CreateLanguage creates a new language in the database.
+func CreateLanguage(db *sql.DB, language *models.Language) error {
+	if _, err := db.Exec("INSERT INTO languages (code, name) VALUES (?, ?)", language.Code, language.Name); err != nil {
+		return fmt.Errorf("failed to create language: %w", err)
+	}
+	return nil
+}
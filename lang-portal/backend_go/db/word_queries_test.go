@@ -17,7 +17,7 @@ func TestCreateWord(t *testing.T) {
 	defer db.Close()
 
 	mock.ExpectExec("INSERT INTO words").
-		WithArgs("test", "teste", "noun").
+		WithArgs("en", "pt", "test", "teste", "noun", nil).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	id, err := CreateWord(db, &models.Word{
@@ -37,12 +37,35 @@ func TestGetAllWordsIntegration(t *testing.T) {
 	defer db.Close()
 
 	// Insert test data
-	_, err = db.Exec(`INSERT INTO words (english, portuguese, parts) VALUES 
+	_, err = db.Exec(`INSERT INTO words (source_text, target_text, parts) VALUES 
 		('hello', 'olá', 'interjection'),
 		('goodbye', 'adeus', 'interjection')`)
 	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO words (source_language, target_language, source_text, target_text, parts) VALUES
+		('en', 'ja', 'hello', 'こんにちは', 'interjection')`)
+	require.NoError(t, err)
+
+	words, err := GetAllWords(db, WordFilter{})
+	require.NoError(t, err)
+	assert.Len(t, words, 3)
+
+	words, err = GetAllWords(db, WordFilter{SourceLanguage: "en", TargetLanguage: "ja"})
+	require.NoError(t, err)
+	require.Len(t, words, 1)
+	assert.Equal(t, "こんにちは", words[0].TargetText)
+	assert.Equal(t, "こんにちは", words[0].Portuguese, "legacy fields mirror the pair")
 
-	words, err := GetAllWords(db)
+	words, err = GetAllWords(db, WordFilter{TargetLanguage: "pt"})
 	require.NoError(t, err)
 	assert.Len(t, words, 2)
+
+	_, err = CreateWord(db, &models.Word{SourceText: "house", TargetText: "casa", Parts: "noun",
+		Grammar: &models.Grammar{Gender: "feminine", Plural: "casas"}})
+	require.NoError(t, err)
+
+	words, err = GetAllWords(db, WordFilter{PartOfSpeech: "noun", Gender: "feminine"})
+	require.NoError(t, err)
+	require.Len(t, words, 1)
+	require.NotNil(t, words[0].Grammar)
+	assert.Equal(t, "casas", words[0].Grammar.Plural)
 }
@@ -4,13 +4,16 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"math"
+	"time"
 
 	"backend_go/models" // Import your models package
 )
 
-// GetAllWordReviewItems retrieves all word review items from the database.
-func GetAllWordReviewItems(db *sql.DB) ([]models.WordReviewItem, error) {
-	rows, err := db.Query("SELECT id, study_activity_id, word_id, is_correct FROM word_review_items")
+// GetAllWordReviewItems retrieves all word review items belonging to a user.
+func GetAllWordReviewItems(db *sql.DB, userID int) ([]models.WordReviewItem, error) {
+	rows, err := db.Query(`SELECT id, user_id, study_session_id, word_id, is_correct, created_at, response_ms,
+		COALESCE(given_answer, ''), COALESCE(direction, ''), COALESCE(activity_type, '') FROM word_review_items WHERE user_id = ?`, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query word review items: %w", err)
 	}
@@ -19,7 +22,8 @@ func GetAllWordReviewItems(db *sql.DB) ([]models.WordReviewItem, error) {
 	var wordReviewItems []models.WordReviewItem
 	for rows.Next() {
 		var wordReviewItem models.WordReviewItem
-		if err := rows.Scan(&wordReviewItem.ID, &wordReviewItem.StudySessionID, &wordReviewItem.WordID, &wordReviewItem.Correct); err != nil {
+		if err := rows.Scan(&wordReviewItem.ID, &wordReviewItem.UserID, &wordReviewItem.StudySessionID, &wordReviewItem.WordID, &wordReviewItem.Correct, &wordReviewItem.CreatedAt, &wordReviewItem.ResponseMS,
+			&wordReviewItem.GivenAnswer, &wordReviewItem.Direction, &wordReviewItem.ActivityType); err != nil {
 			log.Println("Error scanning word review item row:", err)
 			continue
 		}
@@ -35,10 +39,12 @@ func GetAllWordReviewItems(db *sql.DB) ([]models.WordReviewItem, error) {
 
 // GetWordReviewItemByID retrieves a word review item from the database by its ID.
 func GetWordReviewItemByID(db *sql.DB, id int) (*models.WordReviewItem, error) {
-	row := db.QueryRow("SELECT id, study_activity_id, word_id, is_correct FROM word_review_items WHERE id = ?", id)
+	row := db.QueryRow(`SELECT id, user_id, study_session_id, word_id, is_correct, created_at, response_ms,
+		COALESCE(given_answer, ''), COALESCE(direction, ''), COALESCE(activity_type, '') FROM word_review_items WHERE id = ?`, id)
 
 	var wordReviewItem models.WordReviewItem
-	err := row.Scan(&wordReviewItem.ID, &wordReviewItem.StudySessionID, &wordReviewItem.WordID, &wordReviewItem.Correct)
+	err := row.Scan(&wordReviewItem.ID, &wordReviewItem.UserID, &wordReviewItem.StudySessionID, &wordReviewItem.WordID, &wordReviewItem.Correct, &wordReviewItem.CreatedAt, &wordReviewItem.ResponseMS,
+		&wordReviewItem.GivenAnswer, &wordReviewItem.Direction, &wordReviewItem.ActivityType)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Word review item not found
@@ -49,10 +55,21 @@ func GetWordReviewItemByID(db *sql.DB, id int) (*models.WordReviewItem, error) {
 	return &wordReviewItem, nil
 }
 
-// CreateWordReviewItem creates a new word review item in the database.
+// insertWordReviewItemSQL inserts a review; its user is always the owner of its study session.
+const insertWordReviewItemSQL = `INSERT INTO word_review_items
+		(user_id, study_session_id, word_id, is_correct, created_at, response_ms, given_answer, direction, activity_type)
+		VALUES ((SELECT user_id FROM study_sessions WHERE id = ?1), ?1, ?, ?, ?, ?, ?, ?, ?)`
+
+// CreateWordReviewItem creates a new word review item in the database. An abandoned
+// study session that receives a review becomes active again.
 func CreateWordReviewItem(db *sql.DB, wordReviewItem *models.WordReviewItem) (int, error) {
-	result, err := db.Exec("INSERT INTO word_review_items (study_activity_id, word_id, is_correct) VALUES (?, ?, ?)",
-		wordReviewItem.StudySessionID, wordReviewItem.WordID, wordReviewItem.Correct)
+	if wordReviewItem.CreatedAt.IsZero() {
+		wordReviewItem.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := db.Exec(insertWordReviewItemSQL,
+		wordReviewItem.StudySessionID, wordReviewItem.WordID, wordReviewItem.Correct, wordReviewItem.CreatedAt, wordReviewItem.ResponseMS,
+		nullIfEmpty(wordReviewItem.GivenAnswer), nullIfEmpty(wordReviewItem.Direction), nullIfEmpty(wordReviewItem.ActivityType))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create word review item: %w", err)
 	}
@@ -62,13 +79,73 @@ func CreateWordReviewItem(db *sql.DB, wordReviewItem *models.WordReviewItem) (in
 		return 0, fmt.Errorf("failed to get last insert id: %w", err)
 	}
 
+	if _, err := db.Exec(reopenStudySessionSQL, wordReviewItem.StudySessionID); err != nil {
+		return 0, fmt.Errorf("failed to reopen study session: %w", err)
+	}
+
 	return int(id), nil
 }
 
-// UpdateWordReviewItem updates an existing word review item in the database.
+// CreateWordReviewItems inserts several word review items in a single transaction
+// and returns their IDs in the same order. Nothing is written if any insert fails.
+// Like CreateWordReviewItem, it reopens abandoned sessions that receive reviews.
+func CreateWordReviewItems(db *sql.DB, items []models.WordReviewItem) ([]int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(insertWordReviewItemSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare word review item insert: %w", err)
+	}
+	defer stmt.Close()
+
+	ids := make([]int, 0, len(items))
+	sessionIDs := map[int]bool{}
+	for _, item := range items {
+		if item.CreatedAt.IsZero() {
+			item.CreatedAt = time.Now().UTC()
+		}
+
+		result, err := stmt.Exec(item.StudySessionID, item.WordID, item.Correct, item.CreatedAt, item.ResponseMS,
+			nullIfEmpty(item.GivenAnswer), nullIfEmpty(item.Direction), nullIfEmpty(item.ActivityType))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create word review item: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		ids = append(ids, int(id))
+		sessionIDs[item.StudySessionID] = true
+	}
+
+	for sessionID := range sessionIDs {
+		if _, err := tx.Exec(reopenStudySessionSQL, sessionID); err != nil {
+			return nil, fmt.Errorf("failed to reopen study session: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit word review items: %w", err)
+	}
+
+	return ids, nil
+}
+
+// UpdateWordReviewItem updates an existing word review item owned by wordReviewItem.UserID.
+// The review can only be moved to another session of the same user.
 func UpdateWordReviewItem(db *sql.DB, wordReviewItem *models.WordReviewItem) error {
-	result, err := db.Exec("UPDATE word_review_items SET study_activity_id = ?, word_id = ?, is_correct = ? WHERE id = ?",
-		wordReviewItem.StudySessionID, wordReviewItem.WordID, wordReviewItem.Correct, wordReviewItem.ID)
+	result, err := db.Exec(`UPDATE word_review_items
+		SET study_session_id = ?, word_id = ?, is_correct = ?, response_ms = ?, given_answer = ?, direction = ?, activity_type = ?
+		WHERE id = ? AND user_id = ?
+		AND EXISTS (SELECT 1 FROM study_sessions WHERE id = ? AND user_id = ?)`,
+		wordReviewItem.StudySessionID, wordReviewItem.WordID, wordReviewItem.Correct, wordReviewItem.ResponseMS,
+		nullIfEmpty(wordReviewItem.GivenAnswer), nullIfEmpty(wordReviewItem.Direction), nullIfEmpty(wordReviewItem.ActivityType),
+		wordReviewItem.ID, wordReviewItem.UserID, wordReviewItem.StudySessionID, wordReviewItem.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to update word review item: %w", err)
 	}
@@ -85,9 +162,9 @@ func UpdateWordReviewItem(db *sql.DB, wordReviewItem *models.WordReviewItem) err
 	return nil
 }
 
-// DeleteWordReviewItem deletes a word review item from the database.
-func DeleteWordReviewItem(db *sql.DB, id int) error {
-	result, err := db.Exec("DELETE FROM word_review_items WHERE id = ?", id)
+// DeleteWordReviewItem deletes a word review item owned by a user from the database.
+func DeleteWordReviewItem(db *sql.DB, id, userID int) error {
+	result, err := db.Exec("DELETE FROM word_review_items WHERE id = ? AND user_id = ?", id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete word review item: %w", err)
 	}
@@ -103,3 +180,32 @@ func DeleteWordReviewItem(db *sql.DB, id int) error {
 
 	return nil
 }
+
+// GetStudySessionTotals returns aggregated review counts for a study session.
+func GetStudySessionTotals(db *sql.DB, sessionID int) (*models.StudySessionTotals, error) {
+	row := db.QueryRow(`
+        SELECT
+            COUNT(*),
+            COUNT(DISTINCT word_id),
+            COALESCE(SUM(CASE WHEN is_correct = 1 THEN 1 ELSE 0 END), 0),
+            COALESCE(SUM(CASE WHEN is_correct = 0 THEN 1 ELSE 0 END), 0)
+        FROM word_review_items
+        WHERE study_session_id = ?`, sessionID)
+
+	totals := models.StudySessionTotals{StudySessionID: sessionID}
+	err := row.Scan(&totals.ReviewCount, &totals.TotalWords, &totals.CorrectCount, &totals.IncorrectCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan study session totals: %w", err)
+	}
+
+	if totals.ReviewCount > 0 {
+		totals.SuccessRate = math.Round(float64(totals.CorrectCount)*1000/float64(totals.ReviewCount)) / 10
+	}
+
+	return &totals, nil
+}
+
+// nullIfEmpty stores empty optional text columns as NULL.
+func nullIfEmpty(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -2,12 +2,26 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 
 	"backend_go/models" // Import your models package
+
+	"github.com/mattn/go-sqlite3"
 )
 
+// ErrAlreadyMember is returned when a word is added to a group it already belongs to.
+var ErrAlreadyMember = errors.New("word is already a member of the group")
+
+// isUniqueViolation reports whether err is a SQLite UNIQUE constraint failure.
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
 // GetAllWordsGroups retrieves all words_groups from the database.
 func GetAllWordsGroups(db *sql.DB) ([]models.WordsGroups, error) {
 	rows, err := db.Query("SELECT id, word_id, group_id FROM words_groups")
@@ -49,11 +63,15 @@ func GetWordsGroupsByID(db *sql.DB, id int) (*models.WordsGroups, error) {
 	return &wordsGroup, nil
 }
 
-// CreateWordsGroups creates a new words_groups in the database.
+// CreateWordsGroups creates a new words_groups in the database. It returns
+// ErrAlreadyMember when the word already belongs to the group.
 func CreateWordsGroups(db *sql.DB, wordsGroup *models.WordsGroups) (int, error) {
 	result, err := db.Exec("INSERT INTO words_groups (word_id, group_id) VALUES (?, ?)",
 		wordsGroup.WordID, wordsGroup.GroupID)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, ErrAlreadyMember
+		}
 		return 0, fmt.Errorf("failed to create words_groups: %w", err)
 	}
 
@@ -65,11 +83,15 @@ func CreateWordsGroups(db *sql.DB, wordsGroup *models.WordsGroups) (int, error)
 	return int(id), nil
 }
 
-// UpdateWordsGroups updates an existing words_groups in the database.
+// UpdateWordsGroups updates an existing words_groups in the database. It returns
+// ErrAlreadyMember when another row already links the word to the group.
 func UpdateWordsGroups(db *sql.DB, wordsGroup *models.WordsGroups) error {
 	result, err := db.Exec("UPDATE words_groups SET word_id = ?, group_id = ? WHERE id = ?",
 		wordsGroup.WordID, wordsGroup.GroupID, wordsGroup.ID)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyMember
+		}
 		return fmt.Errorf("failed to update words_groups: %w", err)
 	}
 
@@ -103,3 +125,134 @@ func DeleteWordsGroups(db *sql.DB, id int) error {
 
 	return nil
 }
+
+// GetGroupWordIDs returns the set of word IDs that belong to a group, evaluating
+// smart groups for scope.
+func GetGroupWordIDs(db *sql.DB, groupID int, scope GroupScope) (map[int]bool, error) {
+	members, args, err := groupMembers(db, groupID, scope)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(members, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query group words: %w", err)
+	}
+	defer rows.Close()
+
+	wordIDs := make(map[int]bool)
+	for rows.Next() {
+		var wordID int
+		if err := rows.Scan(&wordID); err != nil {
+			return nil, fmt.Errorf("failed to scan group word id: %w", err)
+		}
+		wordIDs[wordID] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group word rows: %w", err)
+	}
+
+	return wordIDs, nil
+}
+
+// SetGroupWords replaces the words of a group with wordIDs in one transaction.
+// Only the difference is written: memberships that stay are left untouched, so
+// their IDs survive. It returns how many words were added and removed.
+func SetGroupWords(db *sql.DB, groupID int, wordIDs []int) (added, removed int, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT word_id FROM words_groups WHERE group_id = ?", groupID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query group words: %w", err)
+	}
+	current := make(map[int]bool)
+	for rows.Next() {
+		var wordID int
+		if err := rows.Scan(&wordID); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to scan group word id: %w", err)
+		}
+		current[wordID] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("error iterating group word rows: %w", err)
+	}
+
+	wanted := make(map[int]bool, len(wordIDs))
+	for _, wordID := range wordIDs {
+		if wanted[wordID] {
+			continue
+		}
+		wanted[wordID] = true
+		if current[wordID] {
+			continue
+		}
+		if _, err := tx.Exec("INSERT INTO words_groups (word_id, group_id) VALUES (?, ?)", wordID, groupID); err != nil {
+			return 0, 0, fmt.Errorf("failed to add word to group: %w", err)
+		}
+		added++
+	}
+
+	for wordID := range current {
+		if wanted[wordID] {
+			continue
+		}
+		if _, err := tx.Exec("DELETE FROM words_groups WHERE word_id = ? AND group_id = ?", wordID, groupID); err != nil {
+			return 0, 0, fmt.Errorf("failed to remove word from group: %w", err)
+		}
+		removed++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit group words: %w", err)
+	}
+
+	return added, removed, nil
+}
+
+// GetMissingWordIDs returns, in ascending order, the IDs in wordIDs that do not exist.
+func GetMissingWordIDs(db *sql.DB, wordIDs []int) ([]int, error) {
+	missing := []int{}
+	if len(wordIDs) == 0 {
+		return missing, nil
+	}
+
+	placeholders := make([]string, len(wordIDs))
+	args := make([]interface{}, len(wordIDs))
+	for i, id := range wordIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	rows, err := db.Query("SELECT id FROM words WHERE id IN ("+strings.Join(placeholders, ", ")+")", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query words: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan word id: %w", err)
+		}
+		found[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating word rows: %w", err)
+	}
+
+	for _, id := range wordIDs {
+		if !found[id] {
+			found[id] = true // Report each missing ID once
+			missing = append(missing, id)
+		}
+	}
+	sort.Ints(missing)
+	return missing, nil
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend_go/models"
+)
+
+// ErrDuplicateStatement is returned when a statement ID has already been stored.
+var ErrDuplicateStatement = errors.New("statement already exists")
+
+// XAPIStatementFilter narrows the reviews returned by GetXAPIStatements.
+// Zero values are ignored.
+type XAPIStatementFilter struct {
+	UserID         int
+	StatementID    string
+	ReviewID       int
+	WordID         int
+	StudySessionID int
+	Since          time.Time
+	Until          time.Time
+	Limit          int
+	Ascending      bool
+}
+
+// CreateXAPIStatements records each statement's word review item and statement ID
+// in a single transaction. Nothing is written if any statement ID already exists.
+// As with other reviews, an abandoned study session becomes active again.
+func CreateXAPIStatements(db *sql.DB, records []models.XAPIStatementRecord) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, record := range records {
+		var exists int
+		err := tx.QueryRow("SELECT COUNT(*) FROM xapi_statements WHERE id = ?", record.StatementID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check statement id: %w", err)
+		}
+		if exists > 0 {
+			return fmt.Errorf("statement %s: %w", record.StatementID, ErrDuplicateStatement)
+		}
+
+		result, err := tx.Exec(insertWordReviewItemSQL,
+			record.Review.StudySessionID, record.Review.WordID, record.Review.Correct, record.Review.CreatedAt,
+			record.Review.ResponseMS, nullIfEmpty(record.Review.GivenAnswer), nullIfEmpty(record.Review.Direction),
+			nullIfEmpty(record.Review.ActivityType))
+		if err != nil {
+			return fmt.Errorf("failed to create word review item: %w", err)
+		}
+
+		reviewID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+
+		_, err = tx.Exec("INSERT INTO xapi_statements (id, word_review_item_id, actor) VALUES (?, ?, ?)",
+			record.StatementID, reviewID, record.Actor)
+		if err != nil {
+			return fmt.Errorf("failed to create xapi statement: %w", err)
+		}
+
+		if _, err := tx.Exec(reopenStudySessionSQL, record.Review.StudySessionID); err != nil {
+			return fmt.Errorf("failed to reopen study session: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit xapi statements: %w", err)
+	}
+
+	return nil
+}
+
+// GetXAPIStatements retrieves word review items, with any stored statement ID and
+// actor, matching the filter. Newest reviews come first unless Ascending is set.
+func GetXAPIStatements(db *sql.DB, filter XAPIStatementFilter) ([]models.XAPIStatementRecord, error) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.UserID != 0 {
+		conditions = append(conditions, "wri.user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.StatementID != "" {
+		conditions = append(conditions, "xs.id = ?")
+		args = append(args, filter.StatementID)
+	}
+	if filter.ReviewID != 0 {
+		conditions = append(conditions, "wri.id = ?")
+		args = append(args, filter.ReviewID)
+	}
+	if filter.WordID != 0 {
+		conditions = append(conditions, "wri.word_id = ?")
+		args = append(args, filter.WordID)
+	}
+	if filter.StudySessionID != 0 {
+		conditions = append(conditions, "wri.study_session_id = ?")
+		args = append(args, filter.StudySessionID)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "julianday(wri.created_at) > julianday(?)")
+		args = append(args, filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "julianday(wri.created_at) <= julianday(?)")
+		args = append(args, filter.Until.UTC())
+	}
+
+	query := `
+        SELECT wri.id, wri.user_id, wri.word_id, wri.study_session_id, wri.is_correct, wri.created_at,
+               wri.response_ms, COALESCE(wri.given_answer, ''),
+               COALESCE(xs.id, ''), COALESCE(xs.actor, ''),
+               w.source_language, w.target_language, w.source_text, w.target_text
+        FROM word_review_items wri
+        JOIN words w ON w.id = wri.word_id
+        LEFT JOIN xapi_statements xs ON xs.word_review_item_id = wri.id`
+	if len(conditions) > 0 {
+		query += "\n        WHERE " + strings.Join(conditions, " AND ")
+	}
+	if filter.Ascending {
+		query += "\n        ORDER BY wri.created_at ASC, wri.id ASC"
+	} else {
+		query += "\n        ORDER BY wri.created_at DESC, wri.id DESC"
+	}
+	if filter.Limit > 0 {
+		query += "\n        LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query xapi statements: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.XAPIStatementRecord
+	for rows.Next() {
+		var record models.XAPIStatementRecord
+		err := rows.Scan(&record.Review.ID, &record.Review.UserID, &record.Review.WordID, &record.Review.StudySessionID,
+			&record.Review.Correct, &record.Review.CreatedAt,
+			&record.Review.ResponseMS, &record.Review.GivenAnswer,
+			&record.StatementID, &record.Actor,
+			&record.SourceLanguage, &record.TargetLanguage, &record.SourceText, &record.TargetText)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan xapi statement row: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating xapi statement rows: %w", err)
+	}
+
+	return records, nil
+}
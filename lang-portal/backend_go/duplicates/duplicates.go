@@ -0,0 +1,133 @@
+// Package duplicates finds words that are probably entered more than once,
+// either verbatim or with small differences in spelling, case or punctuation.
+package duplicates
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"backend_go/models"
+)
+
+// DefaultMaxDistance is the edit distance allowed between fuzzy duplicates.
+const DefaultMaxDistance = 1
+
+// minFuzzyLength keeps short words, where one edit changes the meaning ("sim" and
+// "sem"), out of fuzzy matching.
+const minFuzzyLength = 5
+
+// Reasons two words are reported as duplicates.
+const (
+	ReasonExact      = "exact"       // Same source and target text
+	ReasonSameTarget = "same_target" // Same target text, different source ("thanks", "thank you")
+	ReasonSameSource = "same_source" // Same source text, different target
+	ReasonSimilar    = "similar"     // Both texts within the edit distance
+)
+
+// accentFolder removes the diacritics of the supported Latin-script languages.
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// Normalize lowercases text, removes accents, turns punctuation into spaces and
+// collapses whitespace, so "Olá!" and "ola", or "guarda-chuva" and "guarda chuva",
+// compare equal.
+func Normalize(text string) string {
+	folded := accentFolder.Replace(strings.ToLower(text))
+	kept := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, folded)
+	return strings.Join(strings.Fields(kept), " ")
+}
+
+// Distance returns the Levenshtein edit distance between a and b in runes.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// Find compares every two words with the same language pair and returns the
+// pairs that look like duplicates, lower ID first, in word ID order. Texts are
+// compared after Normalize; fuzzy matches allow maxDistance edits in each text.
+func Find(words []models.Word, maxDistance int) []models.DuplicatePair {
+	type normalized struct {
+		source, target string
+	}
+	texts := make([]normalized, len(words))
+	for i, word := range words {
+		texts[i] = normalized{Normalize(word.SourceText), Normalize(word.TargetText)}
+	}
+
+	pairs := []models.DuplicatePair{}
+	for i := range words {
+		for j := i + 1; j < len(words); j++ {
+			a, b := words[i], words[j]
+			if a.SourceLanguage != b.SourceLanguage || a.TargetLanguage != b.TargetLanguage {
+				continue
+			}
+
+			sameSource := texts[i].source == texts[j].source
+			sameTarget := texts[i].target == texts[j].target
+			pair := models.DuplicatePair{Word: a, Duplicate: b}
+			switch {
+			case sameSource && sameTarget:
+				pair.Reason = ReasonExact
+			case sameTarget:
+				pair.Reason = ReasonSameTarget
+				pair.Distance = Distance(texts[i].source, texts[j].source)
+			case sameSource:
+				pair.Reason = ReasonSameSource
+				pair.Distance = Distance(texts[i].target, texts[j].target)
+			default:
+				if !fuzzy(texts[i].source, texts[j].source, maxDistance) || !fuzzy(texts[i].target, texts[j].target, maxDistance) {
+					continue
+				}
+				pair.Reason = ReasonSimilar
+				pair.Distance = Distance(texts[i].source, texts[j].source) + Distance(texts[i].target, texts[j].target)
+			}
+			if a.ID > b.ID {
+				pair.Word, pair.Duplicate = b, a
+			}
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
+
+// fuzzy reports whether two normalized texts are long enough to compare fuzzily
+// and within maxDistance edits of each other.
+func fuzzy(a, b string, maxDistance int) bool {
+	if a == b {
+		return true
+	}
+	if utf8.RuneCountInString(a) < minFuzzyLength || utf8.RuneCountInString(b) < minFuzzyLength {
+		return false
+	}
+	return Distance(a, b) <= maxDistance
+}
@@ -0,0 +1,56 @@
+package duplicates
+
+import (
+	"testing"
+
+	"backend_go/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "ola", Normalize(" Olá! "))
+	assert.Equal(t, "thank you", Normalize("Thank   you."))
+	assert.Equal(t, "guarda chuva", Normalize("guarda-chuva"))
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, Distance("obrigado", "obrigado"))
+	assert.Equal(t, 1, Distance("obrigado", "obrigada"))
+	assert.Equal(t, 3, Distance("kitten", "sitting"))
+	assert.Equal(t, 4, Distance("", "casa"))
+	assert.Equal(t, 1, Distance("maçã", "maça"))
+}
+
+func TestFind(t *testing.T) {
+	word := func(id int, source, target string) models.Word {
+		return models.Word{ID: id, SourceLanguage: "en", TargetLanguage: "pt", SourceText: source, TargetText: target}
+	}
+	words := []models.Word{
+		word(1, "thank you", "obrigado"),
+		word(2, "hello", "olá"),
+		word(3, "Hello!", "Ola"),
+		word(4, "thanks", "obrigado"),
+		word(5, "yes", "sim"),
+		word(6, "no", "sem"),
+		word(7, "kitchen", "cozinha"),
+		word(8, "kitchen", "cosinha"),
+		word(9, "kitchens", "cozinhas"),
+		{ID: 10, SourceLanguage: "en", TargetLanguage: "es", SourceText: "hello", TargetText: "hola"},
+	}
+
+	pairs := Find(words, DefaultMaxDistance)
+	found := map[[2]int]string{}
+	for _, pair := range pairs {
+		require.Less(t, pair.Word.ID, pair.Duplicate.ID)
+		found[[2]int{pair.Word.ID, pair.Duplicate.ID}] = pair.Reason
+	}
+
+	assert.Equal(t, map[[2]int]string{
+		{1, 4}: ReasonSameTarget,
+		{2, 3}: ReasonExact,
+		{7, 8}: ReasonSameSource,
+		{7, 9}: ReasonSimilar,
+	}, found)
+}
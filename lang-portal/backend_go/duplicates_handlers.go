@@ -0,0 +1,82 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"backend_go/db"
+	"backend_go/duplicates"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxDuplicateDistance caps the max_distance parameter of GET /api/words/duplicates;
+// larger distances mostly report unrelated words.
+const maxDuplicateDistance = 3
+
+// getWordDuplicatesHandler handles the GET /api/words/duplicates endpoint.
+// It returns pairs of words that look like the same entry, optionally limited by
+// the same filters as GET /api/words.
+func getWordDuplicatesHandler(c *gin.Context) {
+	maxDistance, err := strconv.Atoi(c.DefaultQuery("max_distance", strconv.Itoa(duplicates.DefaultMaxDistance)))
+	if err != nil || maxDistance < 0 || maxDistance > maxDuplicateDistance {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_distance must be between 0 and " + strconv.Itoa(maxDuplicateDistance)})
+		return
+	}
+
+	words, err := db.GetAllWords(dbConn, wordFilterParam(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch words from database"})
+		log.Println("Failed to fetch words:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": duplicates.Find(words, maxDistance)})
+}
+
+// mergeWordHandler handles the POST /api/words/:id/merge endpoint.
+// The word named in the body is merged into the word in the path: its groups,
+// review history, sentences and media move to the survivor and it is deleted.
+func mergeWordHandler(c *gin.Context) {
+	var request struct {
+		DuplicateID int `json:"duplicate_id"`
+	}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	survivor := wordParam(c)
+	if survivor == nil {
+		return
+	}
+	if request.DuplicateID == survivor.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A word cannot be merged into itself"})
+		return
+	}
+
+	duplicate, err := db.GetWordByID(dbConn, request.DuplicateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word from database"})
+		log.Println("Failed to fetch word:", err)
+		return
+	}
+	if duplicate == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Duplicate word not found"})
+		return
+	}
+	if duplicate.SourceLanguage != survivor.SourceLanguage || duplicate.TargetLanguage != survivor.TargetLanguage {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only words with the same language pair can be merged"})
+		return
+	}
+
+	result, err := db.MergeWords(dbConn, survivor.ID, duplicate.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge words"})
+		log.Println("Failed to merge words:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Words merged successfully", "item": result})
+}
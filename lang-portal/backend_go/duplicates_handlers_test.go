@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	dbpkg "backend_go/db"
+	"backend_go/duplicates"
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordDuplicatesAndMerge(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Word 3 is "thank you" / "obrigado"
+	thanks, err := dbpkg.CreateWord(db, &models.Word{SourceText: "thanks", TargetText: "Obrigado!", Parts: "phrase"})
+	require.NoError(t, err)
+	spanish, err := dbpkg.CreateWord(db, &models.Word{SourceLanguage: "en", TargetLanguage: "es", SourceText: "thanks", TargetText: "gracias"})
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		INSERT INTO words_groups (word_id, group_id) VALUES (3, 1), (?, 1), (?, 2);
+		INSERT INTO study_sessions (group_id, created_at, study_activity_id) VALUES (1, '2025-01-01 10:00:00', 1);
+		INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at) VALUES
+			(3, 1, 1, '2025-01-01 10:00:00'), (?, 1, 0, '2025-01-01 10:01:00'), (?, 1, 1, '2025-01-01 10:02:00');
+		INSERT INTO sentences (word_id, text, origin, created_at) VALUES
+			(3, 'Obrigado pela ajuda.', 'manual', '2025-01-01'), (?, 'Obrigado pela ajuda.', 'manual', '2025-01-01'),
+			(?, 'Muito obrigado!', 'manual', '2025-01-01')`,
+		thanks, thanks, thanks, thanks, thanks, thanks)
+	require.NoError(t, err)
+
+	t.Run("Duplicates are found", func(t *testing.T) {
+		resp := request("GET", "/api/words/duplicates", "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var body struct{ Items []models.DuplicatePair }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Len(t, body.Items, 1)
+		assert.Equal(t, 3, body.Items[0].Word.ID)
+		assert.Equal(t, thanks, body.Items[0].Duplicate.ID)
+		assert.Equal(t, duplicates.ReasonSameTarget, body.Items[0].Reason)
+
+		resp = request("GET", "/api/words/duplicates?target_language=es", "")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Empty(t, body.Items)
+
+		assert.Equal(t, http.StatusBadRequest, request("GET", "/api/words/duplicates?max_distance=9", "").Code)
+	})
+
+	t.Run("Invalid merges are refused", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/words/3/merge", `{"duplicate_id": 3}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/words/3/merge", `{"duplicate_id": `+strconv.Itoa(spanish)+`}`).Code)
+		assert.Equal(t, http.StatusNotFound, request("POST", "/api/words/3/merge", `{"duplicate_id": 999}`).Code)
+		assert.Equal(t, http.StatusNotFound, request("POST", "/api/words/999/merge", `{"duplicate_id": 3}`).Code)
+	})
+
+	t.Run("Merging moves the duplicate's history to the survivor", func(t *testing.T) {
+		resp := request("POST", "/api/words/3/merge", `{"duplicate_id": `+strconv.Itoa(thanks)+`}`)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var body struct{ Item models.MergeResult }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, models.MergeResult{Groups: 1, Reviews: 2, Sentences: 1}, body.Item)
+
+		word, err := dbpkg.GetWordByID(db, thanks)
+		require.NoError(t, err)
+		assert.Nil(t, word)
+
+		count := func(query string, args ...interface{}) int {
+			var n int
+			require.NoError(t, db.QueryRow(query, args...).Scan(&n))
+			return n
+		}
+		assert.Equal(t, 0, count("SELECT COUNT(*) FROM words_groups WHERE word_id = ?", thanks))
+		assert.Equal(t, 2, count("SELECT COUNT(*) FROM words_groups WHERE word_id = 3"))
+		assert.Equal(t, 3, count("SELECT COUNT(*) FROM word_review_items WHERE word_id = 3"))
+		assert.Equal(t, 2, count("SELECT COUNT(*) FROM sentences WHERE word_id = 3"))
+		assert.Equal(t, 0, count("SELECT COUNT(*) FROM sentences WHERE word_id = ?", thanks))
+	})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend_go/db"
+	"backend_go/models"
+	"backend_go/sessiontoken"
+
+	"github.com/gin-gonic/gin"
+)
+
+// externalReviewsRequest is the payload an external learning app posts with its results.
+type externalReviewsRequest struct {
+	Reviews []struct {
+		WordID       int        `json:"word_id"`
+		Correct      bool       `json:"correct"`
+		AnsweredAt   *time.Time `json:"answered_at"`
+		ResponseMS   *int       `json:"response_ms"`
+		GivenAnswer  string     `json:"given_answer"`
+		Direction    string     `json:"direction"`
+		ActivityType string     `json:"activity_type"`
+	} `json:"reviews"`
+}
+
+// createStudySessionTokenHandler handles the POST /api/study_sessions/:id/token endpoint.
+// It re-issues a session token, e.g. when a launched app's token has expired.
+func createStudySessionTokenHandler(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid study session ID"})
+		return
+	}
+
+	session, err := db.GetStudySessionByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session"})
+		log.Println("Failed to fetch study session:", err)
+		return
+	}
+	if session == nil || session.UserID != currentUser(c).ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
+		return
+	}
+
+	token, expiresAt, err := tokenSigner.Issue(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue session token"})
+		log.Println("Failed to issue session token:", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"study_session_id": id,
+		"token":            token,
+		"token_expires_at": expiresAt,
+	})
+}
+
+// createExternalReviewsHandler handles the POST /api/external/sessions/:token/reviews endpoint.
+// External learning apps use it to record a batch of word results for the session the token is scoped to.
+func createExternalReviewsHandler(c *gin.Context) {
+	claims, err := tokenSigner.Verify(c.Param("token"))
+	if err != nil {
+		if errors.Is(err, sessiontoken.ErrExpiredToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session token expired"})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid session token"})
+		return
+	}
+
+	var request externalReviewsRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(request.Reviews) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No reviews provided"})
+		return
+	}
+
+	session, err := db.GetStudySessionByID(dbConn, claims.SessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session"})
+		log.Println("Failed to fetch study session:", err)
+		return
+	}
+	if session == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
+		return
+	}
+
+	// Only words from the session's group may be reviewed
+	groupWordIDs, err := db.GetGroupWordIDs(dbConn, session.GroupID, db.SessionScope(session))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group words"})
+		log.Println("Failed to fetch group words:", err)
+		return
+	}
+
+	items := make([]models.WordReviewItem, 0, len(request.Reviews))
+	invalidWordIDs := []int{}
+	for _, review := range request.Reviews {
+		if !groupWordIDs[review.WordID] {
+			invalidWordIDs = append(invalidWordIDs, review.WordID)
+			continue
+		}
+		if !normalizeReviewDirection(&review.Direction) || (review.ResponseMS != nil && *review.ResponseMS < 0) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review details", "word_id": review.WordID})
+			return
+		}
+
+		item := models.WordReviewItem{
+			WordID:         review.WordID,
+			StudySessionID: session.ID,
+			Correct:        review.Correct,
+			CreatedAt:      time.Now().UTC(),
+			ResponseMS:     review.ResponseMS,
+			GivenAnswer:    review.GivenAnswer,
+			Direction:      review.Direction,
+			ActivityType:   review.ActivityType,
+		}
+		if review.AnsweredAt != nil {
+			item.CreatedAt = review.AnsweredAt.UTC()
+		}
+		items = append(items, item)
+	}
+
+	if len(invalidWordIDs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":            "Words do not belong to the study session's group",
+			"invalid_word_ids": invalidWordIDs,
+		})
+		return
+	}
+
+	ids, err := db.CreateWordReviewItems(dbConn, items)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record word reviews"})
+		log.Println("Failed to record word reviews:", err)
+		return
+	}
+
+	reviewsRecorded.Add(float64(len(ids)))
+	for i := range items {
+		items[i].ID = ids[i]
+		items[i].UserID = session.UserID
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"study_session_id": session.ID,
+		"items":            items,
+	})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend_go/sessiontoken"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalReviews(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	dbConn = db
+	appConfig.OpenMode = true
+	tokenSigner = sessiontoken.NewSigner([]byte("test-secret"), time.Hour)
+
+	_, err = db.Exec(`
+		INSERT INTO groups (id, name, description) VALUES (1, 'Basics', '');
+		INSERT INTO words (id, source_text, target_text, parts) VALUES
+			(1, 'hello', 'olá', 'interjection'),
+			(2, 'goodbye', 'adeus', 'interjection');
+		INSERT INTO words_groups (word_id, group_id) VALUES (1, 1);
+		INSERT INTO study_sessions (id, group_id, created_at, study_activity_id) VALUES (1, 1, CURRENT_TIMESTAMP, 1);`)
+	require.NoError(t, err)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	token, _, err := tokenSigner.Issue(1)
+	require.NoError(t, err)
+
+	post := func(token string, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/api/external/sessions/"+token+"/reviews", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("Records reviews for words in the session's group", func(t *testing.T) {
+		resp := post(token, `{"reviews": [{"word_id": 1, "correct": true}, {"word_id": 1, "correct": false}]}`)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM word_review_items WHERE study_session_id = 1").Scan(&count))
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("Rejects words outside the group", func(t *testing.T) {
+		resp := post(token, `{"reviews": [{"word_id": 1, "correct": true}, {"word_id": 2, "correct": true}]}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+		var body struct {
+			InvalidWordIDs []int `json:"invalid_word_ids"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, []int{2}, body.InvalidWordIDs)
+	})
+
+	t.Run("Rejects an invalid token", func(t *testing.T) {
+		resp := post("bogus", `{"reviews": [{"word_id": 1, "correct": true}]}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"backend_go/db"
+	"backend_go/llm"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Vocabulary generation limits for POST /api/groups/:id/generate.
+const (
+	defaultGeneratedWords = 10
+	maxGeneratedWords     = 50
+)
+
+// generateWordsRequest is the body of POST /api/groups/:id/generate. Every field
+// is optional; the theme defaults to the group's name and description.
+type generateWordsRequest struct {
+	Count          int    `json:"count"`
+	Theme          string `json:"theme"`
+	SourceLanguage string `json:"source_language"`
+	TargetLanguage string `json:"target_language"`
+}
+
+// acceptedWord reports how one accepted draft word was handled.
+type acceptedWord struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	WordID int    `json:"word_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// groupParam loads the group named by the :id parameter. It writes an error
+// response and returns nil when the group is not found.
+func groupParam(c *gin.Context) *models.Group {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return nil
+	}
+
+	group, err := db.GetGroupByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group from database"})
+		log.Println("Failed to fetch group:", err)
+		return nil
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return nil
+	}
+	return group
+}
+
+// languageParam loads a language by code for a request field. It writes an error
+// response and returns nil when the language is unknown.
+func languageParam(c *gin.Context, code string) *models.Language {
+	language, err := db.GetLanguage(dbConn, code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check language"})
+		log.Println("Failed to check language:", err)
+		return nil
+	}
+	if language == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown language: " + code})
+		return nil
+	}
+	return language
+}
+
+// generateGroupWordsHandler handles the POST /api/groups/:id/generate endpoint.
+// It asks the configured LLM for words on the group's theme and returns them as
+// a draft; nothing is stored until the draft is accepted.
+func generateGroupWordsHandler(c *gin.Context) {
+	if llmClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Vocabulary generation is not configured"})
+		return
+	}
+
+	request := generateWordsRequest{
+		Count:          defaultGeneratedWords,
+		SourceLanguage: models.DefaultSourceLanguage,
+		TargetLanguage: models.DefaultTargetLanguage,
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	if request.Count < 1 || request.Count > maxGeneratedWords {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "count must be between 1 and " + strconv.Itoa(maxGeneratedWords)})
+		return
+	}
+	if request.SourceLanguage == request.TargetLanguage {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source_language and target_language must differ"})
+		return
+	}
+
+	group := groupParam(c)
+	if group == nil || !staticGroup(c, group) {
+		return
+	}
+	source := languageParam(c, request.SourceLanguage)
+	if source == nil {
+		return
+	}
+	target := languageParam(c, request.TargetLanguage)
+	if target == nil {
+		return
+	}
+
+	if request.Theme == "" {
+		request.Theme = group.Name
+		if group.Description != "" {
+			request.Theme += ": " + group.Description
+		}
+	}
+
+	// Ask the model to skip words the group already has
+	existing, err := db.GetGroupWords(dbConn, group.ID, requestScope(c), db.WordFilter{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group words from database"})
+		log.Println("Failed to fetch group words:", err)
+		return
+	}
+	exclude := make([]string, len(existing))
+	for i, word := range existing {
+		exclude[i] = word.TargetText
+	}
+
+	words, err := llm.ProposeWords(c.Request.Context(), llmClient, llm.VocabularyRequest{
+		Theme:          request.Theme,
+		Count:          request.Count,
+		SourceLanguage: *source,
+		TargetLanguage: *target,
+		Exclude:        exclude,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to generate words"})
+		log.Println("Failed to generate words:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": words, "theme": request.Theme})
+}
+
+// acceptGroupWordsHandler handles the POST /api/groups/:id/generate/accept endpoint.
+// It creates the accepted draft words, as edited by the reviewer, and adds them to
+// the group. Invalid words are reported per item and skipped.
+func acceptGroupWordsHandler(c *gin.Context) {
+	var words []models.Word
+	if err := c.BindJSON(&words); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(words) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No words provided"})
+		return
+	}
+	if len(words) > maxGeneratedWords {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many words in one batch", "max_batch_size": maxGeneratedWords})
+		return
+	}
+
+	group := groupParam(c)
+	if group == nil || !staticGroup(c, group) {
+		return
+	}
+
+	results := make([]acceptedWord, len(words))
+	created := 0
+	for i := range words {
+		results[i] = acceptedWord{Index: i, Status: "rejected"}
+
+		problem, err := checkWord(&words[i])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check language"})
+			log.Println("Failed to check language:", err)
+			return
+		}
+		if problem != "" {
+			results[i].Error = problem
+			continue
+		}
+
+		wordID, err := db.CreateWord(dbConn, &words[i])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create word in database"})
+			log.Println("Failed to create word:", err)
+			return
+		}
+		if _, err := db.CreateWordsGroups(dbConn, &models.WordsGroups{WordID: wordID, GroupID: group.ID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add word to group"})
+			log.Println("Failed to create words_groups:", err)
+			return
+		}
+
+		results[i].Status = "created"
+		results[i].WordID = wordID
+		created++
+	}
+
+	status := http.StatusCreated
+	if created == 0 {
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{"items": results, "created": created})
+}
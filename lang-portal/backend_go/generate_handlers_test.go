@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dbpkg "backend_go/db"
+	"backend_go/llm"
+	"backend_go/llm/llmtest"
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateGroupWords(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	llmClient = nil
+	assert.Equal(t, http.StatusServiceUnavailable, request("POST", "/api/groups/2/generate", "").Code)
+
+	server := llmtest.NewServer(llmtest.Reply(`[
+		{"source_text": "ticket", "target_text": "bilhete", "parts": "noun"},
+		{"source_text": "train", "target_text": "comboio", "parts": "noun"},
+		{"source_text": "airport", "target_text": "aeroporto", "parts": "noun"}
+	]`))
+	defer server.Close()
+	llmClient = llm.NewClient(server.URL, "test-model", "", time.Second)
+	defer func() { llmClient = nil }()
+
+	_, err = db.Exec("INSERT INTO words_groups (word_id, group_id) VALUES (1, 2)")
+	require.NoError(t, err)
+	groupWord, err := dbpkg.GetWordByID(db, 1)
+	require.NoError(t, err)
+
+	t.Run("Draft is proposed for the group's theme without storing anything", func(t *testing.T) {
+		resp := request("POST", "/api/groups/2/generate", `{"count": 2}`)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		var body struct {
+			Items []models.Word
+			Theme string
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, "Travel Phrases: Useful phrases for traveling in Portuguese-speaking countries", body.Theme)
+		require.Len(t, body.Items, 2)
+		assert.Equal(t, "bilhete", body.Items[0].TargetText)
+		assert.Equal(t, "pt", body.Items[0].TargetLanguage)
+		assert.Zero(t, body.Items[0].ID)
+
+		requests := server.Requests()
+		prompt := requests[len(requests)-1].Messages[1].Content
+		assert.True(t, strings.Contains(prompt, "Do not include: "+groupWord.TargetText), prompt)
+
+		words, err := dbpkg.GetGroupWords(db, 2, dbpkg.GroupScope{}, dbpkg.WordFilter{})
+		require.NoError(t, err)
+		assert.Len(t, words, 1)
+	})
+
+	t.Run("Invalid generation requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/groups/2/generate", `{"count": 500}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/groups/2/generate", `{"target_language": "xx"}`).Code)
+		assert.Equal(t, http.StatusNotFound, request("POST", "/api/groups/999/generate", "").Code)
+	})
+
+	t.Run("Accepted words are created and added to the group", func(t *testing.T) {
+		resp := request("POST", "/api/groups/2/generate/accept", `[
+			{"source_text": "ticket", "target_text": "bilhete", "parts": "noun", "source_language": "en", "target_language": "pt"},
+			{"english": "train", "portuguese": "comboio", "parts": "noun"},
+			{"source_text": "airport", "target_text": "", "parts": "noun"}
+		]`)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+		var body struct {
+			Items   []acceptedWord
+			Created int
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, 2, body.Created)
+		assert.Equal(t, "created", body.Items[1].Status)
+		assert.Equal(t, "rejected", body.Items[2].Status)
+		assert.NotEmpty(t, body.Items[2].Error)
+
+		words, err := dbpkg.GetGroupWords(db, 2, dbpkg.GroupScope{}, dbpkg.WordFilter{})
+		require.NoError(t, err)
+		require.Len(t, words, 3)
+		assert.Equal(t, "comboio", words[2].TargetText)
+
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/groups/2/generate/accept", `[{"source_text": "x"}]`).Code)
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/groups/2/generate/accept", `[]`).Code)
+	})
+}
@@ -0,0 +1,169 @@
+package goals
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend_go/db"
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyCountsUsesLocalDayBoundaries(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	// 01:30 UTC on the 2nd is still the evening of the 1st in São Paulo
+	timestamps := []time.Time{
+		time.Date(2025, 2, 2, 1, 30, 0, 0, time.UTC),
+		time.Date(2025, 2, 2, 15, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, map[string]int{"2025-02-01": 1, "2025-02-02": 1}, DailyCounts(timestamps, saoPaulo))
+	assert.Equal(t, map[string]int{"2025-02-02": 2}, DailyCounts(timestamps, time.UTC))
+}
+
+func TestDailyBucketCountsUsesLocalDayBoundaries(t *testing.T) {
+	kathmandu, err := time.LoadLocation("Asia/Kathmandu")
+	require.NoError(t, err)
+
+	// Kathmandu is UTC+05:45, so its midnight falls on a quarter hour boundary
+	buckets := []models.ReviewBucket{
+		{Start: time.Date(2025, 2, 1, 18, 0, 0, 0, time.UTC), Count: 2},
+		{Start: time.Date(2025, 2, 1, 18, 15, 0, 0, time.UTC), Count: 3},
+	}
+
+	assert.Equal(t, map[string]int{"2025-02-01": 2, "2025-02-02": 3}, DailyBucketCounts(buckets, kathmandu))
+	assert.Equal(t, map[string]int{"2025-02-01": 5}, DailyBucketCounts(buckets, time.UTC))
+}
+
+func TestStreaks(t *testing.T) {
+	counts := map[string]int{
+		"2025-01-01": 3, "2025-01-02": 1, "2025-01-03": 2, "2025-01-04": 5,
+		"2025-01-10": 1, "2025-01-11": 4,
+	}
+
+	current, longest := Streaks(counts, time.Date(2025, 1, 12, 8, 0, 0, 0, time.UTC))
+	assert.Equal(t, 2, current, "streak is still alive when today has no reviews yet")
+	assert.Equal(t, 4, longest)
+
+	current, _ = Streaks(counts, time.Date(2025, 1, 13, 8, 0, 0, 0, time.UTC))
+	assert.Equal(t, 0, current)
+
+	current, longest = Streaks(map[string]int{}, time.Now())
+	assert.Zero(t, current)
+	assert.Zero(t, longest)
+}
+
+func TestProgress(t *testing.T) {
+	conn, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Exec(`
+		UPDATE goals SET daily_review_target = 3, time_zone = 'America/Sao_Paulo';
+		INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at) VALUES
+			(1, 1, 1, '2025-01-20 12:00:00'),
+			(1, 1, 1, '2025-02-01 12:00:00'),
+			(1, 1, 1, '2025-02-02 01:30:00'),
+			(1, 1, 0, '2025-02-02 12:00:00')`)
+	require.NoError(t, err)
+	// Reviews stored from Go carry fractional seconds and an offset
+	_, err = conn.Exec("INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at) VALUES (1, 1, 1, ?)",
+		time.Date(2025, 2, 3, 14, 0, 0, 500, time.UTC))
+	require.NoError(t, err)
+
+	goal, err := db.GetGoal(conn, models.DefaultUserID)
+	require.NoError(t, err)
+
+	progress, err := Progress(conn, goal, time.Date(2025, 2, 3, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "2025-02-03", progress.Date)
+	assert.Equal(t, 1, progress.ReviewsToday)
+	assert.Equal(t, 2, progress.Remaining)
+	// 01:30 UTC on the 2nd is the 1st in São Paulo, so the 1st, 2nd and 3rd are all study days
+	assert.Equal(t, 3, progress.CurrentStreak)
+	assert.Equal(t, 3, progress.LongestStreak)
+	require.NotNil(t, progress.LastStudyDate)
+	assert.Equal(t, "2025-02-03", *progress.LastStudyDate)
+}
+
+func TestReminderRecordsMissedDays(t *testing.T) {
+	conn, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Exec(`
+		UPDATE goals SET daily_review_target = 2, time_zone = 'UTC', created_at = '2025-02-01 00:00:00';
+		INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at) VALUES
+			(1, 1, 1, '2025-02-01 10:00:00'),
+			(1, 1, 1, '2025-02-01 10:01:00'),
+			(1, 1, 0, '2025-02-02 10:00:00');
+		INSERT INTO users (id, username, display_name, role, created_at) VALUES (2, 'ana', 'Ana', 'learner', '2025-02-03 00:00:00');
+		INSERT INTO goals (user_id, daily_review_target, time_zone, created_at, updated_at)
+		VALUES (2, 2, 'UTC', '2025-02-03 00:00:00', '2025-02-03 00:00:00');
+		INSERT INTO word_review_items (user_id, word_id, study_session_id, is_correct, created_at) VALUES
+			(2, 1, 2, 1, '2025-02-03 09:00:00'),
+			(2, 1, 2, 1, '2025-02-03 09:01:00')`)
+	require.NoError(t, err)
+
+	var notified []models.GoalEvent
+	reminder := NewReminder(conn, time.Hour, func(event models.GoalEvent) {
+		notified = append(notified, event)
+	})
+	reminder.now = func() time.Time { return time.Date(2025, 2, 4, 12, 0, 0, 0, time.UTC) }
+
+	recorded, err := reminder.Check()
+	require.NoError(t, err)
+	// Ana met her goal on the 3rd; her reviews do not count towards the default learner's
+	require.Len(t, recorded, 2)
+	assert.Equal(t, models.DefaultUserID, recorded[0].UserID)
+	assert.Equal(t, "2025-02-02", recorded[0].Day)
+	assert.Equal(t, 1, recorded[0].ReviewCount)
+	assert.Equal(t, "2025-02-03", recorded[1].Day)
+	assert.Equal(t, recorded, notified)
+
+	// Checking again does not record the same days twice
+	recorded, err = reminder.Check()
+	require.NoError(t, err)
+	assert.Empty(t, recorded)
+}
+
+func TestReminderChecksRemainingGoalsAfterAFailure(t *testing.T) {
+	conn, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The default learner's time zone no longer loads; Ana's goal is still checked
+	_, err = conn.Exec(`
+		UPDATE goals SET daily_review_target = 2, time_zone = 'Nowhere/Atlantis', created_at = '2025-02-01 00:00:00';
+		INSERT INTO users (id, username, display_name, role, created_at) VALUES (2, 'ana', 'Ana', 'learner', '2025-02-03 00:00:00');
+		INSERT INTO goals (user_id, daily_review_target, time_zone, created_at, updated_at)
+		VALUES (2, 2, 'UTC', '2025-02-03 00:00:00', '2025-02-03 00:00:00')`)
+	require.NoError(t, err)
+
+	reminder := NewReminder(conn, time.Hour, func(models.GoalEvent) {})
+	reminder.now = func() time.Time { return time.Date(2025, 2, 4, 12, 0, 0, 0, time.UTC) }
+
+	recorded, err := reminder.Check()
+	assert.ErrorContains(t, err, "Nowhere/Atlantis")
+	require.Len(t, recorded, 1)
+	assert.Equal(t, 2, recorded[0].UserID)
+	assert.Equal(t, "2025-02-03", recorded[0].Day)
+}
+
+func TestReminderRunWithoutInterval(t *testing.T) {
+	conn, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A zero interval falls back to the default instead of panicking
+	NewReminder(conn, 0, nil).Run(ctx)
+}
@@ -0,0 +1,52 @@
+package goals
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend_go/db"
+	"backend_go/models"
+)
+
+// Progress computes today's progress towards the goal and the goal owner's study
+// streaks in the goal's time zone.
+func Progress(conn *sql.DB, goal *models.Goal, now time.Time) (*models.GoalProgress, error) {
+	loc, err := time.LoadLocation(goal.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid goal time zone %q: %w", goal.TimeZone, err)
+	}
+
+	// Streaks span the whole history, so count per quarter hour rather than
+	// loading every review
+	buckets, err := db.GetReviewBuckets(conn, goal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	localNow := now.In(loc)
+	counts := DailyBucketCounts(buckets, loc)
+	current, longest := Streaks(counts, localNow)
+
+	today := localNow.Format(dayLayout)
+	progress := &models.GoalProgress{
+		Date:              today,
+		TimeZone:          goal.TimeZone,
+		DailyReviewTarget: goal.DailyReviewTarget,
+		ReviewsToday:      counts[today],
+		CurrentStreak:     current,
+		LongestStreak:     longest,
+	}
+
+	progress.Remaining = goal.DailyReviewTarget - progress.ReviewsToday
+	if progress.Remaining < 0 {
+		progress.Remaining = 0
+	}
+	progress.Completed = progress.Remaining == 0
+
+	if last := LastStudyDay(counts); last != "" {
+		progress.LastStudyDate = &last
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,144 @@
+package goals
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"backend_go/db"
+	"backend_go/models"
+)
+
+// lookbackDays is how many past days each check inspects, so days missed while
+// the server was down are still recorded.
+const lookbackDays = 7
+
+// defaultInterval is how often Run checks when Interval is not positive.
+const defaultInterval = time.Hour
+
+// Notifier is called once for every newly recorded missed day.
+type Notifier func(event models.GoalEvent)
+
+// LogNotifier is the default Notifier; it only writes the event to the log.
+func LogNotifier(event models.GoalEvent) {
+	log.Printf("Daily goal missed by user %d on %s: %d of %d reviews", event.UserID, event.Day, event.ReviewCount, event.DailyReviewTarget)
+}
+
+// Reminder periodically records days on which the daily review target was missed.
+type Reminder struct {
+	DB       *sql.DB
+	Interval time.Duration
+	Notify   Notifier
+
+	now func() time.Time
+}
+
+// NewReminder returns a Reminder that checks every interval and reports missed days to notify.
+func NewReminder(conn *sql.DB, interval time.Duration, notify Notifier) *Reminder {
+	if notify == nil {
+		notify = LogNotifier
+	}
+	return &Reminder{DB: conn, Interval: interval, Notify: notify, now: time.Now}
+}
+
+// Run checks immediately and then on every tick until ctx is cancelled.
+func (r *Reminder) Run(ctx context.Context) {
+	interval := r.Interval
+	if interval <= 0 {
+		log.Printf("Invalid daily goal check interval %s, using %s", interval, defaultInterval)
+		interval = defaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Check(); err != nil {
+			log.Println("Failed to check daily goal:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check records, for every user's goal, a missed_day event for each finished day in
+// the lookback window whose review count fell short of the target. Days before the
+// goal was created are skipped. It returns the newly recorded events. A goal that
+// cannot be checked does not stop the others; its error is logged and returned
+// along with those of any other failed goals.
+func (r *Reminder) Check() ([]models.GoalEvent, error) {
+	userGoals, err := db.GetGoals(r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var recorded []models.GoalEvent
+	var errs []error
+	for i := range userGoals {
+		events, err := r.checkGoal(&userGoals[i])
+		recorded = append(recorded, events...)
+		if err != nil {
+			log.Printf("Failed to check daily goal of user %d: %v", userGoals[i].UserID, err)
+			errs = append(errs, fmt.Errorf("user %d: %w", userGoals[i].UserID, err))
+		}
+	}
+
+	return recorded, errors.Join(errs...)
+}
+
+// checkGoal records the missed days of a single user's goal.
+func (r *Reminder) checkGoal(goal *models.Goal) ([]models.GoalEvent, error) {
+	if goal.DailyReviewTarget <= 0 {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(goal.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid goal time zone %q: %w", goal.TimeZone, err)
+	}
+
+	today := civilDay(r.now().In(loc))
+	first := today.AddDate(0, 0, -lookbackDays)
+	if created := civilDay(goal.CreatedAt.In(loc)); created.After(first) {
+		first = created
+	}
+
+	// Fetch from a day earlier than needed so time zone offsets cannot cut off reviews
+	timestamps, err := db.GetReviewTimestamps(r.DB, goal.UserID, first.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	counts := DailyCounts(timestamps, loc)
+
+	var recorded []models.GoalEvent
+	for day := first; day.Before(today); day = day.AddDate(0, 0, 1) {
+		key := day.Format(dayLayout)
+		if counts[key] >= goal.DailyReviewTarget {
+			continue
+		}
+
+		event := models.GoalEvent{
+			UserID:            goal.UserID,
+			EventType:         models.GoalEventMissedDay,
+			Day:               key,
+			ReviewCount:       counts[key],
+			DailyReviewTarget: goal.DailyReviewTarget,
+		}
+		created, err := db.CreateGoalEvent(r.DB, &event)
+		if err != nil {
+			return recorded, err
+		}
+		if created {
+			recorded = append(recorded, event)
+			r.Notify(event)
+		}
+	}
+
+	return recorded, nil
+}
@@ -0,0 +1,95 @@
+// Package goals computes daily goal progress and study streaks from review
+// timestamps, and runs the reminder that records missed days.
+package goals
+
+import (
+	"sort"
+	"time"
+
+	"backend_go/models"
+)
+
+// dayLayout formats calendar days.
+const dayLayout = "2006-01-02"
+
+// DailyCounts counts reviews per calendar day in loc. Day boundaries follow the
+// learner's time zone, so a review at 23:30 local time counts for that day even
+// when it is already the next day in UTC.
+func DailyCounts(timestamps []time.Time, loc *time.Location) map[string]int {
+	counts := make(map[string]int)
+	for _, timestamp := range timestamps {
+		counts[timestamp.In(loc).Format(dayLayout)]++
+	}
+	return counts
+}
+
+// DailyBucketCounts is DailyCounts for reviews already counted per quarter hour.
+func DailyBucketCounts(buckets []models.ReviewBucket, loc *time.Location) map[string]int {
+	counts := make(map[string]int)
+	for _, bucket := range buckets {
+		counts[bucket.Start.In(loc).Format(dayLayout)] += bucket.Count
+	}
+	return counts
+}
+
+// Streaks returns the current and longest runs of consecutive study days.
+// The current streak is still alive if the learner studied yesterday but not yet today.
+func Streaks(counts map[string]int, today time.Time) (current, longest int) {
+	if len(counts) == 0 {
+		return 0, 0
+	}
+
+	days := make([]time.Time, 0, len(counts))
+	for day, count := range counts {
+		if count == 0 {
+			continue
+		}
+		parsed, err := time.Parse(dayLayout, day)
+		if err != nil {
+			continue
+		}
+		days = append(days, parsed)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	run := 0
+	for i, day := range days {
+		if i > 0 && day.Sub(days[i-1]) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	// Walk back from today (or yesterday) while every day has reviews
+	cursor := civilDay(today)
+	if counts[cursor.Format(dayLayout)] == 0 {
+		cursor = cursor.AddDate(0, 0, -1)
+	}
+	for counts[cursor.Format(dayLayout)] > 0 {
+		current++
+		cursor = cursor.AddDate(0, 0, -1)
+	}
+
+	return current, longest
+}
+
+// LastStudyDay returns the latest day with reviews, or "" if there are none.
+func LastStudyDay(counts map[string]int) string {
+	last := ""
+	for day, count := range counts {
+		if count > 0 && day > last {
+			last = day
+		}
+	}
+	return last
+}
+
+// civilDay returns midnight UTC of t's calendar day, so that day arithmetic is
+// not affected by daylight saving changes.
+func civilDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
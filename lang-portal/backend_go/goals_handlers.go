@@ -0,0 +1,136 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend_go/db"
+	"backend_go/goals"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getGoalHandler handles the GET /api/goals endpoint.
+func getGoalHandler(c *gin.Context) {
+	goal, err := db.GetGoal(dbConn, currentUser(c).ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goal"})
+		log.Println("Failed to fetch goal:", err)
+		return
+	}
+	if goal == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"item": goal})
+}
+
+// updateGoalHandler handles the PUT /api/goals endpoint.
+func updateGoalHandler(c *gin.Context) {
+	var goal models.Goal
+	if err := c.BindJSON(&goal); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	goal.UserID = currentUser(c).ID
+	if goal.DailyReviewTarget < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "daily_review_target must be positive"})
+		return
+	}
+	if goal.TimeZone == "" {
+		goal.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(goal.TimeZone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown time_zone"})
+		return
+	}
+
+	if err := db.UpdateGoal(dbConn, &goal); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update goal in database"})
+		log.Println("Failed to update goal:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Goal updated successfully"})
+}
+
+// getGoalProgressHandler handles the GET /api/goals/progress endpoint.
+func getGoalProgressHandler(c *gin.Context) {
+	goal, err := db.GetGoal(dbConn, currentUser(c).ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goal"})
+		log.Println("Failed to fetch goal:", err)
+		return
+	}
+	if goal == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	progress, err := goals.Progress(dbConn, goal, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute goal progress"})
+		log.Println("Failed to compute goal progress:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": progress})
+}
+
+// getGoalEventsHandler handles the GET /api/goals/events endpoint.
+func getGoalEventsHandler(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "30"))
+	if limit < 1 {
+		limit = 30
+	}
+
+	events, err := db.GetGoalEvents(dbConn, currentUser(c).ID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goal events"})
+		log.Println("Failed to fetch goal events:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": events})
+}
+
+// getQuickStatsHandler handles the GET /api/dashboard/quick_stats endpoint.
+func getQuickStatsHandler(c *gin.Context) {
+	userID, ok := statsUserID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := db.GetQuickStats(dbConn, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch quick stats"})
+		log.Println("Failed to fetch quick stats:", err)
+		return
+	}
+
+	goal, err := db.GetGoal(dbConn, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goal"})
+		log.Println("Failed to fetch goal:", err)
+		return
+	}
+	if goal == nil {
+		goal = &models.Goal{UserID: userID, TimeZone: "UTC"}
+	}
+
+	progress, err := goals.Progress(dbConn, goal, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute streaks"})
+		log.Println("Failed to compute streaks:", err)
+		return
+	}
+	stats.CurrentStreak = progress.CurrentStreak
+	stats.LongestStreak = progress.LongestStreak
+
+	c.JSON(http.StatusOK, gin.H{"data": stats})
+}
@@ -0,0 +1,141 @@
+// Package grammar validates the structured grammar stored with each word against
+// the schema for its part of speech, and turns it into drills for study activities.
+package grammar
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"backend_go/models"
+)
+
+// Genders of nouns.
+const (
+	Masculine = "masculine"
+	Feminine  = "feminine"
+)
+
+// Persons are the conjugation persons in the order drills use them: first,
+// second and third person singular, then plural.
+var Persons = []string{"1s", "2s", "3s", "1p", "2p", "3p"}
+
+// definiteArticles are the singular definite articles by target language and gender.
+// Article drills are only generated for these languages.
+var definiteArticles = map[string]map[string]string{
+	"pt": {Masculine: "o", Feminine: "a"},
+	"es": {Masculine: "el", Feminine: "la"},
+}
+
+// PartOfSpeech normalizes a word's parts field for comparison.
+func PartOfSpeech(parts string) string {
+	return strings.ToLower(strings.TrimSpace(parts))
+}
+
+// Validate checks that g only uses the fields allowed for the part of speech:
+// gender for nouns, plural for nouns and adjectives, conjugations for verbs.
+// Examples are allowed for every word. A nil grammar is valid.
+func Validate(parts string, g *models.Grammar) error {
+	if g == nil {
+		return nil
+	}
+	partOfSpeech := PartOfSpeech(parts)
+
+	if g.Gender != "" {
+		if partOfSpeech != "noun" {
+			return errors.New("gender is only allowed for nouns")
+		}
+		if g.Gender != Masculine && g.Gender != Feminine {
+			return errors.New("gender must be masculine or feminine")
+		}
+	}
+
+	if g.Plural != "" && partOfSpeech != "noun" && partOfSpeech != "adjective" {
+		return errors.New("plural is only allowed for nouns and adjectives")
+	}
+
+	if len(g.Conjugations) > 0 {
+		if partOfSpeech != "verb" {
+			return errors.New("conjugations are only allowed for verbs")
+		}
+		for tense, forms := range g.Conjugations {
+			if strings.TrimSpace(tense) == "" {
+				return errors.New("conjugation tenses must not be empty")
+			}
+			for person, form := range forms {
+				if !validPerson(person) {
+					return fmt.Errorf("conjugation person %q must be one of %s", person, strings.Join(Persons, ", "))
+				}
+				if strings.TrimSpace(form) == "" {
+					return fmt.Errorf("conjugation %s %s must not be empty", tense, person)
+				}
+			}
+		}
+	}
+
+	for i, example := range g.Examples {
+		if strings.TrimSpace(example.Sentence) == "" || strings.TrimSpace(example.Translation) == "" {
+			return fmt.Errorf("example %d needs a sentence and a translation", i+1)
+		}
+	}
+
+	return nil
+}
+
+// validPerson reports whether person is one of Persons.
+func validPerson(person string) bool {
+	for _, p := range Persons {
+		if p == person {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidDrillType reports whether drillType is a known drill type.
+func ValidDrillType(drillType string) bool {
+	return drillType == models.DrillArticle || drillType == models.DrillPlural || drillType == models.DrillConjugation
+}
+
+// Drills generates drills of the given type, or of every type when drillType is
+// empty, from the words' grammar. Words without the needed grammar are skipped.
+func Drills(words []models.Word, drillType string) []models.Drill {
+	drills := []models.Drill{}
+	for _, word := range words {
+		if word.Grammar == nil {
+			continue
+		}
+		g := word.Grammar
+
+		if drillType == "" || drillType == models.DrillArticle {
+			if article, ok := definiteArticles[word.TargetLanguage][g.Gender]; ok {
+				drills = append(drills, models.Drill{WordID: word.ID, Type: models.DrillArticle,
+					Prompt: "___ " + word.TargetText, Answer: article})
+			}
+		}
+
+		if (drillType == "" || drillType == models.DrillPlural) && g.Plural != "" {
+			drills = append(drills, models.Drill{WordID: word.ID, Type: models.DrillPlural,
+				Prompt: word.TargetText, Answer: g.Plural})
+		}
+
+		if drillType == "" || drillType == models.DrillConjugation {
+			tenses := make([]string, 0, len(g.Conjugations))
+			for tense := range g.Conjugations {
+				tenses = append(tenses, tense)
+			}
+			sort.Strings(tenses)
+
+			for _, tense := range tenses {
+				for _, person := range Persons {
+					if form, ok := g.Conjugations[tense][person]; ok {
+						drills = append(drills, models.Drill{WordID: word.ID, Type: models.DrillConjugation,
+							Prompt: fmt.Sprintf("%s (%s, %s)", word.TargetText, tense, person), Answer: form})
+					}
+				}
+			}
+		}
+	}
+	return drills
+}
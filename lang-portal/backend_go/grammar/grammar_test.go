@@ -0,0 +1,54 @@
+package grammar
+
+import (
+	"testing"
+
+	"backend_go/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("noun", nil))
+	assert.NoError(t, Validate("Noun", &models.Grammar{Gender: Feminine, Plural: "casas"}))
+	assert.NoError(t, Validate("adjective", &models.Grammar{Plural: "bonitos"}))
+	assert.NoError(t, Validate("verb", &models.Grammar{Conjugations: map[string]map[string]string{
+		"presente": {"1s": "falo", "3p": "falam"},
+	}}))
+	assert.NoError(t, Validate("phrase", &models.Grammar{Examples: []models.Example{
+		{Sentence: "Muito obrigado!", Translation: "Thank you very much!"},
+	}}))
+
+	assert.Error(t, Validate("noun", &models.Grammar{Gender: "neuter"}))
+	assert.Error(t, Validate("verb", &models.Grammar{Gender: Masculine}))
+	assert.Error(t, Validate("verb", &models.Grammar{Plural: "falas"}))
+	assert.Error(t, Validate("noun", &models.Grammar{Conjugations: map[string]map[string]string{"presente": {"1s": "x"}}}))
+	assert.Error(t, Validate("verb", &models.Grammar{Conjugations: map[string]map[string]string{"presente": {"eu": "falo"}}}))
+	assert.Error(t, Validate("verb", &models.Grammar{Conjugations: map[string]map[string]string{"presente": {"1s": ""}}}))
+	assert.Error(t, Validate("noun", &models.Grammar{Examples: []models.Example{{Sentence: "A casa."}}}))
+}
+
+func TestDrills(t *testing.T) {
+	words := []models.Word{
+		{ID: 1, TargetLanguage: "pt", TargetText: "casa", Parts: "noun",
+			Grammar: &models.Grammar{Gender: Feminine, Plural: "casas"}},
+		{ID: 2, TargetLanguage: "pt", TargetText: "falar", Parts: "verb",
+			Grammar: &models.Grammar{Conjugations: map[string]map[string]string{
+				"presente": {"3p": "falam", "1s": "falo"},
+			}}},
+		{ID: 3, TargetLanguage: "ja", TargetText: "水", Parts: "noun",
+			Grammar: &models.Grammar{Gender: Masculine}},
+		{ID: 4, TargetLanguage: "pt", TargetText: "sim", Parts: "adverb"},
+	}
+
+	drills := Drills(words, "")
+	require.Len(t, drills, 4)
+	assert.Equal(t, models.Drill{WordID: 1, Type: models.DrillArticle, Prompt: "___ casa", Answer: "a"}, drills[0])
+	assert.Equal(t, models.Drill{WordID: 1, Type: models.DrillPlural, Prompt: "casa", Answer: "casas"}, drills[1])
+	assert.Equal(t, "falar (presente, 1s)", drills[2].Prompt)
+	assert.Equal(t, "falam", drills[3].Answer)
+
+	assert.Len(t, Drills(words, models.DrillConjugation), 2)
+	assert.Empty(t, Drills(words[3:], ""))
+}
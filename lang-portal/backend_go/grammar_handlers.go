@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"backend_go/db"
+	"backend_go/grammar"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getGroupDrillsHandler handles the GET /api/groups/:id/drills endpoint.
+// It generates article, plural and conjugation drills from the grammar of the
+// group's words; the optional type parameter limits them to one kind and the
+// word filter parameters, such as tag, to some of the words.
+func getGroupDrillsHandler(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	drillType := c.Query("type")
+	if drillType != "" && !grammar.ValidDrillType(drillType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be article, plural or conjugation"})
+		return
+	}
+
+	group, err := db.GetGroupByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group from database"})
+		log.Println("Failed to fetch group:", err)
+		return
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	words, err := db.GetGroupWords(dbConn, id, requestScope(c), wordFilterParam(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group words from database"})
+		log.Println("Failed to fetch group words:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": grammar.Drills(words, drillType)})
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordGrammar(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	createWord := func(body string) int {
+		resp := request("POST", "/api/words", body)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		var created struct{ ID int }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+		_, err := db.Exec("INSERT INTO words_groups (word_id, group_id) VALUES (?, 1)", created.ID)
+		require.NoError(t, err)
+		return created.ID
+	}
+
+	house := createWord(`{"source_text": "house", "target_text": "casa", "parts": "noun",
+		"grammar": {"gender": "feminine", "plural": "casas",
+			"examples": [{"sentence": "A casa é grande.", "translation": "The house is big."}]}}`)
+	createWord(`{"source_text": "to speak", "target_text": "falar", "parts": "verb",
+		"grammar": {"conjugations": {"presente": {"1s": "falo", "3s": "fala"}}}}`)
+
+	t.Run("Grammar must fit the part of speech", func(t *testing.T) {
+		resp := request("POST", "/api/words", `{"source_text": "to eat", "target_text": "comer", "parts": "verb", "grammar": {"gender": "masculine"}}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		resp = request("POST", "/api/words", `{"source_text": "book", "target_text": "livro", "parts": "noun", "grammar": {"gender": "neuter"}}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Grammar is returned and queryable", func(t *testing.T) {
+		resp := request("GET", "/api/words?part_of_speech=noun&gender=feminine", "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var list struct{ Items []models.Word }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, house, list.Items[0].ID)
+		require.NotNil(t, list.Items[0].Grammar)
+		require.Len(t, list.Items[0].Grammar.Examples, 1)
+		assert.Equal(t, "The house is big.", list.Items[0].Grammar.Examples[0].Translation)
+	})
+
+	t.Run("Drills are generated from the group's grammar", func(t *testing.T) {
+		resp := request("GET", "/api/groups/1/drills", "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var drills struct{ Items []models.Drill }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &drills))
+		require.Len(t, drills.Items, 4)
+		assert.Equal(t, models.Drill{WordID: house, Type: models.DrillArticle, Prompt: "___ casa", Answer: "a"}, drills.Items[0])
+
+		resp = request("GET", "/api/groups/1/drills?type=conjugation", "")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &drills))
+		require.Len(t, drills.Items, 2)
+		assert.Equal(t, "falo", drills.Items[0].Answer)
+
+		assert.Equal(t, http.StatusBadRequest, request("GET", "/api/groups/1/drills?type=spelling", "").Code)
+		assert.Equal(t, http.StatusNotFound, request("GET", "/api/groups/99/drills", "").Code)
+	})
+}
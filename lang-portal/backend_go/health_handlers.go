@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"backend_go/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readyCheckTimeout bounds the database ping made by a readiness check.
+const readyCheckTimeout = 2 * time.Second
+
+// healthCheck is the outcome of one readiness check.
+type healthCheck struct {
+	Status  string   `json:"status"`
+	Error   string   `json:"error,omitempty"`
+	Pending []string `json:"pending,omitempty"`
+}
+
+// healthzHandler handles the GET /healthz endpoint. It only reports that the
+// process is up and serving requests.
+func healthzHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// readyzHandler handles the GET /readyz endpoint. The server is ready when the
+// database answers, every migration has been applied, and the database and media
+// directories are writable. Each check is reported; any failure answers 503.
+func readyzHandler(c *gin.Context) {
+	checks := map[string]healthCheck{
+		"database":   checkDatabase(c.Request.Context()),
+		"migrations": checkMigrations(),
+		"disk":       checkDisk(filepath.Dir(dbPath), appConfig.MediaDir),
+	}
+
+	status, code := "ready", http.StatusOK
+	for name, check := range checks {
+		if check.Status != "ok" {
+			status, code = "not_ready", http.StatusServiceUnavailable
+			log.Printf("Readiness check %s failed: %s", name, check.Error)
+		}
+	}
+
+	c.JSON(code, gin.H{"status": status, "checks": checks})
+}
+
+// failedCheck reports err as a failed check.
+func failedCheck(err error) healthCheck {
+	return healthCheck{Status: "failed", Error: err.Error()}
+}
+
+// checkDatabase pings the database.
+func checkDatabase(ctx context.Context) healthCheck {
+	ctx, cancel := context.WithTimeout(ctx, readyCheckTimeout)
+	defer cancel()
+
+	if err := dbConn.PingContext(ctx); err != nil {
+		return failedCheck(err)
+	}
+	return healthCheck{Status: "ok"}
+}
+
+// checkMigrations verifies that the database schema matches this build.
+func checkMigrations() healthCheck {
+	pending, err := db.PendingMigrations(dbConn)
+	if err != nil {
+		return failedCheck(err)
+	}
+	if len(pending) > 0 {
+		return healthCheck{Status: "failed", Error: "migrations have not been applied", Pending: pending}
+	}
+	return healthCheck{Status: "ok"}
+}
+
+// checkDisk verifies that a file can be created in each of dirs.
+func checkDisk(dirs ...string) healthCheck {
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return failedCheck(fmt.Errorf("cannot create %s: %w", dir, err))
+		}
+		f, err := os.CreateTemp(dir, ".readyz-*")
+		if err != nil {
+			return failedCheck(fmt.Errorf("cannot write to %s: %w", dir, err))
+		}
+		f.Close()
+		os.Remove(f.Name())
+	}
+	return healthCheck{Status: "ok"}
+}
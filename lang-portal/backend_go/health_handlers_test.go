@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthAndReadiness(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	dbConn = db
+	appConfig.OpenMode = false
+	defer func() { appConfig.OpenMode = true }()
+	appConfig.MediaDir = filepath.Join(t.TempDir(), "uploads")
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	type readiness struct {
+		Status string
+		Checks map[string]healthCheck
+	}
+	ready := func() (int, readiness) {
+		req, _ := http.NewRequest("GET", "/readyz", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		var body readiness
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		return resp.Code, body
+	}
+
+	t.Run("Health needs no credentials", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/healthz", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Unrecorded migrations", func(t *testing.T) {
+		// The test schema is built without recording migrations
+		code, body := ready()
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "not_ready", body.Status)
+		assert.Equal(t, "ok", body.Checks["database"].Status)
+		assert.Equal(t, "ok", body.Checks["disk"].Status)
+		assert.Equal(t, "failed", body.Checks["migrations"].Status)
+		assert.Contains(t, body.Checks["migrations"].Pending, "0018_add_study_session_status")
+		assert.DirExists(t, appConfig.MediaDir)
+	})
+
+	t.Run("Ready", func(t *testing.T) {
+		_, err := db.Exec(`CREATE TABLE migrations (id INTEGER PRIMARY KEY, applied_at DATETIME DEFAULT CURRENT_TIMESTAMP);
+			WITH RECURSIVE n(id) AS (SELECT 1 UNION ALL SELECT id + 1 FROM n WHERE id < 100)
+			INSERT INTO migrations (id) SELECT id FROM n`)
+		require.NoError(t, err)
+
+		code, body := ready()
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ready", body.Status)
+		assert.Empty(t, body.Checks["migrations"].Pending)
+	})
+
+	t.Run("Broken database", func(t *testing.T) {
+		require.NoError(t, db.Close())
+
+		code, body := ready()
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "failed", body.Checks["database"].Status)
+		assert.NotEmpty(t, body.Checks["database"].Error)
+	})
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"backend_go/db"
+	"backend_go/grammar"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// languageCodePattern matches ISO 639-1 codes, optionally with a region ("pt-BR").
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+
+// getLanguagesHandler handles the GET /api/languages endpoint.
+func getLanguagesHandler(c *gin.Context) {
+	languages, err := db.GetLanguages(dbConn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch languages from database"})
+		log.Println("Failed to fetch languages:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": languages})
+}
+
+// createLanguageHandler handles the POST /api/languages endpoint.
+func createLanguageHandler(c *gin.Context) {
+	var language models.Language
+	if err := c.BindJSON(&language); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	language.Name = strings.TrimSpace(language.Name)
+	if !languageCodePattern.MatchString(language.Code) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code must be an ISO 639-1 language code"})
+		return
+	}
+	if language.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	exists, err := db.LanguageExists(dbConn, language.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check language"})
+		log.Println("Failed to check language:", err)
+		return
+	}
+	if exists {
+		c.JSON(http.StatusConflict, gin.H{"error": "Language already exists"})
+		return
+	}
+
+	if err := db.CreateLanguage(dbConn, &language); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create language in database"})
+		log.Println("Failed to create language:", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"item": language})
+}
+
+// wordFilterParam reads the optional source_language, target_language,
+// part_of_speech, gender and tag query parameters.
+func wordFilterParam(c *gin.Context) db.WordFilter {
+	return db.WordFilter{
+		SourceLanguage: c.Query("source_language"),
+		TargetLanguage: c.Query("target_language"),
+		PartOfSpeech:   c.Query("part_of_speech"),
+		Gender:         c.Query("gender"),
+		Tag:            normalizeTag(c.Query("tag")),
+	}
+}
+
+// bindWord binds a word from the request body. When stored is set the request
+// updates it, and languages missing from the body are kept from it; otherwise
+// clients that only send the legacy english and portuguese fields create English
+// to Portuguese words. When a legacy field and its source_text or target_text
+// counterpart disagree, the one that differs from the stored word is the edit.
+// It writes an error response and returns false when both were edited to different
+// values, the word is incomplete, its languages are unknown or its grammar does
+// not fit its part of speech.
+func bindWord(c *gin.Context, stored *models.Word) (models.Word, bool) {
+	var word models.Word
+	if err := c.BindJSON(&word); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return word, false
+	}
+	var storedSource, storedTarget string
+	if stored != nil {
+		if word.SourceLanguage == "" {
+			word.SourceLanguage = stored.SourceLanguage
+		}
+		if word.TargetLanguage == "" {
+			word.TargetLanguage = stored.TargetLanguage
+		}
+		storedSource, storedTarget = stored.SourceText, stored.TargetText
+	}
+	if !resolveWordText(&word.English, &word.SourceText, storedSource) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "english and source_text were changed to different values"})
+		return word, false
+	}
+	if !resolveWordText(&word.Portuguese, &word.TargetText, storedTarget) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "portuguese and target_text were changed to different values"})
+		return word, false
+	}
+
+	problem, err := checkWord(&word)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check language"})
+		log.Println("Failed to check language:", err)
+		return word, false
+	}
+	if problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return word, false
+	}
+
+	return word, true
+}
+
+// resolveWordText reconciles a legacy text field with its language-neutral
+// counterpart, keeping whichever one differs from stored. It returns false when
+// both differ from stored and from each other.
+func resolveWordText(legacy, text *string, stored string) bool {
+	if *legacy == "" || *text == "" || *legacy == *text {
+		return true
+	}
+	switch {
+	case *legacy == stored:
+		*legacy = *text
+	case *text == stored:
+		*text = *legacy
+	default:
+		return false
+	}
+	return true
+}
+
+// checkWord defaults the word's language pair and returns why it cannot be
+// stored, or "" when it is valid. The error is only set when the check itself failed.
+func checkWord(word *models.Word) (string, error) {
+	db.DefaultLanguagePair(word)
+	if word.SourceText == "" || word.TargetText == "" {
+		return "source_text and target_text are required", nil
+	}
+	if word.SourceLanguage == word.TargetLanguage {
+		return "source_language and target_language must differ", nil
+	}
+	if err := grammar.Validate(word.Parts, word.Grammar); err != nil {
+		return "Invalid grammar: " + err.Error(), nil
+	}
+
+	for _, code := range []string{word.SourceLanguage, word.TargetLanguage} {
+		exists, err := db.LanguageExists(dbConn, code)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return "Unknown language: " + code, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguagePairs(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	listWords := func(query string) []models.Word {
+		resp := request("GET", "/api/words"+query, "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var list struct{ Items []models.Word }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		return list.Items
+	}
+
+	t.Run("Languages can be listed and added", func(t *testing.T) {
+		resp := request("POST", "/api/languages", `{"code": "de", "name": "German"}`)
+		require.Equal(t, http.StatusCreated, resp.Code)
+		assert.Equal(t, http.StatusConflict, request("POST", "/api/languages", `{"code": "de", "name": "German"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/languages", `{"code": "German", "name": "German"}`).Code)
+
+		resp = request("GET", "/api/languages", "")
+		var list struct{ Items []models.Language }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		assert.Len(t, list.Items, 5)
+	})
+
+	t.Run("Words are created in any known language pair", func(t *testing.T) {
+		resp := request("POST", "/api/words", `{"source_language": "en", "target_language": "ja", "source_text": "water", "target_text": "水", "parts": "noun"}`)
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		resp = request("POST", "/api/words", `{"source_language": "en", "target_language": "xx", "source_text": "water", "target_text": "?"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		resp = request("POST", "/api/words", `{"source_language": "en", "target_language": "es"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Legacy clients keep using english and portuguese", func(t *testing.T) {
+		resp := request("POST", "/api/words", `{"english": "bread", "portuguese": "pão", "parts": "noun"}`)
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		words := listWords("?target_language=pt")
+		require.Len(t, words, 6)
+		bread := words[5]
+		assert.Equal(t, "en", bread.SourceLanguage)
+		assert.Equal(t, "pão", bread.TargetText)
+		assert.Equal(t, "bread", bread.English)
+		assert.Equal(t, "pão", bread.Portuguese)
+	})
+
+	t.Run("Legacy updates keep the stored language pair", func(t *testing.T) {
+		water := listWords("?target_language=ja")[0]
+		url := "/api/words/" + strconv.Itoa(water.ID)
+		resp := request("PUT", url, `{"english": "fresh water", "portuguese": "水", "parts": "noun"}`)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		updated := listWords("?target_language=ja")
+		require.Len(t, updated, 1)
+		assert.Equal(t, "en", updated[0].SourceLanguage)
+		assert.Equal(t, "fresh water", updated[0].SourceText)
+
+		assert.Equal(t, http.StatusNotFound, request("PUT", "/api/words/999", `{"english": "x", "portuguese": "y"}`).Code)
+	})
+
+	t.Run("Round-tripped words keep legacy edits", func(t *testing.T) {
+		water := listWords("?target_language=ja")[0]
+		water.English = "still water"
+		body, err := json.Marshal(water)
+		require.NoError(t, err)
+		resp := request("PUT", "/api/words/"+strconv.Itoa(water.ID), string(body))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		updated := listWords("?target_language=ja")[0]
+		assert.Equal(t, "still water", updated.SourceText)
+		assert.Equal(t, "still water", updated.English)
+		assert.Equal(t, "水", updated.TargetText)
+	})
+
+	t.Run("Round-tripped words keep source_text edits", func(t *testing.T) {
+		water := listWords("?target_language=ja")[0]
+		water.SourceText = "sparkling water"
+		body, err := json.Marshal(water)
+		require.NoError(t, err)
+		resp := request("PUT", "/api/words/"+strconv.Itoa(water.ID), string(body))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		updated := listWords("?target_language=ja")[0]
+		assert.Equal(t, "sparkling water", updated.SourceText)
+		assert.Equal(t, "sparkling water", updated.English)
+
+		updated.English = "tap water"
+		updated.SourceText = "mineral water"
+		body, err = json.Marshal(updated)
+		require.NoError(t, err)
+		resp = request("PUT", "/api/words/"+strconv.Itoa(water.ID), string(body))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, "sparkling water", listWords("?target_language=ja")[0].SourceText)
+	})
+
+	t.Run("Words are filtered by language pair", func(t *testing.T) {
+		assert.Len(t, listWords(""), 7)
+
+		words := listWords("?source_language=en&target_language=ja")
+		require.Len(t, words, 1)
+		assert.Equal(t, "水", words[0].TargetText)
+
+		assert.Empty(t, listWords("?source_language=ja"))
+	})
+}
@@ -0,0 +1,95 @@
+// Package llm talks to OpenAI-compatible chat completion endpoints, such as the
+// vLLM and Ollama services in opea-comps, and uses them to propose vocabulary.
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxResponseBytes caps how much of a completion response is read.
+const maxResponseBytes = 4 << 20
+
+// Message is one chat message.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest is the body posted to /chat/completions.
+type ChatRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	Temperature float64   `json:"temperature"`
+}
+
+// Choice is one completion in a ChatResponse.
+type Choice struct {
+	Message Message `json:"message"`
+}
+
+// ChatResponse is the part of a /chat/completions response the client reads.
+type ChatResponse struct {
+	Choices []Choice `json:"choices"`
+}
+
+// Client calls a chat completion endpoint.
+type Client struct {
+	// BaseURL is the API root, such as "http://localhost:8008/v1".
+	BaseURL string
+	Model   string
+	APIKey  string
+	HTTP    *http.Client
+}
+
+// NewClient returns a client for the API at baseURL that gives up on a request after timeout.
+func NewClient(baseURL, model, apiKey string, timeout time.Duration) *Client {
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Model:   model,
+		APIKey:  apiKey,
+		HTTP:    &http.Client{Timeout: timeout},
+	}
+}
+
+// Complete sends the conversation and returns the content of the first choice.
+func (c *Client) Complete(ctx context.Context, messages []Message) (string, error) {
+	body, err := json.Marshal(ChatRequest{Model: c.Model, Messages: messages, Temperature: 0.7})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode chat request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call chat endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("chat endpoint returned %s", resp.Status)
+	}
+
+	var completion ChatResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&completion); err != nil {
+		return "", fmt.Errorf("failed to decode chat response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("chat response has no choices")
+	}
+	return completion.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,62 @@
+package llm_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"backend_go/llm"
+	"backend_go/llm/llmtest"
+	"backend_go/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposeWords(t *testing.T) {
+	server := llmtest.NewServer(llmtest.Reply("Here you go:\n```json\n[" +
+		`{"source_text": "apple", "target_text": "maçã", "parts": "Noun"},` +
+		`{"english": "pear", "portuguese": "pêra", "parts": "noun"},` +
+		`{"source_text": "bread", "target_text": "pão", "parts": "noun"},` +
+		`{"source_text": "apple", "target_text": "Maçã", "parts": "noun"},` +
+		`{"source_text": "", "target_text": "uva", "parts": "noun"},` +
+		`{"source_text": "milk", "target_text": "leite", "parts": "noun"}` +
+		"]\n```"))
+	defer server.Close()
+
+	client := llm.NewClient(server.URL+"/", "llama3", "secret", time.Second)
+	words, err := llm.ProposeWords(context.Background(), client, llm.VocabularyRequest{
+		Theme:          "Food",
+		Count:          2,
+		SourceLanguage: models.Language{Code: "en", Name: "English"},
+		TargetLanguage: models.Language{Code: "pt", Name: "Portuguese"},
+		Exclude:        []string{"Pão"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, words, 2)
+	assert.Equal(t, models.Word{SourceLanguage: "en", TargetLanguage: "pt", SourceText: "apple", TargetText: "maçã",
+		Parts: "noun", English: "apple", Portuguese: "maçã"}, words[0])
+	assert.Equal(t, "pêra", words[1].TargetText)
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "llama3", requests[0].Model)
+	require.Len(t, requests[0].Messages, 2)
+	assert.True(t, strings.Contains(requests[0].Messages[1].Content, `"Food"`))
+	assert.True(t, strings.Contains(requests[0].Messages[1].Content, "Do not include: Pão."))
+}
+
+func TestProposeWordsRejectsInvalidReplies(t *testing.T) {
+	server := llmtest.NewServer(llmtest.Reply("Sorry, I cannot help with that."))
+	defer server.Close()
+
+	client := llm.NewClient(server.URL, "", "", time.Second)
+	_, err := llm.ProposeWords(context.Background(), client, llm.VocabularyRequest{Theme: "Food", Count: 3})
+	assert.Error(t, err)
+
+	client.BaseURL += "/missing"
+	_, err = client.Complete(context.Background(), nil)
+	assert.ErrorContains(t, err, "404")
+}
@@ -0,0 +1,62 @@
+// Package llmtest provides a fake OpenAI-compatible chat endpoint for tests and
+// local development without a model server.
+package llmtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"backend_go/llm"
+)
+
+// ReplyFunc returns the assistant reply for a request.
+type ReplyFunc func(request llm.ChatRequest) string
+
+// Server is a fake chat endpoint. Its URL is the base URL to give llm.NewClient.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []llm.ChatRequest
+}
+
+// NewServer starts a fake endpoint that answers every chat request with reply.
+func NewServer(reply ReplyFunc) *Server {
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var request llm.ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, request)
+		s.mu.Unlock()
+
+		response := llm.ChatResponse{Choices: []llm.Choice{
+			{Message: llm.Message{Role: "assistant", Content: reply(request)}},
+		}}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	return s
+}
+
+// Reply returns a ReplyFunc that always answers with content.
+func Reply(content string) ReplyFunc {
+	return func(llm.ChatRequest) string { return content }
+}
+
+// Requests returns the chat requests received so far.
+func (s *Server) Requests() []llm.ChatRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]llm.ChatRequest(nil), s.requests...)
+}
@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"backend_go/models"
+)
+
+// SentenceRequest describes the example sentences to propose for a word.
+type SentenceRequest struct {
+	Word  models.Word
+	Count int
+	// Source and target languages of the word, by code and name.
+	SourceLanguage models.Language
+	TargetLanguage models.Language
+}
+
+// sentencePrompt instructs the model to reply with nothing but a JSON array.
+const sentencePrompt = `You write short, natural example sentences for language learners.
+Reply with only a JSON array, no other text. Each element is an object with the keys
+"text" (a sentence in %s that uses the given word) and "translation" (the sentence in %s).`
+
+// ProposeSentences asks the model for up to Count example sentences using the
+// word. The sentences are not checked or stored; empty and repeated ones are dropped.
+func ProposeSentences(ctx context.Context, client *Client, request SentenceRequest) ([]models.Sentence, error) {
+	content, err := client.Complete(ctx, []Message{
+		{Role: "system", Content: fmt.Sprintf(sentencePrompt, request.TargetLanguage.Name, request.SourceLanguage.Name)},
+		{Role: "user", Content: fmt.Sprintf("Write %d sentences using %q (%s, meaning %q).",
+			request.Count, request.Word.TargetText, request.Word.Parts, request.Word.SourceText)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var proposals []models.Sentence
+	if err := decodeArray(content, &proposals); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	sentences := []models.Sentence{}
+	for _, s := range proposals {
+		text := strings.TrimSpace(s.Text)
+		if text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		sentences = append(sentences, models.Sentence{
+			WordID:      request.Word.ID,
+			Text:        text,
+			Translation: strings.TrimSpace(s.Translation),
+			Origin:      models.SentenceOriginLLM,
+		})
+		if len(sentences) == request.Count {
+			break
+		}
+	}
+	return sentences, nil
+}
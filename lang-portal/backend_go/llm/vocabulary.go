@@ -0,0 +1,108 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"backend_go/models"
+)
+
+// VocabularyRequest describes the words to propose for a group.
+type VocabularyRequest struct {
+	Theme string
+	Count int
+	// Source and target languages, by code and name.
+	SourceLanguage models.Language
+	TargetLanguage models.Language
+	// Exclude lists target texts the group already has.
+	Exclude []string
+}
+
+// vocabularyPrompt instructs the model to reply with nothing but a JSON array.
+const vocabularyPrompt = `You help language teachers build vocabulary lists.
+Reply with only a JSON array, no other text. Each element is an object with the keys
+"source_text" (the word in %s), "target_text" (the word in %s) and "parts"
+(its part of speech: noun, verb, adjective, adverb, pronoun, preposition, conjunction or phrase).`
+
+// ProposeWords asks the model for up to Count words on the theme. The proposals
+// have their language pair set but are not validated or stored; proposals that
+// repeat an excluded word, each other, or lack a text are dropped.
+func ProposeWords(ctx context.Context, client *Client, request VocabularyRequest) ([]models.Word, error) {
+	user := fmt.Sprintf("Propose %d common %s words for the theme %q.", request.Count, request.TargetLanguage.Name, request.Theme)
+	if len(request.Exclude) > 0 {
+		user += " Do not include: " + strings.Join(request.Exclude, ", ") + "."
+	}
+
+	content, err := client.Complete(ctx, []Message{
+		{Role: "system", Content: fmt.Sprintf(vocabularyPrompt, request.SourceLanguage.Name, request.TargetLanguage.Name)},
+		{Role: "user", Content: user},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	proposals, err := parseWords(content)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, text := range request.Exclude {
+		seen[strings.ToLower(text)] = true
+	}
+
+	words := []models.Word{}
+	for _, word := range proposals {
+		word.SourceText = strings.TrimSpace(word.SourceText)
+		word.TargetText = strings.TrimSpace(word.TargetText)
+		word.Parts = strings.ToLower(strings.TrimSpace(word.Parts))
+		key := strings.ToLower(word.TargetText)
+		if word.SourceText == "" || word.TargetText == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		word.SourceLanguage = request.SourceLanguage.Code
+		word.TargetLanguage = request.TargetLanguage.Code
+		word.English, word.Portuguese = word.SourceText, word.TargetText
+		words = append(words, word)
+		if len(words) == request.Count {
+			break
+		}
+	}
+	return words, nil
+}
+
+// parseWords decodes the words in a reply. Models that answer with "english" and
+// "portuguese" keys are accepted.
+func parseWords(content string) ([]models.Word, error) {
+	var words []models.Word
+	if err := decodeArray(content, &words); err != nil {
+		return nil, err
+	}
+	for i := range words {
+		if words[i].SourceText == "" {
+			words[i].SourceText = words[i].English
+		}
+		if words[i].TargetText == "" {
+			words[i].TargetText = words[i].Portuguese
+		}
+	}
+	return words, nil
+}
+
+// decodeArray decodes the JSON array in a model reply into v, ignoring any text
+// or code fence around it.
+func decodeArray(content string, v interface{}) error {
+	start := strings.Index(content, "[")
+	end := strings.LastIndex(content, "]")
+	if start < 0 || end < start {
+		return fmt.Errorf("model reply does not contain a JSON array")
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), v); err != nil {
+		return fmt.Errorf("failed to decode model reply: %w", err)
+	}
+	return nil
+}
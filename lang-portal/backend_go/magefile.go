@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -15,16 +16,19 @@ import (
 	"strings"
 	"time"
 
+	"backend_go/db"
+	"backend_go/media"
+	"backend_go/tts"
+
 	"github.com/magefile/mage/mg"
 	_ "github.com/mattn/go-sqlite3" // Import SQLite driver
 )
 
+// DB groups database operations.
 type DB mg.Namespace
 
-// Define your tasks with mg.Namespace if needed
-var (
-	DB = mg.Namespace("db", "Database operations")
-)
+// Audio groups pronunciation audio operations.
+type Audio mg.Namespace
 
 // InitDb initializes the database by creating the words.db file if it doesn't exist.
 func (DB) Init() error {
@@ -253,6 +257,85 @@ func (DB) Seed() error {
 	return nil
 }
 
+// Backfill generates pronunciation audio for every word that has none, using the
+// text-to-speech service in LANG_PORTAL_TTS_ENDPOINT ("stub" for offline tones).
+// The media directory, upload limit and TTS settings are read from the same
+// environment variables, with the same defaults, as the server.
+func (Audio) Backfill() error {
+	endpoint := os.Getenv("LANG_PORTAL_TTS_ENDPOINT")
+	if endpoint == "" {
+		return fmt.Errorf("LANG_PORTAL_TTS_ENDPOINT is not set")
+	}
+	mediaDir := os.Getenv("LANG_PORTAL_MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "uploads"
+	}
+	maxBytes := int64(10 << 20)
+	if value := os.Getenv("LANG_PORTAL_MAX_UPLOAD_BYTES"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid LANG_PORTAL_MAX_UPLOAD_BYTES %q: %w", value, err)
+		}
+		maxBytes = parsed
+	}
+	timeout := 30 * time.Second
+	if value := os.Getenv("LANG_PORTAL_TTS_TIMEOUT"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid LANG_PORTAL_TTS_TIMEOUT %q: %w", value, err)
+		}
+		timeout = parsed
+	}
+
+	conn, err := sql.Open("sqlite3", filepath.Join(".", "words.db"))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
+
+	ids, err := db.GetWordIDsWithoutMedia(conn, media.KindAudio)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		fmt.Println("Every word already has audio.")
+		return nil
+	}
+	fmt.Printf("Generating audio for %d words...\n", len(ids))
+
+	generator := &tts.Generator{
+		DB:       conn,
+		Store:    media.NewStore(mediaDir),
+		Provider: tts.New(endpoint, os.Getenv("LANG_PORTAL_TTS_VOICE"), timeout),
+		MaxBytes: maxBytes,
+	}
+	queue := tts.NewQueue(generator.Generate, len(ids))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	for _, id := range ids {
+		if _, err := queue.Enqueue(id); err != nil {
+			return fmt.Errorf("failed to schedule word %d: %w", id, err)
+		}
+	}
+	queue.Wait()
+
+	failed := 0
+	for _, id := range ids {
+		if job, _ := queue.Job(id); job.Status == tts.JobFailed {
+			fmt.Printf("Word %d failed after %d attempts: %s\n", id, job.Attempts, job.Error)
+			failed++
+		}
+	}
+	fmt.Printf("Generated audio for %d of %d words.\n", len(ids)-failed, len(ids))
+	if failed > 0 {
+		return fmt.Errorf("%d words failed", failed)
+	}
+	return nil
+}
+
 // Install installs project dependencies. (Placeholder for now)
 func Install() error {
 	fmt.Println("Installing dependencies... (Not yet implemented)")
@@ -1,48 +1,106 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
+	"os/signal"
 	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
 	"backend_go/db" // Import your db package
+	"backend_go/goals"
+	"backend_go/llm"
+	"backend_go/media"
 	"backend_go/models"
+	"backend_go/sessions"
+	"backend_go/sessiontoken"
+	"backend_go/tts"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/mattn/go-sqlite3" // Import SQLite driver
+	"github.com/mattn/go-sqlite3"
 )
 
+// dbPath is the SQLite database file, created by "mage initdb"
+const dbPath = "words.db"
+
 // dbConn is the database connection variable
 var dbConn *sql.DB
 
+// tokenSigner issues and verifies session tokens for external learning apps
+var tokenSigner *sessiontoken.Signer
+
+// maxGroupWords caps how many words PUT /api/groups/:id/words may assign to a group
+const maxGroupWords = 10000
+
+// mediaStore holds uploaded word audio and images
+var mediaStore *media.Store
+
+// ttsQueue generates pronunciation audio in the background; nil when text-to-speech is not configured
+var ttsQueue *tts.Queue
+
+// llmClient proposes vocabulary for groups; nil when no LLM endpoint is configured
+var llmClient *llm.Client
+
 // Add this function before main()
 func SetupRoutes(router *gin.Engine) {
 	// Move all route registrations here from main()
+	router.Use(metricsMiddleware())
+	router.Use(authMiddleware())
+	router.GET("/metrics", metricsHandler)
 	router.GET("/api/ping", pingHandler)
+	router.GET("/healthz", healthzHandler)
+	router.GET("/readyz", readyzHandler)
 	router.GET("/api/words", getWordsHandler)
+	router.GET("/api/words/duplicates", getWordDuplicatesHandler)
 	router.GET("/api/words/:id", getWordByIDHandler)
-	router.POST("/api/words", createWordHandler)
-	router.PUT("/api/words/:id", updateWordHandler)
-	router.DELETE("/api/words/:id", deleteWordHandler)
+	router.POST("/api/words", requireRole(models.RoleAdmin), createWordHandler)
+	router.PUT("/api/words/:id", requireRole(models.RoleAdmin), updateWordHandler)
+	router.DELETE("/api/words/:id", requireRole(models.RoleAdmin), deleteWordHandler)
+	router.POST("/api/words/:id/merge", requireRole(models.RoleAdmin), mergeWordHandler)
+	router.GET("/api/words/:id/media", getWordMediaHandler)
+	router.POST("/api/words/:id/media", requireRole(models.RoleAdmin), uploadWordMediaHandler)
+	router.GET("/api/media/:id", getMediaFileHandler)
+	router.DELETE("/api/media/:id", requireRole(models.RoleAdmin), deleteMediaHandler)
+	router.GET("/api/words/:id/tags", getWordTagsHandler)
+	router.PUT("/api/words/:id/tags", requireRole(models.RoleAdmin), setWordTagsHandler)
+	router.GET("/api/tags", getTagsHandler)
+	router.GET("/api/words/:id/sentences", getWordSentencesHandler)
+	router.POST("/api/words/:id/sentences", requireRole(models.RoleAdmin), createWordSentenceHandler)
+	router.POST("/api/words/:id/sentences/generate", requireRole(models.RoleAdmin), generateWordSentencesHandler)
+	router.POST("/api/sentences/import", requireRole(models.RoleAdmin), importSentencesHandler)
+	router.DELETE("/api/sentences/:id", requireRole(models.RoleAdmin), deleteSentenceHandler)
+	router.POST("/api/words/:id/audio/generate", requireRole(models.RoleAdmin), generateWordAudioHandler)
+	router.GET("/api/words/:id/audio/generate", getWordAudioJobHandler)
+	router.GET("/api/languages", getLanguagesHandler)
+	router.POST("/api/languages", requireRole(models.RoleAdmin), createLanguageHandler)
 	router.GET("/api/groups", getGroupsHandler)
 	router.GET("/api/groups/:id", getGroupByIDHandler)
-	router.POST("/api/groups", createGroupHandler)
-	router.PUT("/api/groups/:id", updateGroupHandler)
-	router.DELETE("/api/groups/:id", deleteGroupHandler)
+	router.GET("/api/groups/:id/drills", getGroupDrillsHandler)
+	router.GET("/api/groups/:id/cloze", getGroupClozeHandler)
+	router.POST("/api/groups/:id/generate", requireRole(models.RoleAdmin), generateGroupWordsHandler)
+	router.POST("/api/groups/:id/generate/accept", requireRole(models.RoleAdmin), acceptGroupWordsHandler)
+	router.POST("/api/groups", requireRole(models.RoleAdmin), createGroupHandler)
+	router.PUT("/api/groups/:id", requireRole(models.RoleAdmin), updateGroupHandler)
+	router.DELETE("/api/groups/:id", requireRole(models.RoleAdmin), deleteGroupHandler)
+	router.PUT("/api/groups/:id/words", requireRole(models.RoleAdmin), setGroupWordsHandler)
 	router.GET("/api/study_sessions", getStudySessionsHandler)
 	router.GET("/api/study_sessions/:id", getStudySessionByIDHandler)
 	router.POST("/api/study_sessions", createStudySessionHandler)
 	router.PUT("/api/study_sessions/:id", updateStudySessionHandler)
 	router.DELETE("/api/study_sessions/:id", deleteStudySessionHandler)
+	router.POST("/api/study_sessions/:id/complete", completeStudySessionHandler)
 	router.GET("/api/study_activities", getStudyActivitiesHandler)
 	router.GET("/api/study_activities/:id", getStudyActivityByIDHandler)
-	router.POST("/api/study_activities", createStudyActivityHandler)
-	router.PUT("/api/study_activities/:id", updateStudyActivityHandler)
-	router.DELETE("/api/study_activities/:id", deleteStudyActivityHandler)
+	router.POST("/api/study_activities", requireRole(models.RoleAdmin), createStudyActivityHandler)
+	router.PUT("/api/study_activities/:id", requireRole(models.RoleAdmin), updateStudyActivityHandler)
+	router.DELETE("/api/study_activities/:id", requireRole(models.RoleAdmin), deleteStudyActivityHandler)
 	router.GET("/api/word_review_items", getWordReviewItemsHandler)
 	router.GET("/api/word_review_items/:id", getWordReviewItemByIDHandler)
 	router.POST("/api/word_review_items", createWordReviewItemHandler)
@@ -50,13 +108,56 @@ func SetupRoutes(router *gin.Engine) {
 	router.DELETE("/api/word_review_items/:id", deleteWordReviewItemHandler)
 	router.GET("/api/words_groups", getWordsGroupsHandler)
 	router.GET("/api/words_groups/:id", getWordsGroupsByIDHandler)
-	router.POST("/api/words_groups", createWordsGroupsHandler)
-	router.PUT("/api/words_groups/:id", updateWordsGroupsHandler)
-	router.DELETE("/api/words_groups/:id", deleteWordsGroupsHandler)
+	router.POST("/api/words_groups", requireRole(models.RoleAdmin), createWordsGroupsHandler)
+	router.PUT("/api/words_groups/:id", requireRole(models.RoleAdmin), updateWordsGroupsHandler)
+	router.DELETE("/api/words_groups/:id", requireRole(models.RoleAdmin), deleteWordsGroupsHandler)
 	router.GET("/api/study_sessions/:id/words", getStudySessionWordsHandler)
+	router.GET("/api/study_sessions/:id/report", getStudySessionReportHandler)
 	router.GET("/api/study_sessions/:id/words/raw", getStudySessionWordsRawHandler)
+	router.GET("/api/words_groups/:id/words", getWordsGroupWordsHandler)
 	router.GET("/api/words_groups/:id/study_sessions", getWordGroupStudySessionsHandler)
 	router.GET("/api/words_groups/:id/study_sessions/raw", getWordGroupStudySessionsRawHandler)
+	router.POST("/api/study_sessions/:id/token", createStudySessionTokenHandler)
+	router.POST("/api/study_sessions/:id/reviews", createStudySessionReviewsHandler)
+	router.GET("/api/study_sessions/:id/conjugation_drills", getConjugationDrillsHandler)
+	router.POST("/api/study_sessions/:id/conjugation_answers", createConjugationAnswersHandler)
+	router.POST("/api/study_sessions/:id/cloze_answers", createClozeAnswersHandler)
+	router.POST("/api/external/sessions/:token/reviews", createExternalReviewsHandler)
+	router.GET("/api/stats/words", getWordLatencyStatsHandler)
+	router.GET("/api/stats/words/:id", getWordAnswerStatsHandler)
+	router.GET("/api/stats/accuracy", getAccuracyStatsHandler)
+	router.GET("/api/stats/most_missed_words", getMostMissedWordsHandler)
+	router.GET("/api/stats/group_mastery", getGroupMasteryHandler)
+	router.GET("/api/stats/study_time", getStudyTimeHandler)
+	router.GET("/api/goals", getGoalHandler)
+	router.PUT("/api/goals", updateGoalHandler)
+	router.GET("/api/goals/progress", getGoalProgressHandler)
+	router.GET("/api/goals/events", getGoalEventsHandler)
+	router.GET("/api/dashboard/quick_stats", getQuickStatsHandler)
+	router.GET("/api/users", requireRole(models.RoleTeacher), getUsersHandler)
+	router.GET("/api/users/me", getCurrentUserHandler)
+	router.GET("/api/users/:id", getUserByIDHandler)
+	router.POST("/api/users", requireRole(models.RoleAdmin), createUserHandler)
+	router.PUT("/api/users/me/password", updateOwnPasswordHandler)
+	router.PUT("/api/users/:id/password", requireRole(models.RoleAdmin), updateUserPasswordHandler)
+	router.GET("/api/teacher/progress", requireRole(models.RoleTeacher), getClassProgressHandler)
+	router.GET("/api/classes", requireRole(models.RoleTeacher), getClassesHandler)
+	router.GET("/api/classes/:id", requireRole(models.RoleTeacher), getClassByIDHandler)
+	router.POST("/api/classes", requireRole(models.RoleTeacher), createClassHandler)
+	router.DELETE("/api/classes/:id", requireRole(models.RoleTeacher), deleteClassHandler)
+	router.POST("/api/classes/:id/members", requireRole(models.RoleTeacher), addClassMemberHandler)
+	router.DELETE("/api/classes/:id/members/:user_id", requireRole(models.RoleTeacher), removeClassMemberHandler)
+	router.GET("/api/classes/:id/assignments", requireRole(models.RoleTeacher), getClassAssignmentsHandler)
+	router.POST("/api/classes/:id/assignments", requireRole(models.RoleTeacher), createAssignmentHandler)
+	router.GET("/api/assignments/:id/progress", requireRole(models.RoleTeacher), getAssignmentProgressHandler)
+	router.DELETE("/api/assignments/:id", requireRole(models.RoleTeacher), deleteAssignmentHandler)
+	router.POST("/api/auth/login", loginHandler)
+	router.POST("/api/auth/logout", logoutHandler)
+	router.GET("/api/auth/tokens", getAPITokensHandler)
+	router.POST("/api/auth/tokens", createAPITokenHandler)
+	router.DELETE("/api/auth/tokens/:id", deleteAPITokenHandler)
+	router.POST("/xapi/statements", postXAPIStatementsHandler)
+	router.GET("/xapi/statements", getXAPIStatementsHandler)
 }
 
 func main() {
@@ -64,15 +165,91 @@ func main() {
 	if err := initDB(); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
-	defer dbConn.Close()
+
+	appConfig = loadConfig()
+	tokenSigner = sessiontoken.NewSigner(appConfig.TokenSecret, appConfig.TokenTTL)
+	mediaStore = media.NewStore(appConfig.MediaDir)
+	if appConfig.LLMEndpoint != "" {
+		llmClient = llm.NewClient(appConfig.LLMEndpoint, appConfig.LLMModel, appConfig.LLMAPIKey, appConfig.LLMTimeout)
+	}
+	if err := bootstrapAdmin(appConfig.AdminPassword); err != nil {
+		log.Fatalf("Failed to create admin user: %v", err)
+	}
+
+	// Background workers outlive in-flight requests, which may still hand them work
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	runWorker := func(run func(context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workerCtx)
+		}()
+	}
+
+	// Record missed daily goals
+	runWorker(goals.NewReminder(dbConn, appConfig.GoalCheckInterval, goals.LogNotifier).Run)
+
+	// Close study sessions that learners left without finishing
+	if appConfig.SessionIdleTimeout > 0 {
+		notify := func(closed int) {
+			sessions.LogNotifier(closed)
+			studySessionsFinished.Add(float64(closed), models.StudySessionAbandoned)
+		}
+		runWorker(sessions.NewJanitor(dbConn, appConfig.SessionIdleTimeout, appConfig.SessionSweepInterval, notify).Run)
+	}
+
+	// Generate pronunciation audio when a TTS provider is configured
+	if provider := tts.New(appConfig.TTSEndpoint, appConfig.TTSVoice, appConfig.TTSTimeout); provider != nil {
+		generator := &tts.Generator{DB: dbConn, Store: mediaStore, Provider: provider, MaxBytes: appConfig.MaxUploadBytes}
+		ttsQueue = tts.NewQueue(generator.Generate, ttsQueueSize)
+		runWorker(ttsQueue.Run)
+	}
 
 	router := gin.Default()
 	SetupRoutes(router) // Now uses the shared function
 
-	// Start the server and handle errors
-	if err := router.Run(":5000"); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
-	}
+	server := &http.Server{
+		Addr:              appConfig.ListenAddr,
+		Handler:           router,
+		ReadHeaderTimeout: appConfig.ReadHeaderTimeout,
+		ReadTimeout:       appConfig.ReadTimeout,
+		WriteTimeout:      appConfig.WriteTimeout,
+		IdleTimeout:       appConfig.IdleTimeout,
+		ConnState:         trackConnState,
+	}
+
+	// Serve until the process is interrupted or terminated
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	exitCode := 0
+	select {
+	case err := <-serveErr:
+		log.Println("Failed to start server:", err)
+		exitCode = 1
+	case <-ctx.Done():
+		log.Println("Shutting down, waiting for in-flight requests")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), appConfig.ShutdownTimeout)
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Println("Failed to finish in-flight requests:", err)
+			exitCode = 1
+		}
+		cancel()
+	}
+
+	// Let the workers finish what they are doing before the database is closed
+	stopWorkers()
+	workers.Wait()
+	if err := dbConn.Close(); err != nil {
+		log.Println("Failed to close database:", err)
+		exitCode = 1
+	}
+	os.Exit(exitCode)
 }
 
 func pingHandler(c *gin.Context) {
@@ -82,8 +259,9 @@ func pingHandler(c *gin.Context) {
 }
 
 // getWordsHandler handles the /api/words endpoint.
+// Words can be filtered by language pair, part of speech and gender.
 func getWordsHandler(c *gin.Context) {
-	words, err := db.GetAllWords(dbConn)
+	words, err := db.GetAllWords(dbConn, wordFilterParam(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch words from database"})
 		log.Println("Failed to fetch words:", err)
@@ -160,8 +338,6 @@ func getGroupByIDHandler(c *gin.Context) {
 }
 
 func initDB() error {
-	dbPath := filepath.Join(".", "words.db") // Path to the database file
-
 	// Check if the database file exists
 	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
 		fmt.Println("Database file does not exist. Please run 'mage initdb' to create it.")
@@ -170,7 +346,9 @@ func initDB() error {
 
 	fmt.Println("Connecting to database...")
 	var err error
-	dbConn, err = sql.Open("sqlite3", dbPath) // Open connection to SQLite database
+	// Open the database through a driver that reports query durations to /metrics
+	db.RegisterInstrumentedDriver(instrumentedDriverName, &sqlite3.SQLiteDriver{}, observeQuery)
+	dbConn, err = sql.Open(instrumentedDriverName, dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -186,9 +364,8 @@ func initDB() error {
 
 // createWordHandler handles the POST /api/words endpoint.
 func createWordHandler(c *gin.Context) {
-	var word models.Word
-	if err := c.BindJSON(&word); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	word, ok := bindWord(c, nil)
+	if !ok {
 		return
 	}
 
@@ -204,20 +381,17 @@ func createWordHandler(c *gin.Context) {
 
 // updateWordHandler handles the PUT /api/words/:id endpoint.
 func updateWordHandler(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
+	stored := wordParam(c)
+	if stored == nil {
 		return
 	}
 
-	var word models.Word
-	if err := c.BindJSON(&word); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	word, ok := bindWord(c, stored)
+	if !ok {
 		return
 	}
 
-	word.ID = id
+	word.ID = stored.ID
 
 	if err := db.UpdateWord(dbConn, &word); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update word in database"})
@@ -245,9 +419,56 @@ func deleteWordHandler(c *gin.Context) {
 		return
 	}
 
+	// The word is gone, so failing to clean up its sentences, tags and media is only logged
+	if err := db.DeleteWordSentences(dbConn, id); err != nil {
+		log.Println("Failed to delete word sentences:", err)
+	}
+	if err := db.DeleteWordTags(dbConn, id); err != nil {
+		log.Println("Failed to delete word tags:", err)
+	}
+	hashes, err := db.DeleteWordMedia(dbConn, id)
+	if err != nil {
+		log.Println("Failed to delete word media:", err)
+	}
+	removeUnusedMedia(hashes)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Word deleted successfully"})
 }
 
+// checkGroup writes an error response and returns false when the group has an
+// invalid rule, names a parent group that does not exist or nests a smart group.
+func checkGroup(c *gin.Context, group *models.Group) bool {
+	if group.Rule != nil {
+		if problem := checkGroupRule(group.Rule); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return false
+		}
+	}
+	if group.ParentID == nil {
+		return true
+	}
+	if group.Rule != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Smart groups cannot be nested"})
+		return false
+	}
+
+	parent, err := db.GetGroupByID(dbConn, *group.ParentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group from database"})
+		log.Println("Failed to fetch parent group:", err)
+		return false
+	}
+	if parent == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parent group not found"})
+		return false
+	}
+	if parent.Rule != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Smart groups cannot be nested"})
+		return false
+	}
+	return true
+}
+
 // createGroupHandler handles the POST /api/groups endpoint.
 func createGroupHandler(c *gin.Context) {
 	var group models.Group
@@ -257,6 +478,9 @@ func createGroupHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
+	if !checkGroup(c, &group) {
+		return
+	}
 
 	// Call the db.CreateGroup function to create the group in the database
 	id, err := db.CreateGroup(dbConn, &group)
@@ -287,9 +511,28 @@ func updateGroupHandler(c *gin.Context) {
 	}
 
 	group.ID = id // Set the ID of the group to the ID from the URL
+	if !checkGroup(c, &group) {
+		return
+	}
+	if group.Rule != nil {
+		hasSubgroups, err := db.GroupHasSubgroups(dbConn, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group from database"})
+			log.Println("Failed to check subgroups:", err)
+			return
+		}
+		if hasSubgroups {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Smart groups cannot be nested"})
+			return
+		}
+	}
 
 	// Call the db.UpdateGroup function to update the group in the database
 	if err := db.UpdateGroup(dbConn, &group); err != nil {
+		if errors.Is(err, db.ErrGroupCycle) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A group cannot be nested under itself or one of its subgroups"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update group in database"})
 		log.Println("Failed to update group:", err)
 		return
@@ -318,9 +561,50 @@ func deleteGroupHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Group deleted successfully"})
 }
 
+// setGroupWordsHandler handles the PUT /api/groups/:id/words endpoint.
+// It replaces the group's words with the given set; repeated IDs are ignored.
+func setGroupWordsHandler(c *gin.Context) {
+	var request struct {
+		WordIDs []int `json:"word_ids"`
+	}
+	if err := c.BindJSON(&request); err != nil || request.WordIDs == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "word_ids is required"})
+		return
+	}
+	if len(request.WordIDs) > maxGroupWords {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many words", "max_words": maxGroupWords})
+		return
+	}
+
+	group := groupParam(c)
+	if group == nil || !staticGroup(c, group) {
+		return
+	}
+
+	missing, err := db.GetMissingWordIDs(dbConn, request.WordIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch words from database"})
+		log.Println("Failed to check words:", err)
+		return
+	}
+	if len(missing) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown word IDs", "word_ids": missing})
+		return
+	}
+
+	added, removed, err := db.SetGroupWords(dbConn, group.ID, request.WordIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update group words"})
+		log.Println("Failed to set group words:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Group words updated successfully", "added": added, "removed": removed})
+}
+
 // getStudySessionsHandler handles the /api/study_sessions endpoint.
 func getStudySessionsHandler(c *gin.Context) {
-	studySessions, err := db.GetAllStudySessions(dbConn)
+	studySessions, err := db.GetAllStudySessions(dbConn, currentUser(c).ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study sessions from database"})
 		log.Println("Failed to fetch study sessions:", err)
@@ -347,7 +631,8 @@ func getStudySessionByIDHandler(c *gin.Context) {
 		return
 	}
 
-	if studySession == nil {
+	// Other learners' sessions are reported as missing
+	if studySession == nil || !canViewUser(c, studySession.UserID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
 		return
 	}
@@ -365,6 +650,8 @@ func createStudySessionHandler(c *gin.Context) {
 		return
 	}
 
+	studySession.UserID = currentUser(c).ID
+
 	// Call the db.CreateStudySession function to create the studySession in the database
 	id, err := db.CreateStudySession(dbConn, &studySession)
 	if err != nil {
@@ -373,8 +660,22 @@ func createStudySessionHandler(c *gin.Context) {
 		return
 	}
 
+	studySessionsStarted.Inc()
+
+	// Issue a token so the launched learning app can report results for this session
+	token, expiresAt, err := tokenSigner.Issue(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue session token"})
+		log.Println("Failed to issue session token:", err)
+		return
+	}
+
 	// Return the ID of the newly created studySession in the response
-	c.JSON(http.StatusCreated, gin.H{"id": id})
+	c.JSON(http.StatusCreated, gin.H{
+		"id":               id,
+		"token":            token,
+		"token_expires_at": expiresAt,
+	})
 }
 
 // updateStudySessionHandler handles the PUT /api/study_sessions/:id endpoint.
@@ -394,6 +695,7 @@ func updateStudySessionHandler(c *gin.Context) {
 	}
 
 	studySession.ID = id // Set the ID of the studySession to the ID from the URL
+	studySession.UserID = currentUser(c).ID
 
 	// Call the db.UpdateStudySession function to update the studySession in the database
 	if err := db.UpdateStudySession(dbConn, &studySession); err != nil {
@@ -405,6 +707,49 @@ func updateStudySessionHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Study session updated successfully"})
 }
 
+// completeStudySessionHandler handles the POST /api/study_sessions/:id/complete endpoint.
+// The learner finishes the session now; completing it again changes nothing.
+func completeStudySessionHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid study session ID"})
+		return
+	}
+
+	userID := currentUser(c).ID
+	studySession, err := db.GetStudySessionByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session from database"})
+		log.Println("Failed to fetch study session:", err)
+		return
+	}
+	if studySession == nil || studySession.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
+		return
+	}
+
+	completed, err := db.CompleteStudySession(dbConn, id, userID, time.Now().UTC())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete study session"})
+		log.Println("Failed to complete study session:", err)
+		return
+	}
+	if !completed {
+		c.JSON(http.StatusOK, gin.H{"item": studySession})
+		return
+	}
+	studySessionsFinished.Inc(models.StudySessionCompleted)
+
+	studySession, err = db.GetStudySessionByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session from database"})
+		log.Println("Failed to fetch study session:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"item": studySession})
+}
+
 // deleteStudySessionHandler handles the DELETE /api/study_sessions/:id endpoint.
 func deleteStudySessionHandler(c *gin.Context) {
 	idStr := c.Param("id")
@@ -416,7 +761,7 @@ func deleteStudySessionHandler(c *gin.Context) {
 	}
 
 	// Call the db.DeleteStudySession function to delete the studySession from the database
-	if err := db.DeleteStudySession(dbConn, id); err != nil {
+	if err := db.DeleteStudySession(dbConn, id, currentUser(c).ID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete study session from database"})
 		log.Println("Failed to delete study session:", err)
 		return
@@ -534,7 +879,7 @@ func deleteStudyActivityHandler(c *gin.Context) {
 
 // getWordReviewItemsHandler handles the /api/word_review_items endpoint.
 func getWordReviewItemsHandler(c *gin.Context) {
-	wordReviewItems, err := db.GetAllWordReviewItems(dbConn)
+	wordReviewItems, err := db.GetAllWordReviewItems(dbConn, currentUser(c).ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word review items from database"})
 		log.Println("Failed to fetch word review items:", err)
@@ -561,7 +906,7 @@ func getWordReviewItemByIDHandler(c *gin.Context) {
 		return
 	}
 
-	if wordReviewItem == nil {
+	if wordReviewItem == nil || !canViewUser(c, wordReviewItem.UserID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Word review item not found"})
 		return
 	}
@@ -579,6 +924,18 @@ func createWordReviewItemHandler(c *gin.Context) {
 		return
 	}
 
+	// Reviews can only be recorded in the user's own study sessions
+	session, err := db.GetStudySessionByID(dbConn, wordReviewItem.StudySessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session"})
+		log.Println("Failed to fetch study session:", err)
+		return
+	}
+	if session == nil || session.UserID != currentUser(c).ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
+		return
+	}
+
 	// Call the db.CreateWordReviewItem function to create the wordReviewItem in the database
 	id, err := db.CreateWordReviewItem(dbConn, &wordReviewItem)
 	if err != nil {
@@ -587,6 +944,8 @@ func createWordReviewItemHandler(c *gin.Context) {
 		return
 	}
 
+	reviewsRecorded.Inc()
+
 	// Return the ID of the newly created wordReviewItem in the response
 	c.JSON(http.StatusCreated, gin.H{"id": id})
 }
@@ -608,6 +967,7 @@ func updateWordReviewItemHandler(c *gin.Context) {
 	}
 
 	wordReviewItem.ID = id // Set the ID of the wordReviewItem to the ID from the URL
+	wordReviewItem.UserID = currentUser(c).ID
 
 	// Call the db.UpdateWordReviewItem function to update the wordReviewItem in the database
 	if err := db.UpdateWordReviewItem(dbConn, &wordReviewItem); err != nil {
@@ -630,7 +990,7 @@ func deleteWordReviewItemHandler(c *gin.Context) {
 	}
 
 	// Call the db.DeleteWordReviewItem function to delete the wordReviewItem from the database
-	if err := db.DeleteWordReviewItem(dbConn, id); err != nil {
+	if err := db.DeleteWordReviewItem(dbConn, id, currentUser(c).ID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete word review item from database"})
 		log.Println("Failed to delete word review item:", err)
 		return
@@ -685,9 +1045,16 @@ func createWordsGroupsHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
+	if !staticGroupID(c, wordsGroup.GroupID) {
+		return
+	}
 
 	// Call the db.CreateWordsGroups function to create the wordsGroup in the database
 	id, err := db.CreateWordsGroups(dbConn, &wordsGroup)
+	if errors.Is(err, db.ErrAlreadyMember) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Word is already a member of the group"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create words_groups in database"})
 		log.Println("Failed to create words_groups:", err)
@@ -715,9 +1082,17 @@ func updateWordsGroupsHandler(c *gin.Context) {
 	}
 
 	wordsGroup.ID = id // Set the ID of the wordsGroup to the ID from the URL
+	if !staticGroupID(c, wordsGroup.GroupID) {
+		return
+	}
 
 	// Call the db.UpdateWordsGroups function to update the wordsGroup in the database
-	if err := db.UpdateWordsGroups(dbConn, &wordsGroup); err != nil {
+	err = db.UpdateWordsGroups(dbConn, &wordsGroup)
+	if errors.Is(err, db.ErrAlreadyMember) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Word is already a member of the group"})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update words_groups in database"})
 		log.Println("Failed to update words_groups:", err)
 		return
@@ -746,17 +1121,23 @@ func deleteWordsGroupsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "WordsGroups deleted successfully"})
 }
 
-// getStudySessionWordsHandler handles GET /api/study_sessions/:id/words endpoint
+// getStudySessionWordsHandler handles GET /api/study_sessions/:id/words endpoint.
+// The optional view parameter selects per-word counts (summary) or individual reviews (detail).
 func getStudySessionWordsHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	view := c.Query("view")
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid study session ID"})
 		return
 	}
+	if !validListView(view) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "view must be summary or detail"})
+		return
+	}
 
 	// First verify if the study session exists
 	session, err := db.GetStudySessionByID(dbConn, id)
@@ -764,15 +1145,25 @@ func getStudySessionWordsHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session"})
 		return
 	}
-	if session == nil {
+	if session == nil || !canViewUser(c, session.UserID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
 		return
 	}
 
-	// Get reviewed words for this session with pagination
-	words, totalItems, err := db.GetStudySessionWords(dbConn, id, page, limit)
+	// Get reviewed words for this session with pagination, in the requested view
+	var words interface{}
+	var totalItems int
+	switch view {
+	case "summary":
+		words, totalItems, err = db.FetchStudySessionWords(dbConn, id, page, limit)
+	case "detail":
+		words, totalItems, err = db.GetStudySessionWordsRaw(dbConn, id, page, limit)
+	default:
+		words, totalItems, err = db.GetStudySessionWords(dbConn, id, page, limit)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviewed words"})
+		log.Println("Failed to fetch reviewed words:", err)
 		return
 	}
 
@@ -807,7 +1198,7 @@ func getStudySessionWordsRawHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session"})
 		return
 	}
-	if session == nil {
+	if session == nil || !canViewUser(c, session.UserID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
 		return
 	}
@@ -832,17 +1223,27 @@ func getStudySessionWordsRawHandler(c *gin.Context) {
 	})
 }
 
-// getWordGroupStudySessionsHandler handles GET /api/words_groups/:id/study_sessions endpoint
+// getWordGroupStudySessionsHandler handles GET /api/words_groups/:id/study_sessions endpoint.
+// The optional view parameter selects per-session statistics (summary) or individual reviews (detail).
 func getWordGroupStudySessionsHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	view := c.Query("view")
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
 		return
 	}
+	if !validListView(view) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "view must be summary or detail"})
+		return
+	}
+	userID, ok := statsUserID(c)
+	if !ok {
+		return
+	}
 
 	// First verify if the group exists
 	group, err := db.GetGroupByID(dbConn, id)
@@ -855,10 +1256,20 @@ func getWordGroupStudySessionsHandler(c *gin.Context) {
 		return
 	}
 
-	// Get study sessions for this group with pagination
-	sessions, totalItems, err := db.GetWordGroupStudySessions(dbConn, id, page, limit)
+	// Get study sessions for this group with pagination, in the requested view
+	var sessions interface{}
+	var totalItems int
+	switch view {
+	case "summary":
+		sessions, totalItems, err = db.FetchWordGroupStudySessions(dbConn, userID, id, page, limit)
+	case "detail":
+		sessions, totalItems, err = db.GetWordGroupStudySessionsDetails(dbConn, userID, id, page, limit)
+	default:
+		sessions, totalItems, err = db.GetWordGroupStudySessions(dbConn, userID, id, page, limit)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study sessions"})
+		log.Println("Failed to fetch study sessions:", err)
 		return
 	}
 
@@ -886,6 +1297,10 @@ func getWordGroupStudySessionsRawHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
 		return
 	}
+	userID, ok := statsUserID(c)
+	if !ok {
+		return
+	}
 
 	// First verify if the group exists
 	group, err := db.GetGroupByID(dbConn, id)
@@ -899,9 +1314,10 @@ func getWordGroupStudySessionsRawHandler(c *gin.Context) {
 	}
 
 	// Get raw study sessions for this group with pagination
-	sessions, totalItems, err := db.GetWordGroupStudySessionsRaw(dbConn, id, page, limit)
+	sessions, totalItems, err := db.GetWordGroupStudySessionsRaw(dbConn, userID, id, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study sessions"})
+		log.Println("Failed to fetch group study activities:", err)
 		return
 	}
 
@@ -917,3 +1333,8 @@ func getWordGroupStudySessionsRawHandler(c *gin.Context) {
 		},
 	})
 }
+
+// validListView reports whether view is empty or one of the supported list views.
+func validListView(view string) bool {
+	return view == "" || view == "summary" || view == "detail"
+}
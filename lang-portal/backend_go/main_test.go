@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"backend_go/models"
@@ -20,6 +21,8 @@ func TestWordCRUD(t *testing.T) {
 	db, err := testutils.SetupTestDB()
 	require.NoError(t, err)
 	defer db.Close()
+	dbConn = db
+	appConfig.OpenMode = true
 
 	router := gin.Default()
 	SetupRoutes(router) // You'll need to extract route setup to a separate function
@@ -44,16 +47,16 @@ func TestWordCRUD(t *testing.T) {
 		require.NotZero(t, createResponse.ID)
 
 		// Get word
-		req, _ = http.NewRequest("GET", "/api/words/"+string(createResponse.ID), nil)
+		req, _ = http.NewRequest("GET", "/api/words/"+strconv.Itoa(createResponse.ID), nil)
 		resp = httptest.NewRecorder()
 		router.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusOK, resp.Code)
 
-		var word models.Word
-		json.Unmarshal(resp.Body.Bytes(), &word)
-		assert.Equal(t, "hello", word.English)
-		assert.Equal(t, "olá", word.Portuguese)
+		var getResponse struct{ Item models.Word }
+		json.Unmarshal(resp.Body.Bytes(), &getResponse)
+		assert.Equal(t, "hello", getResponse.Item.English)
+		assert.Equal(t, "olá", getResponse.Item.Portuguese)
 	})
 
 	t.Run("Update word", func(t *testing.T) {
@@ -64,3 +67,165 @@ func TestWordCRUD(t *testing.T) {
 		// Similar structure for delete test
 	})
 }
+
+func TestGroupMembership(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	groupWordIDs := func() []int {
+		var ids []int
+		rows, err := db.Query("SELECT word_id FROM words_groups WHERE group_id = 1 ORDER BY word_id")
+		require.NoError(t, err)
+		defer rows.Close()
+		for rows.Next() {
+			var id int
+			require.NoError(t, rows.Scan(&id))
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	t.Run("Adding a word twice is a conflict", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, request("POST", "/api/words_groups", `{"word_id": 1, "group_id": 1}`).Code)
+		assert.Equal(t, http.StatusConflict, request("POST", "/api/words_groups", `{"word_id": 1, "group_id": 1}`).Code)
+
+		resp := request("POST", "/api/words_groups", `{"word_id": 2, "group_id": 1}`)
+		require.Equal(t, http.StatusCreated, resp.Code)
+		var created struct{ ID int }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+		assert.Equal(t, http.StatusConflict,
+			request("PUT", "/api/words_groups/"+strconv.Itoa(created.ID), `{"word_id": 1, "group_id": 1}`).Code)
+
+		assert.Equal(t, []int{1, 2}, groupWordIDs())
+	})
+
+	t.Run("Membership is replaced by difference", func(t *testing.T) {
+		var keptID int
+		require.NoError(t, db.QueryRow("SELECT id FROM words_groups WHERE word_id = 2 AND group_id = 1").Scan(&keptID))
+
+		resp := request("PUT", "/api/groups/1/words", `{"word_ids": [2, 3, 4, 3]}`)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var body struct{ Added, Removed int }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, 2, body.Added)
+		assert.Equal(t, 1, body.Removed)
+		assert.Equal(t, []int{2, 3, 4}, groupWordIDs())
+
+		var id int
+		require.NoError(t, db.QueryRow("SELECT id FROM words_groups WHERE word_id = 2 AND group_id = 1").Scan(&id))
+		assert.Equal(t, keptID, id, "unchanged memberships keep their rows")
+
+		resp = request("PUT", "/api/groups/1/words", `{"word_ids": [2, 3, 4]}`)
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Zero(t, body.Added)
+		assert.Zero(t, body.Removed)
+	})
+
+	t.Run("Invalid membership updates leave the group unchanged", func(t *testing.T) {
+		resp := request("PUT", "/api/groups/1/words", `{"word_ids": [1, 998, 999, 998]}`)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+		var body struct {
+			WordIDs []int `json:"word_ids"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, []int{998, 999}, body.WordIDs)
+
+		assert.Equal(t, http.StatusBadRequest, request("PUT", "/api/groups/1/words", `{}`).Code)
+		assert.Equal(t, http.StatusNotFound, request("PUT", "/api/groups/999/words", `{"word_ids": []}`).Code)
+		assert.Equal(t, []int{2, 3, 4}, groupWordIDs())
+
+		require.Equal(t, http.StatusOK, request("PUT", "/api/groups/1/words", `{"word_ids": []}`).Code)
+		assert.Empty(t, groupWordIDs())
+	})
+}
+
+func TestGroupHierarchy(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	createGroup := func(body string) int {
+		resp := request("POST", "/api/groups", body)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		var created struct{ ID int }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+		return created.ID
+	}
+	totalWords := func() map[int]int {
+		resp := request("GET", "/api/stats/group_mastery", "")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var body struct{ Items []models.GroupMastery }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		totals := make(map[int]int)
+		for _, group := range body.Items {
+			totals[group.GroupID] = group.TotalWords
+		}
+		return totals
+	}
+
+	restaurant := createGroup(`{"name": "Restaurant", "parent_id": 2}`)
+	ordering := createGroup(`{"name": "Ordering food", "parent_id": ` + strconv.Itoa(restaurant) + `}`)
+
+	t.Run("Parent must exist", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/groups", `{"name": "Orphan", "parent_id": 999}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request("PUT", "/api/groups/1", `{"name": "Basic Vocabulary", "parent_id": 999}`).Code)
+	})
+
+	t.Run("Cycles are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, request("PUT", "/api/groups/2", `{"name": "Travel Phrases", "parent_id": 2}`).Code)
+		assert.Equal(t, http.StatusBadRequest,
+			request("PUT", "/api/groups/2", `{"name": "Travel Phrases", "parent_id": `+strconv.Itoa(ordering)+`}`).Code)
+
+		resp := request("PUT", "/api/groups/2", `{"name": "Travel Phrases", "parent_id": 1}`)
+		assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		assert.Equal(t, http.StatusOK, request("PUT", "/api/groups/2", `{"name": "Travel Phrases"}`).Code)
+	})
+
+	t.Run("Group words include subgroup words", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, request("PUT", "/api/groups/2/words", `{"word_ids": [1]}`).Code)
+		require.Equal(t, http.StatusOK, request("PUT", "/api/groups/"+strconv.Itoa(restaurant)+"/words", `{"word_ids": [1, 2]}`).Code)
+		require.Equal(t, http.StatusOK, request("PUT", "/api/groups/"+strconv.Itoa(ordering)+"/words", `{"word_ids": [3]}`).Code)
+
+		totals := totalWords()
+		assert.Equal(t, 3, totals[2])
+		assert.Equal(t, 3, totals[restaurant])
+		assert.Equal(t, 1, totals[ordering])
+		assert.Equal(t, 0, totals[1])
+	})
+
+	t.Run("Deleting a group moves its subgroups up", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, request("DELETE", "/api/groups/"+strconv.Itoa(restaurant), "").Code)
+
+		resp := request("GET", "/api/groups/"+strconv.Itoa(ordering), "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var body struct{ Item models.Group }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.NotNil(t, body.Item.ParentID)
+		assert.Equal(t, 2, *body.Item.ParentID)
+	})
+}
@@ -0,0 +1,145 @@
+// Package media stores uploaded word audio and images on the local filesystem.
+// Files are content-addressed: each is stored once under its SHA-256 hash, split
+// into two directory levels (ab/cd/abcd...) to keep directories small.
+package media
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Media kinds.
+const (
+	KindAudio = "audio"
+	KindImage = "image"
+)
+
+// sniffLength is how many leading bytes http.DetectContentType looks at.
+const sniffLength = 512
+
+// allowedTypes maps the sniffed MIME types that may be uploaded to the type
+// stored for them and their kind.
+var allowedTypes = map[string]struct{ mimeType, kind string }{
+	"audio/mpeg":      {"audio/mpeg", KindAudio},
+	"audio/wave":      {"audio/wav", KindAudio},
+	"audio/aiff":      {"audio/aiff", KindAudio},
+	"application/ogg": {"audio/ogg", KindAudio},
+	"image/png":       {"image/png", KindImage},
+	"image/jpeg":      {"image/jpeg", KindImage},
+	"image/gif":       {"image/gif", KindImage},
+	"image/webp":      {"image/webp", KindImage},
+}
+
+// hashPattern matches the hex SHA-256 hashes files are stored under.
+var hashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+var (
+	// ErrTooLarge is returned when an upload exceeds the size limit.
+	ErrTooLarge = errors.New("file is too large")
+	// ErrUnsupportedType is returned when an upload is not a supported audio or image format.
+	ErrUnsupportedType = errors.New("unsupported media type")
+)
+
+// Blob describes a stored file.
+type Blob struct {
+	Hash     string
+	Size     int64
+	MIMEType string
+	Kind     string
+}
+
+// Store keeps media files under a root directory.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store rooted at dir. The directory is created on first upload.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Save sniffs the content type of r, rejects unsupported types and files over
+// maxBytes, and stores the content under its hash. Saving content that is
+// already stored reuses the existing file.
+func (s *Store) Save(r io.Reader, maxBytes int64) (*Blob, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	head = head[:n]
+
+	allowed, ok := allowedTypes[http.DetectContentType(head)]
+	if n == 0 || !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once the file has been renamed
+	defer tmp.Close()
+
+	hash := sha256.New()
+	content := io.LimitReader(io.MultiReader(bytes.NewReader(head), r), maxBytes+1)
+	size, err := io.Copy(io.MultiWriter(tmp, hash), content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write upload: %w", err)
+	}
+	if size > maxBytes {
+		return nil, ErrTooLarge
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write upload: %w", err)
+	}
+
+	blob := &Blob{Hash: hex.EncodeToString(hash.Sum(nil)), Size: size, MIMEType: allowed.mimeType, Kind: allowed.kind}
+	path := s.path(blob.Hash)
+	if _, err := os.Stat(path); err == nil {
+		return blob, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to store upload: %w", err)
+	}
+
+	return blob, nil
+}
+
+// Open opens the file stored under hash.
+func (s *Store) Open(hash string) (*os.File, error) {
+	if !hashPattern.MatchString(hash) {
+		return nil, fmt.Errorf("invalid media hash %q", hash)
+	}
+	return os.Open(s.path(hash))
+}
+
+// Remove deletes the file stored under hash. Removing a missing file is not an error.
+func (s *Store) Remove(hash string) error {
+	if !hashPattern.MatchString(hash) {
+		return fmt.Errorf("invalid media hash %q", hash)
+	}
+	if err := os.Remove(s.path(hash)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove media file: %w", err)
+	}
+	return nil
+}
+
+// path returns where the file with the given hash is stored.
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash[2:4], hash)
+}
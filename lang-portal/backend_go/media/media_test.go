@@ -0,0 +1,68 @@
+package media
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is enough of a PNG file for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestSaveAndOpen(t *testing.T) {
+	store := NewStore(t.TempDir())
+	content := append([]byte("ID3\x03\x00\x00\x00"), bytes.Repeat([]byte{0}, 1000)...)
+
+	blob, err := store.Save(bytes.NewReader(content), 2000)
+	require.NoError(t, err)
+	assert.Equal(t, "audio/mpeg", blob.MIMEType)
+	assert.Equal(t, KindAudio, blob.Kind)
+	assert.Equal(t, int64(len(content)), blob.Size)
+	assert.FileExists(t, filepath.Join(store.dir, blob.Hash[:2], blob.Hash[2:4], blob.Hash))
+
+	again, err := store.Save(bytes.NewReader(content), 2000)
+	require.NoError(t, err)
+	assert.Equal(t, blob.Hash, again.Hash)
+
+	f, err := store.Open(blob.Hash)
+	require.NoError(t, err)
+	stored, err := io.ReadAll(f)
+	f.Close()
+	require.NoError(t, err)
+	assert.Equal(t, content, stored)
+
+	require.NoError(t, store.Remove(blob.Hash))
+	require.NoError(t, store.Remove(blob.Hash))
+	_, err = store.Open(blob.Hash)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestSaveRejectsInvalidUploads(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	_, err := store.Save(bytes.NewReader([]byte("just some text")), 100)
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	_, err = store.Save(bytes.NewReader(nil), 100)
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+
+	blob, err := store.Save(bytes.NewReader(pngHeader), 100)
+	require.NoError(t, err)
+	assert.Equal(t, KindImage, blob.Kind)
+
+	_, err = store.Save(bytes.NewReader(append(pngHeader, make([]byte, 100)...)), 100)
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	entries, err := os.ReadDir(store.dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.True(t, entry.IsDir(), "temporary upload %s was left behind", entry.Name())
+	}
+
+	_, err = store.Open("../../etc/passwd")
+	assert.Error(t, err)
+}
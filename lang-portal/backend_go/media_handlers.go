@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"backend_go/db"
+	"backend_go/media"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is allowed on top of the upload limit for multipart
+// boundaries and headers.
+const multipartOverhead = 64 << 10
+
+// wordParam loads the word named by the :id parameter. It writes an error
+// response and returns nil when the word is not found.
+func wordParam(c *gin.Context) *models.Word {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
+		return nil
+	}
+
+	word, err := db.GetWordByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word from database"})
+		log.Println("Failed to fetch word:", err)
+		return nil
+	}
+	if word == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Word not found"})
+		return nil
+	}
+	return word
+}
+
+// mediaParam loads the word media named by the :id parameter. It writes an error
+// response and returns nil when the media is not found.
+func mediaParam(c *gin.Context) *models.WordMedia {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return nil
+	}
+
+	m, err := db.GetWordMediaByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		log.Println("Failed to fetch media:", err)
+		return nil
+	}
+	if m == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return nil
+	}
+	return m
+}
+
+// removeUnusedMedia deletes the stored files for hashes no longer referenced by
+// any word media. Failures are logged; a leftover file is harmless.
+func removeUnusedMedia(hashes []string) {
+	for _, hash := range hashes {
+		inUse, err := db.MediaHashInUse(dbConn, hash)
+		if err != nil {
+			log.Println("Failed to check media usage:", err)
+			continue
+		}
+		if inUse {
+			continue
+		}
+		if err := mediaStore.Remove(hash); err != nil {
+			log.Println("Failed to remove media file:", err)
+		}
+	}
+}
+
+// getWordMediaHandler handles the GET /api/words/:id/media endpoint.
+func getWordMediaHandler(c *gin.Context) {
+	word := wordParam(c)
+	if word == nil {
+		return
+	}
+
+	attachments, err := db.GetWordMedia(dbConn, word.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word media"})
+		log.Println("Failed to fetch word media:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": attachments})
+}
+
+// uploadWordMediaHandler handles the POST /api/words/:id/media endpoint.
+// It accepts a multipart "file" field holding an audio clip or image. The type
+// is sniffed from the content rather than trusted from the client.
+func uploadWordMediaHandler(c *gin.Context) {
+	word := wordParam(c)
+	if word == nil {
+		return
+	}
+
+	tooLarge := gin.H{"error": "File is too large", "max_bytes": appConfig.MaxUploadBytes}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, appConfig.MaxUploadBytes+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file must be uploaded in the \"file\" field"})
+		return
+	}
+	if header.Size > appConfig.MaxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read upload"})
+		log.Println("Failed to open upload:", err)
+		return
+	}
+	defer file.Close()
+
+	blob, err := mediaStore.Save(file, appConfig.MaxUploadBytes)
+	switch {
+	case errors.Is(err, media.ErrTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+		return
+	case errors.Is(err, media.ErrUnsupportedType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "File must be MP3, WAV, AIFF or Ogg audio, or a PNG, JPEG, GIF or WebP image"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store upload"})
+		log.Println("Failed to store upload:", err)
+		return
+	}
+
+	m := models.WordMedia{
+		WordID:       word.ID,
+		Kind:         blob.Kind,
+		MIMEType:     blob.MIMEType,
+		SizeBytes:    blob.Size,
+		ContentHash:  blob.Hash,
+		OriginalName: filepath.Base(header.Filename),
+	}
+	if _, err := db.CreateWordMedia(dbConn, &m); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save media"})
+		log.Println("Failed to save media:", err)
+		removeUnusedMedia([]string{blob.Hash})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"item": m})
+}
+
+// getMediaFileHandler handles the GET /api/media/:id endpoint. It streams the
+// file with support for range requests, so audio players can seek.
+func getMediaFileHandler(c *gin.Context) {
+	m := mediaParam(c)
+	if m == nil {
+		return
+	}
+
+	file, err := mediaStore.Open(m.ContentHash)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read media file"})
+		log.Println("Failed to open media file:", err)
+		return
+	}
+	defer file.Close()
+
+	// Stored files never change, so the content hash is a strong ETag
+	c.Header("Content-Type", m.MIMEType)
+	c.Header("ETag", `"`+m.ContentHash+`"`)
+	c.Header("Cache-Control", "private, max-age=86400")
+	http.ServeContent(c.Writer, c.Request, "", m.CreatedAt, file)
+}
+
+// deleteMediaHandler handles the DELETE /api/media/:id endpoint.
+func deleteMediaHandler(c *gin.Context) {
+	m := mediaParam(c)
+	if m == nil {
+		return
+	}
+
+	if err := db.DeleteWordMediaByID(dbConn, m.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete media"})
+		log.Println("Failed to delete media:", err)
+		return
+	}
+	removeUnusedMedia([]string{m.ContentHash})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Media deleted successfully"})
+}
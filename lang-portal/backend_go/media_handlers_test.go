@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"backend_go/media"
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordMedia(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+	appConfig.MaxUploadBytes = 4096
+	mediaStore = media.NewStore(t.TempDir())
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	upload := func(wordID int, name string, content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", name)
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		req, _ := http.NewRequest("POST", "/api/words/"+strconv.Itoa(wordID)+"/media", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	request := func(method, url string, header http.Header) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	uploaded := func(resp *httptest.ResponseRecorder) models.WordMedia {
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		var body struct{ Item models.WordMedia }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		return body.Item
+	}
+
+	mp3 := append([]byte("ID3\x03\x00\x00\x00"), bytes.Repeat([]byte("a"), 1000)...)
+
+	t.Run("Uploads are sniffed, stored and streamed with range support", func(t *testing.T) {
+		m := uploaded(upload(1, "../ola.mp3", mp3))
+		assert.Equal(t, media.KindAudio, m.Kind)
+		assert.Equal(t, "audio/mpeg", m.MIMEType)
+		assert.Equal(t, int64(len(mp3)), m.SizeBytes)
+		assert.Equal(t, "ola.mp3", m.OriginalName)
+
+		resp := request("GET", "/api/words/1/media", nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var list struct{ Items []models.WordMedia }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		require.Len(t, list.Items, 1)
+
+		mediaURL := "/api/media/" + strconv.Itoa(m.ID)
+		resp = request("GET", mediaURL, nil)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "audio/mpeg", resp.Header().Get("Content-Type"))
+		assert.Equal(t, "bytes", resp.Header().Get("Accept-Ranges"))
+		assert.Equal(t, mp3, resp.Body.Bytes())
+
+		resp = request("GET", mediaURL, http.Header{"Range": {"bytes=0-2"}})
+		require.Equal(t, http.StatusPartialContent, resp.Code)
+		assert.Equal(t, "ID3", resp.Body.String())
+		assert.Equal(t, "bytes 0-2/"+strconv.Itoa(len(mp3)), resp.Header().Get("Content-Range"))
+
+		resp = request("GET", mediaURL, http.Header{"If-None-Match": {`"` + m.ContentHash + `"`}})
+		assert.Equal(t, http.StatusNotModified, resp.Code)
+	})
+
+	t.Run("Invalid uploads are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnsupportedMediaType, upload(1, "notes.mp3", []byte("not really audio")).Code)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, upload(1, "long.mp3", append(mp3, make([]byte, 4096)...)).Code)
+		assert.Equal(t, http.StatusNotFound, upload(999, "ola.mp3", mp3).Code)
+		assert.Equal(t, http.StatusNotFound, request("GET", "/api/media/999", nil).Code)
+	})
+
+	t.Run("Files are removed once no media refers to them", func(t *testing.T) {
+		shared := uploaded(upload(2, "a.mp3", mp3))
+		copied := uploaded(upload(3, "b.mp3", mp3))
+		require.Equal(t, shared.ContentHash, copied.ContentHash)
+
+		require.Equal(t, http.StatusOK, request("DELETE", "/api/media/"+strconv.Itoa(shared.ID), nil).Code)
+		assert.Equal(t, http.StatusNotFound, request("GET", "/api/media/"+strconv.Itoa(shared.ID), nil).Code)
+		f, err := mediaStore.Open(copied.ContentHash)
+		require.NoError(t, err)
+		f.Close()
+
+		require.Equal(t, http.StatusOK, request("DELETE", "/api/words/3", nil).Code)
+		require.Equal(t, http.StatusOK, request("DELETE", "/api/words/1", nil).Code)
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM word_media").Scan(&count))
+		assert.Zero(t, count)
+		_, err = mediaStore.Open(copied.ContentHash)
+		assert.Error(t, err)
+	})
+
+	t.Run("Only admins can upload", func(t *testing.T) {
+		appConfig.OpenMode = false
+		defer func() { appConfig.OpenMode = true }()
+		_, err := db.Exec("INSERT INTO users (id, username, display_name, role, created_at) VALUES (7, 'listener', 'Listener', 'learner', CURRENT_TIMESTAMP)")
+		require.NoError(t, err)
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "ola.mp3")
+		io.Copy(part, bytes.NewReader(mp3))
+		writer.Close()
+		req, _ := http.NewRequest("POST", "/api/words/2/media", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+issueTestToken(t, 7))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
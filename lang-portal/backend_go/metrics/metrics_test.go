@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounter("requests_total", "Requests handled.", "route", "status")
+	latency := registry.NewHistogram("latency_seconds", "Request latency.", []float64{0.1, 1}, "route")
+	registry.NewGauge("open_connections", "Open connections.", func() float64 { return 3 })
+
+	requests.Inc("/words/:id", "200")
+	requests.Add(2, "/words/:id", "200")
+	requests.Inc(`/say "hi"\`, "404")
+	latency.Observe(0.05, "/words")
+	latency.Observe(0.1, "/words")
+	latency.Observe(4, "/words")
+
+	var out strings.Builder
+	require.NoError(t, registry.WriteText(&out))
+	assert.Equal(t, `# HELP requests_total Requests handled.
+# TYPE requests_total counter
+requests_total{route="/say \"hi\"\\",status="404"} 1
+requests_total{route="/words/:id",status="200"} 3
+# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/words",le="0.1"} 2
+latency_seconds_bucket{route="/words",le="1"} 2
+latency_seconds_bucket{route="/words",le="+Inf"} 3
+latency_seconds_sum{route="/words"} 4.15
+latency_seconds_count{route="/words"} 3
+# HELP open_connections Open connections.
+# TYPE open_connections gauge
+open_connections 3
+`, out.String())
+
+	assert.Equal(t, 3.0, requests.Value("/words/:id", "200"))
+	assert.Equal(t, uint64(3), latency.Count("/words"))
+	assert.Panics(t, func() { requests.Inc("/words") })
+	assert.Panics(t, func() { requests.Add(-1, "/words", "200") })
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	jobs := registry.NewCounter("jobs_total", "Jobs run.")
+	registry.NewCounter("failures_total", "Jobs that failed.")
+	jobs.Inc()
+
+	resp := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, ContentType, resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), "\njobs_total 1\n")
+	assert.Contains(t, resp.Body.String(), "\nfailures_total 0\n")
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"backend_go/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// instrumentedDriverName is the SQLite driver that reports query durations.
+const instrumentedDriverName = "sqlite3-instrumented"
+
+// dbQueryBuckets suits SQLite queries, which are mostly well under a millisecond.
+var dbQueryBuckets = []float64{0.0001, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1}
+
+// metricsRegistry holds everything served on /metrics.
+var metricsRegistry = metrics.NewRegistry()
+
+// httpOpenConnections counts client connections the server has not yet closed.
+var httpOpenConnections atomic.Int64
+
+var (
+	httpRequests = metricsRegistry.NewCounter("langportal_http_requests_total",
+		"HTTP requests handled, by method, route and status.", "method", "route", "status")
+	httpRequestDuration = metricsRegistry.NewHistogram("langportal_http_request_duration_seconds",
+		"Time spent handling HTTP requests, by method, route and status.", metrics.DefaultBuckets, "method", "route", "status")
+	_ = metricsRegistry.NewGauge("langportal_http_open_connections",
+		"Open client connections.", func() float64 { return float64(httpOpenConnections.Load()) })
+
+	dbQueryDuration = metricsRegistry.NewHistogram("langportal_db_query_duration_seconds",
+		"Time spent running database statements, including reading their rows, by db package function.", dbQueryBuckets, "function")
+	dbQueryErrors = metricsRegistry.NewCounter("langportal_db_query_errors_total",
+		"Database statements that failed, by db package function.", "function")
+	_ = metricsRegistry.NewGauge("langportal_db_open_connections",
+		"Open database connections, in use or idle.", func() float64 { return float64(dbStats().OpenConnections) })
+	_ = metricsRegistry.NewGauge("langportal_db_in_use_connections",
+		"Database connections currently in use.", func() float64 { return float64(dbStats().InUse) })
+
+	reviewsRecorded = metricsRegistry.NewCounter("langportal_reviews_recorded_total",
+		"Word reviews recorded.")
+	studySessionsStarted = metricsRegistry.NewCounter("langportal_study_sessions_started_total",
+		"Study sessions started.")
+	studySessionsFinished = metricsRegistry.NewCounter("langportal_study_sessions_finished_total",
+		"Study sessions finished, by status: completed by the learner or closed as abandoned.", "status")
+)
+
+// dbStats returns the connection pool statistics, or zeros before the database is opened.
+func dbStats() sql.DBStats {
+	if dbConn == nil {
+		return sql.DBStats{}
+	}
+	return dbConn.Stats()
+}
+
+// observeQuery records a statement run through the instrumented driver.
+func observeQuery(function string, duration time.Duration, err error) {
+	dbQueryDuration.Observe(duration.Seconds(), function)
+	if err != nil && err != driver.ErrSkip {
+		dbQueryErrors.Inc(function)
+	}
+}
+
+// trackConnState keeps httpOpenConnections up to date; it is the server's ConnState hook.
+func trackConnState(_ net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		httpOpenConnections.Add(1)
+	case http.StateClosed, http.StateHijacked:
+		httpOpenConnections.Add(-1)
+	}
+}
+
+// metricsMiddleware counts and times every request. Routes are labelled by their
+// pattern, such as /api/words/:id, so IDs do not create new series.
+func metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.Inc(c.Request.Method, route, status)
+		httpRequestDuration.Observe(time.Since(start).Seconds(), c.Request.Method, route, status)
+	}
+}
+
+// metricsHandler handles the GET /metrics endpoint in the Prometheus text format.
+func metricsHandler(c *gin.Context) {
+	metricsRegistry.Handler().ServeHTTP(c.Writer, c.Request)
+}
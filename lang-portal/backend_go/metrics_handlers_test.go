@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend_go/models"
+	"backend_go/sessiontoken"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+	tokenSigner = sessiontoken.NewSigner([]byte("test-secret"), time.Hour)
+
+	_, err = db.Exec(`INSERT INTO words_groups (word_id, group_id) VALUES (1, 1)`)
+	require.NoError(t, err)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, strings.NewReader(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	started := studySessionsStarted.Value()
+	reviews := reviewsRecorded.Value()
+	completed := studySessionsFinished.Value(models.StudySessionCompleted)
+
+	require.Equal(t, http.StatusOK, request("GET", "/api/words/1", "").Code)
+	require.Equal(t, http.StatusNotFound, request("GET", "/api/words/999", "").Code)
+	require.Equal(t, http.StatusNotFound, request("GET", "/nowhere", "").Code)
+
+	resp := request("POST", "/api/study_sessions", `{"GroupID": 1, "StudyActivityID": 1}`)
+	require.Equal(t, http.StatusCreated, resp.Code)
+	resp = request("POST", "/api/study_sessions/1/reviews", `[{"word_id": 1, "correct": true}]`)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	require.Equal(t, http.StatusOK, request("POST", "/api/study_sessions/1/complete", "").Code)
+	require.Equal(t, http.StatusOK, request("POST", "/api/study_sessions/1/complete", "").Code)
+
+	assert.Equal(t, started+1, studySessionsStarted.Value())
+	assert.Equal(t, reviews+1, reviewsRecorded.Value())
+	assert.Equal(t, completed+1, studySessionsFinished.Value(models.StudySessionCompleted), "completing twice counts once")
+
+	resp = request("GET", "/metrics", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	body := resp.Body.String()
+	assert.Contains(t, body, `langportal_http_requests_total{method="GET",route="/api/words/:id",status="200"}`)
+	assert.Contains(t, body, `langportal_http_requests_total{method="GET",route="/api/words/:id",status="404"}`)
+	assert.Contains(t, body, `langportal_http_requests_total{method="GET",route="unmatched",status="404"}`)
+	assert.Contains(t, body, `langportal_http_request_duration_seconds_bucket{method="POST",route="/api/study_sessions",status="201",le="+Inf"}`)
+	assert.Contains(t, body, "# TYPE langportal_db_open_connections gauge")
+	assert.Contains(t, body, "# TYPE langportal_db_query_duration_seconds histogram")
+	assert.NotContains(t, body, "/api/words/1\"", "routes are labelled by pattern")
+}
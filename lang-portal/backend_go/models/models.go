@@ -2,12 +2,79 @@ package models
 
 import "time"
 
-// Word represents the 'words' table in the database.
+// Word represents the 'words' table in the database: a word in the source
+// language and its translation in the target language.
 type Word struct {
-	ID         int    `json:"id"`
+	ID             int    `json:"id"`
+	SourceLanguage string `json:"source_language"`
+	TargetLanguage string `json:"target_language"`
+	SourceText     string `json:"source_text"`
+	TargetText     string `json:"target_text"`
+	Parts          string `json:"parts"`
+
+	// Grammar holds structured details for the word's part of speech, if any.
+	Grammar *Grammar `json:"grammar"`
+
+	// English and Portuguese mirror SourceText and TargetText for clients written
+	// before words had language pairs.
 	English    string `json:"english"`
 	Portuguese string `json:"portuguese"`
-	Parts      string `json:"parts"`
+}
+
+// Language codes of the language pair every word had before pairs were configurable.
+const (
+	DefaultSourceLanguage = "en"
+	DefaultTargetLanguage = "pt"
+)
+
+// Grammar is the structured grammar of a word, stored as JSON in words.grammar.
+// Which fields are allowed depends on the word's part of speech; see package grammar.
+type Grammar struct {
+	// Gender is "masculine" or "feminine" for nouns.
+	Gender string `json:"gender,omitempty"`
+	// Plural is the plural form of a noun or adjective.
+	Plural string `json:"plural,omitempty"`
+	// Conjugations maps a verb's tense to its forms by person
+	// ("1s", "2s", "3s", "1p", "2p", "3p").
+	Conjugations map[string]map[string]string `json:"conjugations,omitempty"`
+	Examples     []Example                    `json:"examples,omitempty"`
+}
+
+// Example is an example sentence in the target language with its translation.
+type Example struct {
+	Sentence    string `json:"sentence"`
+	Translation string `json:"translation"`
+}
+
+// Drill is a grammar exercise generated from a word's grammar.
+type Drill struct {
+	WordID int    `json:"word_id"`
+	Type   string `json:"type"`
+	Prompt string `json:"prompt"`
+	Answer string `json:"answer"`
+}
+
+// ConjugationDrill asks for one form of a verb. The answer is checked by the
+// server, so it is not included.
+type ConjugationDrill struct {
+	WordID     int    `json:"word_id"`
+	Infinitive string `json:"infinitive"`
+	Tense      string `json:"tense"`
+	Person     string `json:"person"`
+	Prompt     string `json:"prompt"`
+}
+
+// Drill types.
+const (
+	DrillArticle     = "article"
+	DrillPlural      = "plural"
+	DrillConjugation = "conjugation"
+)
+
+// Language represents the 'languages' table.
+type Language struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
 }
 
 // Group represents the 'groups' table.
@@ -15,18 +82,111 @@ type Group struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+
+	// ParentID is the group this group is nested under, if any. A group's words
+	// include the words of all of its descendants.
+	ParentID *int `json:"parent_id"`
+
+	// Rule makes this a smart group: its words are the words matching the rule when
+	// it is used, rather than those listed in words_groups. Smart groups are not nested.
+	Rule *GroupRule `json:"rule"`
+}
+
+// DueAfterDays is how long after its latest correct answer a word is due for review again.
+const DueAfterDays = 7
+
+// GroupRule selects the words of a smart group. Every condition that is set must
+// hold. Review conditions look at the history of the learner using the group.
+type GroupRule struct {
+	SourceLanguage string `json:"source_language,omitempty"`
+	TargetLanguage string `json:"target_language,omitempty"`
+	PartOfSpeech   string `json:"part_of_speech,omitempty"`
+	Gender         string `json:"gender,omitempty"`
+
+	// Tags must all be on the word.
+	Tags []string `json:"tags,omitempty"`
+
+	// MinAccuracy and MaxAccuracy bound the percentage of the word's reviews that
+	// were answered correctly. Words that were never reviewed match neither.
+	MinAccuracy *float64 `json:"min_accuracy,omitempty"`
+	MaxAccuracy *float64 `json:"max_accuracy,omitempty"`
+
+	// WrongWithinDays matches words answered incorrectly in the last that many days,
+	// ReviewedWithinDays words reviewed in them and NotReviewedForDays words not
+	// reviewed in them, including words never reviewed.
+	WrongWithinDays    int `json:"wrong_within_days,omitempty"`
+	ReviewedWithinDays int `json:"reviewed_within_days,omitempty"`
+	NotReviewedForDays int `json:"not_reviewed_for_days,omitempty"`
+
+	// Due matches words that are due for review when true and words that are not
+	// when false. A word is due when it was never reviewed, its latest answer was
+	// wrong or it was last reviewed DueAfterDays or more ago.
+	Due *bool `json:"due,omitempty"`
+}
+
+// User represents the 'users' table.
+type User struct {
+	ID          int       `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// PasswordHash is only loaded when checking credentials and never serialized.
+	PasswordHash string `json:"-"`
+}
+
+// User roles. Learners record reviews, teachers can see every learner's progress
+// and admins can do everything, including editing vocabulary.
+const (
+	RoleLearner = "learner"
+	RoleTeacher = "teacher"
+	RoleAdmin   = "admin"
+)
+
+// AuthToken represents the 'auth_tokens' table. The token itself is never stored.
+type AuthToken struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Kind       string     `json:"kind"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
 }
 
+// Auth token kinds: browser login sessions and personal API tokens.
+const (
+	TokenKindSession = "session"
+	TokenKindAPI     = "api"
+)
+
+// DefaultUserID is the learner that owned all data before multi-user support.
+// Requests that do not identify a user act as this user.
+const DefaultUserID = 1
+
 // StudySession represents the 'study_sessions' table.
 type StudySession struct {
 	ID              int `json:"id"`
+	UserID          int `json:"user_id"`
 	GroupID         int
 	CreatedAt       string
 	StudyActivityID int
 	StartedAt       time.Time `json:"started_at"`
-	EndedAt         time.Time `json:"ended_at"`
+	// Status is one of the StudySession* statuses. EndedAt is set once the session
+	// is no longer active.
+	Status  string     `json:"status"`
+	EndedAt *time.Time `json:"ended_at"`
 }
 
+// Study session statuses. Active sessions that receive no reviews for a while are
+// closed as abandoned, ending at their last review.
+const (
+	StudySessionActive    = "active"
+	StudySessionCompleted = "completed"
+	StudySessionAbandoned = "abandoned"
+)
+
 // StudyActivity represents the 'study_activities' table.
 type StudyActivity struct {
 	ID             int       `json:"id"`
@@ -39,10 +199,45 @@ type StudyActivity struct {
 // WordReviewItem represents the 'word_review_items' table.
 type WordReviewItem struct {
 	ID             int       `json:"id"`
+	UserID         int       `json:"user_id"`
 	WordID         int       `json:"word_id"`
 	StudySessionID int       `json:"study_session_id"`
 	Correct        bool      `json:"correct"`
 	CreatedAt      time.Time `json:"created_at"`
+	ResponseMS     *int      `json:"response_ms,omitempty"`
+	GivenAnswer    string    `json:"given_answer,omitempty"`
+	Direction      string    `json:"direction,omitempty"`
+	ActivityType   string    `json:"activity_type,omitempty"`
+}
+
+// Review directions stored in word_review_items.direction. A forward review shows
+// the source text and asks for the target text; a reverse review asks the other way.
+const (
+	DirectionForward = "forward"
+	DirectionReverse = "reverse"
+)
+
+// Legacy review directions, accepted from clients written before words carried a
+// language pair. English to Portuguese is forward for those words.
+const (
+	DirectionEnglishToPortuguese = "en_pt"
+	DirectionPortugueseToEnglish = "pt_en"
+)
+
+// ActivityTypeConjugation marks reviews recorded from conjugation drill answers.
+const ActivityTypeConjugation = "conjugation"
+
+// ActivityTypeCloze marks reviews recorded from cloze exercise answers.
+const ActivityTypeCloze = "cloze"
+
+// StudySessionTotals summarizes the reviews recorded in a study session.
+type StudySessionTotals struct {
+	StudySessionID int     `json:"study_session_id"`
+	ReviewCount    int     `json:"review_count"`
+	TotalWords     int     `json:"total_words"`
+	CorrectCount   int     `json:"correct_count"`
+	IncorrectCount int     `json:"incorrect_count"`
+	SuccessRate    float64 `json:"success_rate"`
 }
 
 // WordsGroups represents a words_groups in the database.
@@ -51,3 +246,361 @@ type WordsGroups struct {
 	WordID  int `json:"word_id"`
 	GroupID int `json:"group_id"`
 }
+
+// XAPIStatementRecord pairs a word review item with the xAPI statement describing it.
+type XAPIStatementRecord struct {
+	StatementID    string
+	Actor          string
+	Review         WordReviewItem
+	SourceLanguage string
+	TargetLanguage string
+	SourceText     string
+	TargetText     string
+}
+
+// WordLatencyStats summarizes how quickly and how well a word is answered.
+// Average response times are nil when no review recorded a response time.
+type WordLatencyStats struct {
+	WordID                 int      `json:"word_id"`
+	English                string   `json:"english"`
+	Portuguese             string   `json:"portuguese"`
+	ReviewCount            int      `json:"review_count"`
+	CorrectCount           int      `json:"correct_count"`
+	AvgResponseMS          *float64 `json:"avg_response_ms"`
+	AvgCorrectResponseMS   *float64 `json:"avg_correct_response_ms"`
+	AvgIncorrectResponseMS *float64 `json:"avg_incorrect_response_ms"`
+}
+
+// WrongAnswerCount is a wrong answer and how often it was given.
+type WrongAnswerCount struct {
+	Answer string `json:"answer"`
+	Count  int    `json:"count"`
+}
+
+// WordAnswerStats adds the most common wrong answers to a word's latency stats.
+type WordAnswerStats struct {
+	WordLatencyStats
+	CommonWrongAnswers []WrongAnswerCount `json:"common_wrong_answers"`
+}
+
+// AccuracyPoint is the review accuracy for one day or week.
+type AccuracyPoint struct {
+	Period         string  `json:"period"`
+	ReviewCount    int     `json:"review_count"`
+	CorrectCount   int     `json:"correct_count"`
+	IncorrectCount int     `json:"incorrect_count"`
+	Accuracy       float64 `json:"accuracy"`
+}
+
+// MissedWord is a word ranked by how often it was answered incorrectly.
+type MissedWord struct {
+	WordID         int     `json:"word_id"`
+	English        string  `json:"english"`
+	Portuguese     string  `json:"portuguese"`
+	ReviewCount    int     `json:"review_count"`
+	IncorrectCount int     `json:"incorrect_count"`
+	ErrorRate      float64 `json:"error_rate"`
+}
+
+// GroupMastery is the share of a group's words answered correctly several times in a row.
+type GroupMastery struct {
+	GroupID           int     `json:"group_id"`
+	Name              string  `json:"name"`
+	TotalWords        int     `json:"total_words"`
+	MasteredWords     int     `json:"mastered_words"`
+	MasteryPercentage float64 `json:"mastery_percentage"`
+}
+
+// StudyTimeDay is the time spent studying on one day.
+type StudyTimeDay struct {
+	Date         string  `json:"date"`
+	SessionCount int     `json:"session_count"`
+	ReviewCount  int     `json:"review_count"`
+	StudyMinutes float64 `json:"study_minutes"`
+}
+
+// SessionWordStats is a word reviewed in a study session with its answer counts.
+type SessionWordStats struct {
+	ID             int    `json:"id"`
+	English        string `json:"english"`
+	Portuguese     string `json:"portuguese"`
+	CorrectCount   int    `json:"correct_count"`
+	IncorrectCount int    `json:"incorrect_count"`
+}
+
+// GroupStudySessionSummary is a group's study session with aggregated review statistics.
+type GroupStudySessionSummary struct {
+	ID              int       `json:"id"`
+	CreatedAt       time.Time `json:"created_at"`
+	ActivityName    string    `json:"activity_name"`
+	TotalWords      int       `json:"total_words"`
+	CorrectCount    int       `json:"correct_count"`
+	IncorrectCount  int       `json:"incorrect_count"`
+	SuccessRate     float64   `json:"success_rate"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Status          string    `json:"status"`
+}
+
+// GroupStudySessionReview is a single review made in one of a group's study sessions.
+type GroupStudySessionReview struct {
+	ID              int       `json:"id"`
+	CreatedAt       time.Time `json:"created_at"`
+	ActivityName    string    `json:"activity_name"`
+	WordID          int       `json:"word_id"`
+	English         string    `json:"english"`
+	Portuguese      string    `json:"portuguese"`
+	IsCorrect       bool      `json:"is_correct"`
+	ReviewCreatedAt time.Time `json:"review_created_at"`
+}
+
+// Goal represents the 'goals' table: the learner's daily review target.
+type Goal struct {
+	UserID            int       `json:"user_id"`
+	DailyReviewTarget int       `json:"daily_review_target"`
+	TimeZone          string    `json:"time_zone"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// GoalEvent represents the 'goal_events' table.
+type GoalEvent struct {
+	ID                int       `json:"id"`
+	UserID            int       `json:"user_id"`
+	EventType         string    `json:"event_type"`
+	Day               string    `json:"day"`
+	ReviewCount       int       `json:"review_count"`
+	DailyReviewTarget int       `json:"daily_review_target"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// GoalEventMissedDay marks a day on which the daily review target was not reached.
+const GoalEventMissedDay = "missed_day"
+
+// GoalProgress is today's progress towards the daily goal plus study streaks.
+type GoalProgress struct {
+	Date              string  `json:"date"`
+	TimeZone          string  `json:"time_zone"`
+	DailyReviewTarget int     `json:"daily_review_target"`
+	ReviewsToday      int     `json:"reviews_today"`
+	Remaining         int     `json:"remaining"`
+	Completed         bool    `json:"completed"`
+	CurrentStreak     int     `json:"current_streak"`
+	LongestStreak     int     `json:"longest_streak"`
+	LastStudyDate     *string `json:"last_study_date"`
+}
+
+// ReviewBucket counts the reviews a user made in one quarter hour starting at Start.
+// Every time zone offset is a whole number of quarter hours, so buckets never
+// straddle a local midnight.
+type ReviewBucket struct {
+	Start time.Time
+	Count int
+}
+
+// QuickStats holds the dashboard's key learning indicators.
+type QuickStats struct {
+	SuccessRate        float64    `json:"success_rate"`
+	TotalWordsStudied  int        `json:"total_words_studied"`
+	TotalWordsCorrect  int        `json:"total_words_correct"`
+	TotalStudySessions int        `json:"total_study_sessions"`
+	TotalActiveGroups  int        `json:"total_active_groups"`
+	CurrentStreak      int        `json:"current_streak"`
+	LongestStreak      int        `json:"longest_streak"`
+	LastStudyDate      *time.Time `json:"last_study_date"`
+}
+
+// LearnerProgress summarizes one learner's study activity for teachers.
+type LearnerProgress struct {
+	UserID        int        `json:"user_id"`
+	Username      string     `json:"username"`
+	DisplayName   string     `json:"display_name"`
+	StudySessions int        `json:"study_sessions"`
+	ReviewCount   int        `json:"review_count"`
+	CorrectCount  int        `json:"correct_count"`
+	WordsStudied  int        `json:"words_studied"`
+	Accuracy      float64    `json:"accuracy"`
+	LastStudyDate *time.Time `json:"last_study_date"`
+}
+
+// ClassProgress aggregates the progress of every learner.
+type ClassProgress struct {
+	Learners       int     `json:"learners"`
+	ActiveLearners int     `json:"active_learners"`
+	StudySessions  int     `json:"study_sessions"`
+	ReviewCount    int     `json:"review_count"`
+	Accuracy       float64 `json:"accuracy"`
+}
+
+// Class represents the 'classes' table: a teacher's group of learners.
+type Class struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	TeacherID   int       `json:"teacher_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	MemberCount int       `json:"member_count"`
+}
+
+// Assignment represents the 'assignments' table. TargetAccuracy is a percentage.
+type Assignment struct {
+	ID             int       `json:"id"`
+	ClassID        int       `json:"class_id"`
+	GroupID        int       `json:"group_id"`
+	GroupName      string    `json:"group_name"`
+	DueAt          time.Time `json:"due_at"`
+	TargetAccuracy float64   `json:"target_accuracy"`
+	TargetSessions int       `json:"target_sessions"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AssignmentProgress summarizes one class member's work on an assignment.
+// Only sessions on the assigned group between the assignment's creation and
+// due date that recorded reviews count.
+type AssignmentProgress struct {
+	UserID        int    `json:"user_id"`
+	Username      string `json:"username"`
+	DisplayName   string `json:"display_name"`
+	StudySessions int    `json:"study_sessions"`
+	// SessionsMet counts the sessions whose own accuracy reached the target.
+	SessionsMet   int        `json:"sessions_met"`
+	ReviewCount   int        `json:"review_count"`
+	CorrectCount  int        `json:"correct_count"`
+	Accuracy      float64    `json:"accuracy"`
+	LastStudyDate *time.Time `json:"last_study_date"`
+	Status        string     `json:"status"`
+}
+
+// Assignment progress statuses.
+const (
+	AssignmentNotStarted = "not_started"
+	AssignmentInProgress = "in_progress"
+	AssignmentCompleted  = "completed"
+	AssignmentOverdue    = "overdue"
+)
+
+// WordMedia represents the 'word_media' table: an audio clip or image attached to
+// a word. The file itself is stored on disk under its content hash.
+type WordMedia struct {
+	ID           int       `json:"id"`
+	WordID       int       `json:"word_id"`
+	Kind         string    `json:"kind"`
+	MIMEType     string    `json:"mime_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	ContentHash  string    `json:"content_hash"`
+	OriginalName string    `json:"original_name"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Sentence represents the 'sentences' table: an example sentence in the word's
+// target language, with its translation into the source language.
+type Sentence struct {
+	ID          int       `json:"id"`
+	WordID      int       `json:"word_id"`
+	Text        string    `json:"text"`
+	Translation string    `json:"translation"`
+	Origin      string    `json:"origin"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Sentence origins: added by hand, bulk imported or generated by an LLM.
+const (
+	SentenceOriginManual = "manual"
+	SentenceOriginImport = "import"
+	SentenceOriginLLM    = "llm"
+)
+
+// ClozeExercise is a sentence with the practised word blanked out. The expected
+// answer is kept on the server.
+type ClozeExercise struct {
+	SentenceID  int    `json:"sentence_id"`
+	WordID      int    `json:"word_id"`
+	Prompt      string `json:"prompt"`
+	Translation string `json:"translation"`
+	Hint        string `json:"hint"`
+}
+
+// DuplicatePair is two words that look like the same entry. Word is the one with
+// the lower ID, usually the one to keep.
+type DuplicatePair struct {
+	Word      Word   `json:"word"`
+	Duplicate Word   `json:"duplicate"`
+	Reason    string `json:"reason"`
+	Distance  int    `json:"distance"`
+}
+
+// MergeResult counts the records moved from a duplicate word to the survivor.
+type MergeResult struct {
+	Groups    int `json:"groups"`
+	Reviews   int `json:"reviews"`
+	Sentences int `json:"sentences"`
+	Media     int `json:"media"`
+	Tags      int `json:"tags"`
+}
+
+// TagCount is a word tag and how many words carry it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Words int    `json:"words"`
+}
+
+// SessionReport summarizes a finished study session for the learner.
+type SessionReport struct {
+	StudySessionID int                `json:"study_session_id"`
+	GroupID        int                `json:"group_id"`
+	GroupName      string             `json:"group_name"`
+	StartedAt      *time.Time         `json:"started_at"`
+	EndedAt        *time.Time         `json:"ended_at"`
+	Totals         StudySessionTotals `json:"totals"`
+
+	// AverageResponseMS averages the answers that recorded a response time.
+	AverageResponseMS *int `json:"average_response_ms"`
+
+	Timeline      []SessionAnswer        `json:"timeline"`
+	PartsOfSpeech []PartOfSpeechAccuracy `json:"parts_of_speech"`
+
+	// Learned words were last answered correctly in the session after being missed
+	// or never reviewed before it; Lapsed words were last answered incorrectly after
+	// being known before it.
+	Learned []SessionWord `json:"learned"`
+	Lapsed  []SessionWord `json:"lapsed"`
+
+	// Suggested words from the group are worth studying next.
+	Suggested []SessionWord `json:"suggested"`
+}
+
+// SessionAnswer is one answer in a session report's timeline.
+type SessionAnswer struct {
+	ReviewID     int       `json:"review_id"`
+	WordID       int       `json:"word_id"`
+	SourceText   string    `json:"source_text"`
+	TargetText   string    `json:"target_text"`
+	Parts        string    `json:"parts"`
+	Correct      bool      `json:"correct"`
+	GivenAnswer  string    `json:"given_answer,omitempty"`
+	Direction    string    `json:"direction,omitempty"`
+	ActivityType string    `json:"activity_type,omitempty"`
+	AnsweredAt   time.Time `json:"answered_at"`
+	ResponseMS   *int      `json:"response_ms,omitempty"`
+}
+
+// PartOfSpeechAccuracy is the accuracy of a session's answers for one part of speech.
+type PartOfSpeechAccuracy struct {
+	PartOfSpeech string  `json:"part_of_speech"`
+	ReviewCount  int     `json:"review_count"`
+	CorrectCount int     `json:"correct_count"`
+	Accuracy     float64 `json:"accuracy"`
+}
+
+// Reasons a word is suggested in a session report.
+const (
+	SuggestionMissed = "missed"
+	SuggestionNew    = "new"
+	SuggestionStale  = "stale"
+)
+
+// SessionWord is a word listed in a session report.
+type SessionWord struct {
+	WordID     int    `json:"word_id"`
+	SourceText string `json:"source_text"`
+	TargetText string `json:"target_text"`
+	Reason     string `json:"reason,omitempty"`
+}
@@ -0,0 +1,177 @@
+// Package report builds the report shown to a learner after a study session and
+// renders it as a printable HTML page.
+package report
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"backend_go/grammar"
+	"backend_go/models"
+)
+
+// DefaultSuggestions is how many words a report suggests studying next.
+const DefaultSuggestions = 10
+
+// unknownPartOfSpeech labels answers to words without a part of speech.
+const unknownPartOfSpeech = "other"
+
+// Input is what a session report is built from.
+type Input struct {
+	Session   models.StudySession
+	GroupName string
+	Totals    models.StudySessionTotals
+
+	// Answers are the session's answers in the order they were given.
+	Answers []models.SessionAnswer
+
+	// PriorOutcomes holds, for words answered before the session, whether the
+	// latest earlier answer was correct.
+	PriorOutcomes map[int]bool
+
+	// GroupWords and LastReviewed, the learner's latest review time per word,
+	// choose the suggested words.
+	GroupWords     []models.Word
+	LastReviewed   map[int]time.Time
+	MaxSuggestions int
+}
+
+// Build assembles the session report.
+func Build(in Input) *models.SessionReport {
+	report := &models.SessionReport{
+		StudySessionID: in.Session.ID,
+		GroupID:        in.Session.GroupID,
+		GroupName:      in.GroupName,
+		Totals:         in.Totals,
+		Timeline:       in.Answers,
+		PartsOfSpeech:  partsOfSpeech(in.Answers),
+		Learned:        []models.SessionWord{},
+		Lapsed:         []models.SessionWord{},
+	}
+	if report.Timeline == nil {
+		report.Timeline = []models.SessionAnswer{}
+	}
+	if len(in.Answers) > 0 {
+		started, ended := in.Answers[0].AnsweredAt, in.Answers[len(in.Answers)-1].AnsweredAt
+		report.StartedAt, report.EndedAt = &started, &ended
+	}
+
+	var responseTotal, responseCount int
+	for _, answer := range in.Answers {
+		if answer.ResponseMS != nil {
+			responseTotal += *answer.ResponseMS
+			responseCount++
+		}
+	}
+	if responseCount > 0 {
+		average := int(math.Round(float64(responseTotal) / float64(responseCount)))
+		report.AverageResponseMS = &average
+	}
+
+	// A word's outcome in the session is its last answer
+	var answered []models.SessionAnswer
+	final := make(map[int]bool)
+	for _, answer := range in.Answers {
+		if _, seen := final[answer.WordID]; !seen {
+			answered = append(answered, answer)
+		}
+		final[answer.WordID] = answer.Correct
+	}
+
+	missed := []models.SessionWord{}
+	for _, answer := range answered {
+		word := models.SessionWord{WordID: answer.WordID, SourceText: answer.SourceText, TargetText: answer.TargetText}
+		knewBefore, seenBefore := in.PriorOutcomes[answer.WordID]
+		switch {
+		case final[answer.WordID] && !knewBefore:
+			report.Learned = append(report.Learned, word)
+		case !final[answer.WordID] && seenBefore && knewBefore:
+			report.Lapsed = append(report.Lapsed, word)
+		}
+		if !final[answer.WordID] {
+			word.Reason = models.SuggestionMissed
+			missed = append(missed, word)
+		}
+	}
+
+	report.Suggested = suggest(missed, in.GroupWords, final, in.LastReviewed, in.MaxSuggestions)
+	return report
+}
+
+// partsOfSpeech groups the answers' accuracy by the part of speech of their words.
+func partsOfSpeech(answers []models.SessionAnswer) []models.PartOfSpeechAccuracy {
+	byPart := make(map[string]*models.PartOfSpeechAccuracy)
+	for _, answer := range answers {
+		part := grammar.PartOfSpeech(answer.Parts)
+		if part == "" {
+			part = unknownPartOfSpeech
+		}
+		accuracy, ok := byPart[part]
+		if !ok {
+			accuracy = &models.PartOfSpeechAccuracy{PartOfSpeech: part}
+			byPart[part] = accuracy
+		}
+		accuracy.ReviewCount++
+		if answer.Correct {
+			accuracy.CorrectCount++
+		}
+	}
+
+	parts := make([]models.PartOfSpeechAccuracy, 0, len(byPart))
+	for _, accuracy := range byPart {
+		accuracy.Accuracy = math.Round(float64(accuracy.CorrectCount)*1000/float64(accuracy.ReviewCount)) / 10
+		parts = append(parts, *accuracy)
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartOfSpeech < parts[j].PartOfSpeech })
+	return parts
+}
+
+// suggest picks up to limit words to study next: the words missed in the session,
+// then group words never reviewed, then the group words reviewed longest ago.
+// Words answered correctly in the session are left out.
+func suggest(missed []models.SessionWord, groupWords []models.Word, final map[int]bool, lastReviewed map[int]time.Time, limit int) []models.SessionWord {
+	if limit <= 0 {
+		limit = DefaultSuggestions
+	}
+
+	var unseen, stale []models.SessionWord
+	for _, word := range groupWords {
+		if _, answered := final[word.ID]; answered {
+			continue
+		}
+		suggestion := models.SessionWord{WordID: word.ID, SourceText: word.SourceText, TargetText: word.TargetText}
+		if _, reviewed := lastReviewed[word.ID]; reviewed {
+			suggestion.Reason = models.SuggestionStale
+			stale = append(stale, suggestion)
+		} else {
+			suggestion.Reason = models.SuggestionNew
+			unseen = append(unseen, suggestion)
+		}
+	}
+	sort.SliceStable(stale, func(i, j int) bool {
+		return lastReviewed[stale[i].WordID].Before(lastReviewed[stale[j].WordID])
+	})
+
+	suggestions := append(append(missed, unseen...), stale...)
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+//go:embed session.html
+var templates embed.FS
+
+// page is the printable HTML session report.
+var page = template.Must(template.New("session.html").Funcs(template.FuncMap{
+	"datetime": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04:05") },
+}).ParseFS(templates, "session.html"))
+
+// WriteHTML renders the report as a standalone, printable HTML page.
+func WriteHTML(w io.Writer, report *models.SessionReport) error {
+	return page.Execute(w, report)
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"backend_go/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	ms := func(n int) *int { return &n }
+	answer := func(wordID int, target, parts string, correct bool, offset time.Duration, responseMS *int) models.SessionAnswer {
+		return models.SessionAnswer{WordID: wordID, SourceText: "src" + target, TargetText: target, Parts: parts,
+			Correct: correct, AnsweredAt: start.Add(offset), ResponseMS: responseMS}
+	}
+
+	report := Build(Input{
+		Session:   models.StudySession{ID: 7, GroupID: 2},
+		GroupName: "Travel",
+		Answers: []models.SessionAnswer{
+			answer(1, "falar", "verb", false, 0, ms(1200)),
+			answer(1, "falar", "verb", true, time.Minute, ms(800)),
+			answer(2, "casa", "Noun", false, 2*time.Minute, nil),
+			answer(3, "sim", "", true, 3*time.Minute, ms(400)),
+		},
+		// falar was missed before, casa was known, sim is new
+		PriorOutcomes: map[int]bool{1: false, 2: true},
+		GroupWords: []models.Word{
+			{ID: 1, TargetText: "falar"}, {ID: 2, TargetText: "casa"}, {ID: 3, TargetText: "sim"},
+			{ID: 4, TargetText: "comer"}, {ID: 5, TargetText: "beber"}, {ID: 6, TargetText: "rua"},
+		},
+		LastReviewed:   map[int]time.Time{4: start.AddDate(0, 0, -1), 5: start.AddDate(0, 0, -9)},
+		MaxSuggestions: 3,
+	})
+
+	assert.Equal(t, "Travel", report.GroupName)
+	require.NotNil(t, report.StartedAt)
+	assert.Equal(t, start, *report.StartedAt)
+	assert.Equal(t, start.Add(3*time.Minute), *report.EndedAt)
+	require.NotNil(t, report.AverageResponseMS)
+	assert.Equal(t, 800, *report.AverageResponseMS)
+	assert.Len(t, report.Timeline, 4)
+
+	assert.Equal(t, []models.PartOfSpeechAccuracy{
+		{PartOfSpeech: "noun", ReviewCount: 1, CorrectCount: 0, Accuracy: 0},
+		{PartOfSpeech: "other", ReviewCount: 1, CorrectCount: 1, Accuracy: 100},
+		{PartOfSpeech: "verb", ReviewCount: 2, CorrectCount: 1, Accuracy: 50},
+	}, report.PartsOfSpeech)
+
+	wordIDs := func(words []models.SessionWord) []int {
+		ids := []int{}
+		for _, word := range words {
+			ids = append(ids, word.WordID)
+		}
+		return ids
+	}
+	assert.Equal(t, []int{1, 3}, wordIDs(report.Learned))
+	assert.Equal(t, []int{2}, wordIDs(report.Lapsed))
+
+	// Missed first, then never reviewed, then reviewed longest ago
+	assert.Equal(t, []int{2, 6, 5}, wordIDs(report.Suggested))
+	assert.Equal(t, []string{models.SuggestionMissed, models.SuggestionNew, models.SuggestionStale},
+		[]string{report.Suggested[0].Reason, report.Suggested[1].Reason, report.Suggested[2].Reason})
+}
+
+func TestBuildEmptySession(t *testing.T) {
+	report := Build(Input{Session: models.StudySession{ID: 1}})
+	assert.Nil(t, report.StartedAt)
+	assert.Nil(t, report.AverageResponseMS)
+	assert.Empty(t, report.Timeline)
+	assert.NotNil(t, report.Timeline)
+	assert.Empty(t, report.Suggested)
+}
+
+func TestWriteHTML(t *testing.T) {
+	at := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	report := Build(Input{
+		Session:   models.StudySession{ID: 7},
+		GroupName: "Travel <b>phrases</b>",
+		Answers: []models.SessionAnswer{
+			{WordID: 1, SourceText: "to speak", TargetText: "falar", Parts: "verb", GivenAnswer: "<script>", AnsweredAt: at},
+		},
+	})
+
+	var page bytes.Buffer
+	require.NoError(t, WriteHTML(&page, report))
+	html := page.String()
+	assert.Contains(t, html, "Travel &lt;b&gt;phrases&lt;/b&gt;")
+	assert.Contains(t, html, "&lt;script&gt;")
+	assert.NotContains(t, html, "<script>")
+	assert.Contains(t, html, "2025-03-01 10:00:00")
+	assert.Contains(t, html, "@media print")
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend_go/db"
+	"backend_go/models"
+	"backend_go/report"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getStudySessionReportHandler handles the GET /api/study_sessions/:id/report endpoint.
+// It returns the session's answer timeline, accuracy by part of speech, the words
+// learned and lapsed compared with earlier sessions and words to study next, as
+// JSON or, with format=html, as a printable page.
+func getStudySessionReportHandler(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or html"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid study session ID"})
+		return
+	}
+
+	session, err := db.GetStudySessionByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session"})
+		log.Println("Failed to fetch study session:", err)
+		return
+	}
+	if session == nil || session.UserID != currentUser(c).ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
+		return
+	}
+
+	sessionReport, err := buildSessionReport(session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build study session report"})
+		log.Println("Failed to build study session report:", err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"item": sessionReport})
+		return
+	}
+
+	var page bytes.Buffer
+	if err := report.WriteHTML(&page, sessionReport); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render study session report"})
+		log.Println("Failed to render study session report:", err)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// buildSessionReport loads what a study session's report is built from and builds it.
+func buildSessionReport(session *models.StudySession) (*models.SessionReport, error) {
+	input := report.Input{Session: *session, MaxSuggestions: report.DefaultSuggestions}
+
+	group, err := db.GetGroupByID(dbConn, session.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group != nil {
+		input.GroupName = group.Name
+	}
+
+	totals, err := db.GetStudySessionTotals(dbConn, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	input.Totals = *totals
+
+	if input.Answers, err = db.GetSessionAnswers(dbConn, session.ID); err != nil {
+		return nil, err
+	}
+	if input.PriorOutcomes, err = db.GetPriorOutcomes(dbConn, session.UserID, session.ID); err != nil {
+		return nil, err
+	}
+
+	// Suggestions are for the next session, so smart groups are evaluated as of now
+	scope := db.GroupScope{UserID: session.UserID, AsOf: time.Now().UTC()}
+	if input.GroupWords, err = db.GetGroupWords(dbConn, session.GroupID, scope, db.WordFilter{}); err != nil {
+		return nil, err
+	}
+	if input.LastReviewed, err = db.GetLastReviewTimes(dbConn, session.UserID); err != nil {
+		return nil, err
+	}
+
+	return report.Build(input), nil
+}
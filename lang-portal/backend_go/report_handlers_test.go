@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"backend_go/models"
+	"backend_go/sessiontoken"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudySessionReport(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+	tokenSigner = sessiontoken.NewSigner([]byte("test-secret"), time.Hour)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	createSession := func(reviews string) string {
+		resp := request("POST", "/api/study_sessions", `{"GroupID": 1, "StudyActivityID": 1}`)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		var session struct{ ID int }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &session))
+		id := strconv.Itoa(session.ID)
+		resp = request("POST", "/api/study_sessions/"+id+"/reviews", reviews)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		return id
+	}
+
+	require.Equal(t, http.StatusOK, request("PUT", "/api/groups/1/words", `{"word_ids": [1, 2, 3, 4]}`).Code)
+	createSession(`[
+		{"word_id": 1, "correct": true, "answered_at": "2025-03-01T10:00:00Z"},
+		{"word_id": 2, "correct": false, "answered_at": "2025-03-01T10:01:00Z"}
+	]`)
+	sessionID := createSession(`[
+		{"word_id": 1, "correct": false, "answered_at": "2025-03-02T10:00:00Z", "response_ms": 3000},
+		{"word_id": 2, "correct": true, "answered_at": "2025-03-02T10:00:30Z", "response_ms": 1000},
+		{"word_id": 3, "correct": true, "answered_at": "2025-03-02T10:01:00Z"}
+	]`)
+
+	t.Run("JSON report", func(t *testing.T) {
+		resp := request("GET", "/api/study_sessions/"+sessionID+"/report", "")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var body struct{ Item models.SessionReport }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		report := body.Item
+
+		assert.Equal(t, "Basic Vocabulary", report.GroupName)
+		assert.Equal(t, 3, report.Totals.ReviewCount)
+		require.Len(t, report.Timeline, 3)
+		assert.Equal(t, []int{1, 2, 3}, []int{report.Timeline[0].WordID, report.Timeline[1].WordID, report.Timeline[2].WordID})
+		require.NotNil(t, report.AverageResponseMS)
+		assert.Equal(t, 2000, *report.AverageResponseMS)
+
+		require.Len(t, report.Learned, 2)
+		assert.Equal(t, 2, report.Learned[0].WordID)
+		assert.Equal(t, 3, report.Learned[1].WordID)
+		require.Len(t, report.Lapsed, 1)
+		assert.Equal(t, 1, report.Lapsed[0].WordID)
+
+		require.Len(t, report.Suggested, 2)
+		assert.Equal(t, models.SessionWord{WordID: 1, SourceText: "hello", TargetText: "olá", Reason: models.SuggestionMissed}, report.Suggested[0])
+		assert.Equal(t, 4, report.Suggested[1].WordID)
+		assert.Equal(t, models.SuggestionNew, report.Suggested[1].Reason)
+	})
+
+	t.Run("HTML report", func(t *testing.T) {
+		resp := request("GET", "/api/study_sessions/"+sessionID+"/report?format=html", "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "text/html; charset=utf-8", resp.Header().Get("Content-Type"))
+		assert.Contains(t, resp.Body.String(), "Basic Vocabulary")
+		assert.Contains(t, resp.Body.String(), "2025-03-02 10:00:30")
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, request("GET", "/api/study_sessions/"+sessionID+"/report?format=pdf", "").Code)
+		assert.Equal(t, http.StatusNotFound, request("GET", "/api/study_sessions/999/report", "").Code)
+	})
+}
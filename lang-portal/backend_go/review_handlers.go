@@ -0,0 +1,170 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend_go/db"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxReviewBatchSize caps how many answers one batch submission may contain.
+const maxReviewBatchSize = 500
+
+// reviewSubmission is one answer in a batch review submission.
+type reviewSubmission struct {
+	WordID       int        `json:"word_id"`
+	Correct      *bool      `json:"correct"`
+	AnsweredAt   *time.Time `json:"answered_at"`
+	ResponseMS   *int       `json:"response_ms"`
+	GivenAnswer  string     `json:"given_answer"`
+	Direction    string     `json:"direction"`
+	ActivityType string     `json:"activity_type"`
+}
+
+// reviewResult reports the outcome of one submitted answer.
+type reviewResult struct {
+	Index  int    `json:"index"`
+	WordID int    `json:"word_id"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// createStudySessionReviewsHandler handles the POST /api/study_sessions/:id/reviews endpoint.
+// Answers are stored in one transaction only when every one of them is valid; otherwise
+// nothing is written and each invalid answer is reported, so the batch can be fixed and resent.
+func createStudySessionReviewsHandler(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid study session ID"})
+		return
+	}
+
+	var submissions []reviewSubmission
+	if err := c.BindJSON(&submissions); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(submissions) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No reviews provided"})
+		return
+	}
+	if len(submissions) > maxReviewBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many reviews in one batch", "max_batch_size": maxReviewBatchSize})
+		return
+	}
+
+	session, err := db.GetStudySessionByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session"})
+		log.Println("Failed to fetch study session:", err)
+		return
+	}
+	if session == nil || session.UserID != currentUser(c).ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
+		return
+	}
+
+	groupWordIDs, err := db.GetGroupWordIDs(dbConn, session.GroupID, db.SessionScope(session))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group words"})
+		log.Println("Failed to fetch group words:", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	results := make([]reviewResult, len(submissions))
+	items := []models.WordReviewItem{}
+	for i, submission := range submissions {
+		results[i] = reviewResult{Index: i, WordID: submission.WordID, Status: "rejected"}
+
+		switch {
+		case submission.Correct == nil:
+			results[i].Error = "correct is required"
+		case !groupWordIDs[submission.WordID]:
+			results[i].Error = "word does not belong to the study session's group"
+		case submission.ResponseMS != nil && *submission.ResponseMS < 0:
+			results[i].Error = "response_ms must not be negative"
+		case submission.AnsweredAt != nil && submission.AnsweredAt.After(now.Add(time.Minute)):
+			results[i].Error = "answered_at is in the future"
+		case !normalizeReviewDirection(&submission.Direction):
+			results[i].Error = "direction must be forward or reverse"
+		}
+		if results[i].Error != "" {
+			continue
+		}
+		results[i].Status = "valid"
+
+		item := models.WordReviewItem{
+			WordID:         submission.WordID,
+			StudySessionID: session.ID,
+			Correct:        *submission.Correct,
+			CreatedAt:      now,
+			ResponseMS:     submission.ResponseMS,
+			GivenAnswer:    submission.GivenAnswer,
+			Direction:      submission.Direction,
+			ActivityType:   submission.ActivityType,
+		}
+		if submission.AnsweredAt != nil {
+			item.CreatedAt = submission.AnsweredAt.UTC()
+		}
+		items = append(items, item)
+	}
+
+	if len(items) < len(submissions) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Some reviews are invalid; none were recorded",
+			"items":   results,
+			"created": 0,
+		})
+		return
+	}
+
+	ids, err := db.CreateWordReviewItems(dbConn, items)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record word reviews"})
+		log.Println("Failed to record word reviews:", err)
+		return
+	}
+	reviewsRecorded.Add(float64(len(ids)))
+	for i, id := range ids {
+		results[i].Status = "created"
+		results[i].ID = id
+	}
+
+	totals, err := db.GetStudySessionTotals(dbConn, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study session totals"})
+		log.Println("Failed to fetch study session totals:", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"items":   results,
+		"created": len(items),
+		"totals":  totals,
+	})
+}
+
+// normalizeReviewDirection maps a legacy review direction onto forward or reverse
+// and reports whether direction is empty or a known review direction.
+func normalizeReviewDirection(direction *string) bool {
+	switch *direction {
+	case "", models.DirectionForward, models.DirectionReverse:
+		return true
+	case models.DirectionEnglishToPortuguese:
+		*direction = models.DirectionForward
+		return true
+	case models.DirectionPortugueseToEnglish:
+		*direction = models.DirectionReverse
+		return true
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateStudySessionReviews(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	dbConn = db
+	appConfig.OpenMode = true
+
+	_, err = db.Exec(`
+		INSERT INTO groups (id, name, description) VALUES (1, 'Basics', '');
+		INSERT INTO words (id, source_text, target_text, parts) VALUES
+			(1, 'hello', 'olá', 'interjection'),
+			(2, 'goodbye', 'adeus', 'interjection'),
+			(3, 'yes', 'sim', 'adverb');
+		INSERT INTO words_groups (word_id, group_id) VALUES (1, 1), (2, 1);
+		INSERT INTO study_sessions (id, group_id, created_at, study_activity_id) VALUES (1, 1, CURRENT_TIMESTAMP, 1);`)
+	require.NoError(t, err)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	var result struct {
+		Items   []reviewResult            `json:"items"`
+		Created int                       `json:"created"`
+		Totals  models.StudySessionTotals `json:"totals"`
+	}
+
+	t.Run("Rejects the whole batch when any review is invalid", func(t *testing.T) {
+		body := `[
+			{"word_id": 1, "correct": true, "answered_at": "2025-02-01T10:00:00Z", "response_ms": 850},
+			{"word_id": 2, "correct": false, "response_ms": 4200},
+			{"word_id": 3, "correct": true},
+			{"word_id": 1}
+		]`
+		req, _ := http.NewRequest("POST", "/api/study_sessions/1/reviews", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(t, 0, result.Created)
+		require.Len(t, result.Items, 4)
+		assert.Equal(t, "valid", result.Items[0].Status)
+		assert.Equal(t, "valid", result.Items[1].Status)
+		assert.Equal(t, "rejected", result.Items[2].Status)
+		assert.Equal(t, "word does not belong to the study session's group", result.Items[2].Error)
+		assert.Equal(t, "rejected", result.Items[3].Status)
+		assert.Equal(t, "correct is required", result.Items[3].Error)
+
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM word_review_items").Scan(&count))
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("Records a valid batch", func(t *testing.T) {
+		body := `[
+			{"word_id": 1, "correct": true, "answered_at": "2025-02-01T10:00:00Z", "response_ms": 850},
+			{"word_id": 2, "correct": false, "response_ms": 4200}
+		]`
+		req, _ := http.NewRequest("POST", "/api/study_sessions/1/reviews", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(t, 2, result.Created)
+		require.Len(t, result.Items, 2)
+		assert.Equal(t, "created", result.Items[0].Status)
+		assert.Equal(t, "created", result.Items[1].Status)
+
+		assert.Equal(t, 2, result.Totals.ReviewCount)
+		assert.Equal(t, 1, result.Totals.CorrectCount)
+		assert.Equal(t, 1, result.Totals.IncorrectCount)
+		assert.Equal(t, 50.0, result.Totals.SuccessRate)
+
+		var responseMS int
+		require.NoError(t, db.QueryRow("SELECT response_ms FROM word_review_items WHERE id = ?", result.Items[0].ID).Scan(&responseMS))
+		assert.Equal(t, 850, responseMS)
+	})
+
+	t.Run("Stores language-neutral directions", func(t *testing.T) {
+		body := `[
+			{"word_id": 1, "correct": true, "direction": "reverse"},
+			{"word_id": 2, "correct": true, "direction": "en_pt"}
+		]`
+		req, _ := http.NewRequest("POST", "/api/study_sessions/1/reviews", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		for i, want := range []string{models.DirectionReverse, models.DirectionForward} {
+			var direction string
+			require.NoError(t, db.QueryRow("SELECT direction FROM word_review_items WHERE id = ?", result.Items[i].ID).Scan(&direction))
+			assert.Equal(t, want, direction)
+		}
+
+		req, _ = http.NewRequest("POST", "/api/study_sessions/1/reviews", bytes.NewBufferString(`[{"word_id": 1, "correct": true, "direction": "en_ja"}]`))
+		resp = httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend_go/cloze"
+	"backend_go/db"
+	"backend_go/llm"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sentence generation limits for POST /api/words/:id/sentences/generate.
+const (
+	defaultGeneratedSentences = 3
+	maxGeneratedSentences     = 10
+)
+
+// sentenceImport is one sentence in a POST /api/sentences/import batch.
+type sentenceImport struct {
+	WordID      int    `json:"word_id"`
+	Text        string `json:"text"`
+	Translation string `json:"translation"`
+}
+
+// importedSentence reports how one imported sentence was handled.
+type importedSentence struct {
+	Index      int    `json:"index"`
+	Status     string `json:"status"`
+	SentenceID int    `json:"sentence_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// saveSentence stores an example sentence for word. It returns why the sentence
+// was not stored, or "" when it was; sentences that do not contain the word in
+// some inflected form cannot become cloze exercises and are refused.
+func saveSentence(word *models.Word, sentence *models.Sentence) (string, error) {
+	sentence.WordID = word.ID
+	sentence.Text = strings.TrimSpace(sentence.Text)
+	sentence.Translation = strings.TrimSpace(sentence.Translation)
+	if sentence.Text == "" {
+		return "text is required", nil
+	}
+	if _, _, ok := cloze.Make(sentence.Text, word.TargetText); !ok {
+		return "sentence does not contain the word", nil
+	}
+
+	created, err := db.CreateSentence(dbConn, sentence)
+	if err != nil {
+		return "", err
+	}
+	if !created {
+		return "sentence already exists", nil
+	}
+	return "", nil
+}
+
+// getWordSentencesHandler handles the GET /api/words/:id/sentences endpoint.
+func getWordSentencesHandler(c *gin.Context) {
+	word := wordParam(c)
+	if word == nil {
+		return
+	}
+
+	sentences, err := db.GetWordSentences(dbConn, word.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sentences"})
+		log.Println("Failed to fetch sentences:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": sentences})
+}
+
+// createWordSentenceHandler handles the POST /api/words/:id/sentences endpoint.
+func createWordSentenceHandler(c *gin.Context) {
+	var sentence models.Sentence
+	if err := c.BindJSON(&sentence); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	word := wordParam(c)
+	if word == nil {
+		return
+	}
+
+	sentence.Origin = models.SentenceOriginManual
+	problem, err := saveSentence(word, &sentence)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create sentence"})
+		log.Println("Failed to create sentence:", err)
+		return
+	}
+	if problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"item": sentence})
+}
+
+// importSentencesHandler handles the POST /api/sentences/import endpoint. Each
+// sentence names its word; sentences that cannot be stored are reported per item
+// and skipped, so a batch can be re-imported safely.
+func importSentencesHandler(c *gin.Context) {
+	var imports []sentenceImport
+	if err := c.BindJSON(&imports); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(imports) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No sentences provided"})
+		return
+	}
+	if len(imports) > maxReviewBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many sentences in one batch", "max_batch_size": maxReviewBatchSize})
+		return
+	}
+
+	words := make(map[int]*models.Word)
+	results := make([]importedSentence, len(imports))
+	created, rejected := 0, 0
+	for i, item := range imports {
+		results[i] = importedSentence{Index: i, Status: "rejected"}
+
+		word, ok := words[item.WordID]
+		if !ok {
+			var err error
+			word, err = db.GetWordByID(dbConn, item.WordID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word from database"})
+				log.Println("Failed to fetch word:", err)
+				return
+			}
+			words[item.WordID] = word
+		}
+		if word == nil {
+			results[i].Error = "word not found"
+			rejected++
+			continue
+		}
+
+		sentence := models.Sentence{Text: item.Text, Translation: item.Translation, Origin: models.SentenceOriginImport}
+		problem, err := saveSentence(word, &sentence)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create sentence"})
+			log.Println("Failed to create sentence:", err)
+			return
+		}
+		if problem != "" {
+			results[i].Error = problem
+			rejected++
+			continue
+		}
+
+		results[i].Status = "created"
+		results[i].SentenceID = sentence.ID
+		created++
+	}
+
+	status := http.StatusOK
+	if created > 0 {
+		status = http.StatusCreated
+	} else if rejected == len(imports) {
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{"items": results, "created": created})
+}
+
+// generateWordSentencesHandler handles the POST /api/words/:id/sentences/generate endpoint.
+// It asks the configured LLM for example sentences and stores those that use the word.
+func generateWordSentencesHandler(c *gin.Context) {
+	if llmClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Sentence generation is not configured"})
+		return
+	}
+
+	request := struct {
+		Count int `json:"count"`
+	}{Count: defaultGeneratedSentences}
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	if request.Count < 1 || request.Count > maxGeneratedSentences {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "count must be between 1 and " + strconv.Itoa(maxGeneratedSentences)})
+		return
+	}
+
+	word := wordParam(c)
+	if word == nil {
+		return
+	}
+	source := languageParam(c, word.SourceLanguage)
+	if source == nil {
+		return
+	}
+	target := languageParam(c, word.TargetLanguage)
+	if target == nil {
+		return
+	}
+
+	proposals, err := llm.ProposeSentences(c.Request.Context(), llmClient, llm.SentenceRequest{
+		Word:           *word,
+		Count:          request.Count,
+		SourceLanguage: *source,
+		TargetLanguage: *target,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to generate sentences"})
+		log.Println("Failed to generate sentences:", err)
+		return
+	}
+
+	sentences := []models.Sentence{}
+	for i := range proposals {
+		problem, err := saveSentence(word, &proposals[i])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create sentence"})
+			log.Println("Failed to create sentence:", err)
+			return
+		}
+		if problem == "" {
+			sentences = append(sentences, proposals[i])
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"items": sentences, "discarded": len(proposals) - len(sentences)})
+}
+
+// deleteSentenceHandler handles the DELETE /api/sentences/:id endpoint.
+func deleteSentenceHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sentence ID"})
+		return
+	}
+
+	sentence, err := db.GetSentenceByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sentence"})
+		log.Println("Failed to fetch sentence:", err)
+		return
+	}
+	if sentence == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sentence not found"})
+		return
+	}
+
+	if err := db.DeleteSentence(dbConn, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete sentence"})
+		log.Println("Failed to delete sentence:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sentence deleted successfully"})
+}
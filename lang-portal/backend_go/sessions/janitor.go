@@ -0,0 +1,74 @@
+// Package sessions closes study sessions that learners left without finishing.
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"backend_go/db"
+)
+
+// defaultInterval is how often Run sweeps when Interval is not positive.
+const defaultInterval = time.Minute
+
+// Notifier is called after every sweep that closed at least one session.
+type Notifier func(closed int)
+
+// LogNotifier is the default Notifier; it only writes the count to the log.
+func LogNotifier(closed int) {
+	log.Printf("Closed %d idle study sessions", closed)
+}
+
+// Janitor periodically closes active study sessions that have received no reviews
+// for IdleTimeout, marking them abandoned and ending them at their last review.
+type Janitor struct {
+	DB          *sql.DB
+	IdleTimeout time.Duration
+	Interval    time.Duration
+	Notify      Notifier
+
+	now func() time.Time
+}
+
+// NewJanitor returns a Janitor that sweeps every interval, closes sessions idle for
+// idleTimeout and reports how many it closed to notify.
+func NewJanitor(conn *sql.DB, idleTimeout, interval time.Duration, notify Notifier) *Janitor {
+	if notify == nil {
+		notify = LogNotifier
+	}
+	return &Janitor{DB: conn, IdleTimeout: idleTimeout, Interval: interval, Notify: notify, now: time.Now}
+}
+
+// Run sweeps immediately and then on every tick until ctx is cancelled. A sweep in
+// progress is finished before Run returns.
+func (j *Janitor) Run(ctx context.Context) {
+	interval := j.Interval
+	if interval <= 0 {
+		log.Printf("Invalid study session sweep interval %s, using %s", interval, defaultInterval)
+		interval = defaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if closed, err := j.Sweep(); err != nil {
+			log.Println("Failed to close idle study sessions:", err)
+		} else if closed > 0 {
+			j.Notify(closed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep closes the sessions that have been idle for longer than IdleTimeout and
+// returns how many were closed.
+func (j *Janitor) Sweep() (int, error) {
+	return db.AbandonIdleStudySessions(j.DB, j.now().Add(-j.IdleTimeout))
+}
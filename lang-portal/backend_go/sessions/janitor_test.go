@@ -0,0 +1,88 @@
+package sessions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend_go/db"
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJanitorClosesIdleSessions(t *testing.T) {
+	conn, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Session 1 went quiet after its last review, 2 never got one, 3 is still in use
+	// and 4 was finished by the learner
+	_, err = conn.Exec(`
+		INSERT INTO study_sessions (id, group_id, created_at, study_activity_id) VALUES
+			(1, 1, '2025-02-01 10:00:00', 1),
+			(2, 1, '2025-02-01 10:30:00', 1),
+			(3, 1, '2025-02-01 10:00:00', 1);
+		INSERT INTO study_sessions (id, group_id, created_at, study_activity_id, status, ended_at) VALUES
+			(4, 1, '2025-02-01 09:00:00', 1, 'completed', '2025-02-01 09:20:00');
+		INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at) VALUES
+			(1, 1, 1, '2025-02-01 10:01:00'),
+			(2, 1, 1, '2025-02-01 10:05:00'),
+			(1, 3, 1, '2025-02-01 10:50:00')`)
+	require.NoError(t, err)
+
+	janitor := NewJanitor(conn, 30*time.Minute, time.Minute, nil)
+	janitor.now = func() time.Time { return time.Date(2025, 2, 1, 11, 10, 0, 0, time.UTC) }
+
+	closed, err := janitor.Sweep()
+	require.NoError(t, err)
+	assert.Equal(t, 2, closed)
+
+	expected := map[int]struct {
+		status  string
+		endedAt string
+	}{
+		1: {models.StudySessionAbandoned, "2025-02-01 10:05:00"},
+		2: {models.StudySessionAbandoned, "2025-02-01 10:30:00"},
+		3: {models.StudySessionActive, ""},
+		4: {models.StudySessionCompleted, "2025-02-01 09:20:00"},
+	}
+	for id, want := range expected {
+		session, err := db.GetStudySessionByID(conn, id)
+		require.NoError(t, err)
+		require.NotNil(t, session)
+		assert.Equal(t, want.status, session.Status, "session %d", id)
+		if want.endedAt == "" {
+			assert.Nil(t, session.EndedAt, "session %d", id)
+			continue
+		}
+		require.NotNil(t, session.EndedAt, "session %d", id)
+		assert.Equal(t, want.endedAt, session.EndedAt.UTC().Format("2006-01-02 15:04:05"), "session %d", id)
+	}
+
+	// Sweeping again finds nothing new; a late review brings the learner back
+	closed, err = janitor.Sweep()
+	require.NoError(t, err)
+	assert.Zero(t, closed)
+
+	_, err = db.CreateWordReviewItem(conn, &models.WordReviewItem{WordID: 1, StudySessionID: 1, Correct: true})
+	require.NoError(t, err)
+	session, err := db.GetStudySessionByID(conn, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.StudySessionActive, session.Status)
+	assert.Nil(t, session.EndedAt)
+}
+
+func TestJanitorRunWithoutInterval(t *testing.T) {
+	conn, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A zero interval falls back to the default instead of panicking
+	NewJanitor(conn, 30*time.Minute, 0, nil).Run(ctx)
+}
@@ -0,0 +1,102 @@
+// Package sessiontoken issues and verifies short-lived HMAC-signed tokens that
+// allow an external learning app to report results for a single study session.
+package sessiontoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned when a token is malformed or its signature does not match.
+	ErrInvalidToken = errors.New("invalid session token")
+	// ErrExpiredToken is returned when a token was valid but is past its expiry.
+	ErrExpiredToken = errors.New("session token expired")
+)
+
+// Claims holds the data carried inside a token.
+type Claims struct {
+	SessionID int
+	ExpiresAt time.Time
+}
+
+// Signer creates and validates tokens with a shared secret.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewSigner returns a Signer that issues tokens valid for ttl.
+func NewSigner(secret []byte, ttl time.Duration) *Signer {
+	return &Signer{secret: secret, ttl: ttl, now: time.Now}
+}
+
+// Issue returns a signed token scoped to sessionID and its expiry time.
+func (s *Signer) Issue(sessionID int) (string, time.Time, error) {
+	if len(s.secret) == 0 {
+		return "", time.Time{}, fmt.Errorf("session token secret is not configured")
+	}
+
+	expiresAt := s.now().Add(s.ttl).UTC().Truncate(time.Second)
+	payload := fmt.Sprintf("%d.%d", sessionID, expiresAt.Unix())
+
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	signature := base64.RawURLEncoding.EncodeToString(s.sign(payload))
+
+	return encoded + "." + signature, expiresAt, nil
+}
+
+// Verify checks the token signature and expiry and returns its claims.
+func (s *Signer) Verify(token string) (*Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	signatureBytes, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload := string(payloadBytes)
+	if !hmac.Equal(signatureBytes, s.sign(payload)) {
+		return nil, ErrInvalidToken
+	}
+
+	sessionPart, expiryPart, ok := strings.Cut(payload, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	sessionID, err := strconv.Atoi(sessionPart)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	expiry, err := strconv.ParseInt(expiryPart, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	claims := &Claims{SessionID: sessionID, ExpiresAt: time.Unix(expiry, 0).UTC()}
+	if !s.now().Before(claims.ExpiresAt) {
+		return nil, ErrExpiredToken
+	}
+
+	return claims, nil
+}
+
+func (s *Signer) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,45 @@
+package sessiontoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueAndVerify(t *testing.T) {
+	signer := NewSigner([]byte("secret"), time.Hour)
+
+	token, expiresAt, err := signer.Issue(42)
+	require.NoError(t, err)
+	assert.True(t, expiresAt.After(time.Now()))
+
+	claims, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, 42, claims.SessionID)
+	assert.Equal(t, expiresAt, claims.ExpiresAt)
+}
+
+func TestVerifyRejectsTamperedToken(t *testing.T) {
+	signer := NewSigner([]byte("secret"), time.Hour)
+	token, _, err := signer.Issue(1)
+	require.NoError(t, err)
+
+	other := NewSigner([]byte("other-secret"), time.Hour)
+	_, err = other.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = signer.Verify("not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerifyRejectsExpiredToken(t *testing.T) {
+	signer := NewSigner([]byte("secret"), time.Minute)
+	token, _, err := signer.Issue(1)
+	require.NoError(t, err)
+
+	signer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, err = signer.Verify(token)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
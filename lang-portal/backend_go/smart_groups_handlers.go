@@ -0,0 +1,85 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend_go/db"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRuleDays caps the day counts in a smart group rule.
+const maxRuleDays = 3650
+
+// requestScope evaluates smart groups for the request user as of now.
+func requestScope(c *gin.Context) db.GroupScope {
+	return db.GroupScope{UserID: currentUser(c).ID, AsOf: time.Now().UTC()}
+}
+
+// checkGroupRule normalizes a smart group rule's tags and returns a description
+// of what is wrong with the rule, or "" when it is valid.
+func checkGroupRule(rule *models.GroupRule) string {
+	for i, tag := range rule.Tags {
+		rule.Tags[i] = normalizeTag(tag)
+		if rule.Tags[i] == "" {
+			return "rule tags must not be empty"
+		}
+	}
+	for _, accuracy := range []*float64{rule.MinAccuracy, rule.MaxAccuracy} {
+		if accuracy != nil && (*accuracy < 0 || *accuracy > 100) {
+			return "rule accuracies must be percentages between 0 and 100"
+		}
+	}
+	for _, days := range []int{rule.WrongWithinDays, rule.ReviewedWithinDays, rule.NotReviewedForDays} {
+		if days < 0 || days > maxRuleDays {
+			return "rule day counts must be between 1 and " + strconv.Itoa(maxRuleDays)
+		}
+	}
+	return ""
+}
+
+// staticGroup writes an error response and returns false for a smart group,
+// whose words cannot be added or removed by hand.
+func staticGroup(c *gin.Context, group *models.Group) bool {
+	if group.Rule != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Words cannot be added to or removed from a smart group; its rule selects them"})
+		return false
+	}
+	return true
+}
+
+// staticGroupID is staticGroup for a group ID from a request body. Unknown groups
+// pass, so the caller reports them as before.
+func staticGroupID(c *gin.Context, groupID int) bool {
+	group, err := db.GetGroupByID(dbConn, groupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group from database"})
+		log.Println("Failed to fetch group:", err)
+		return false
+	}
+	return group == nil || staticGroup(c, group)
+}
+
+// getWordsGroupWordsHandler handles the GET /api/words_groups/:id/words endpoint.
+// It lists the words of the group, including those of its subgroups or, for a
+// smart group, those its rule selects for the request user. The word filter
+// parameters narrow the list.
+func getWordsGroupWordsHandler(c *gin.Context) {
+	group := groupParam(c)
+	if group == nil {
+		return
+	}
+
+	words, err := db.GetGroupWords(dbConn, group.ID, requestScope(c), wordFilterParam(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group words from database"})
+		log.Println("Failed to fetch group words:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": words})
+}
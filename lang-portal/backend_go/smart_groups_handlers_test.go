@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	dbpkg "backend_go/db"
+	"backend_go/models"
+	"backend_go/sessiontoken"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSmartGroups(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+	tokenSigner = sessiontoken.NewSigner([]byte("test-secret"), time.Hour)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	createGroup := func(body string) string {
+		resp := request("POST", "/api/groups", body)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		var created struct{ ID int }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+		return strconv.Itoa(created.ID)
+	}
+	groupWordIDs := func(groupID string) []int {
+		resp := request("GET", "/api/words_groups/"+groupID+"/words", "")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var body struct{ Items []models.Word }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		ids := []int{}
+		for _, word := range body.Items {
+			ids = append(ids, word.ID)
+		}
+		return ids
+	}
+
+	falar, err := dbpkg.CreateWord(db, &models.Word{SourceText: "to speak", TargetText: "falar", Parts: "verb"})
+	require.NoError(t, err)
+	comer, err := dbpkg.CreateWord(db, &models.Word{SourceText: "to eat", TargetText: "comer", Parts: "verb"})
+	require.NoError(t, err)
+	require.NoError(t, dbpkg.SetWordTags(db, comer, []string{"food"}))
+
+	now := time.Now().UTC()
+	for _, review := range []struct {
+		wordID  int
+		correct bool
+		at      time.Time
+	}{
+		{falar, true, now.AddDate(0, 0, -2)},
+		{comer, false, now.AddDate(0, 0, -40)},
+		{1, false, now.AddDate(0, 0, -1)},
+		{1, true, now.Add(-time.Hour)},
+	} {
+		_, err := db.Exec("INSERT INTO word_review_items (user_id, study_session_id, word_id, is_correct, created_at) VALUES (1, 1, ?, ?, ?)",
+			review.wordID, review.correct, review.at)
+		require.NoError(t, err)
+	}
+
+	staleVerbs := createGroup(`{"name": "Verbs to revisit", "rule": {"part_of_speech": "verb", "not_reviewed_for_days": 30}}`)
+	wrongThisWeek := createGroup(`{"name": "Wrong this week", "rule": {"wrong_within_days": 7}}`)
+
+	t.Run("Rules select words when used", func(t *testing.T) {
+		assert.Equal(t, []int{comer}, groupWordIDs(staleVerbs))
+		assert.Equal(t, []int{1}, groupWordIDs(wrongThisWeek))
+
+		dueVerbs := createGroup(`{"name": "Due verbs", "rule": {"part_of_speech": "verb", "due": true}}`)
+		assert.Equal(t, []int{comer}, groupWordIDs(dueVerbs))
+		notDue := createGroup(`{"name": "Not due", "rule": {"due": false}}`)
+		assert.Equal(t, []int{1, falar}, groupWordIDs(notDue))
+		struggling := createGroup(`{"name": "Struggling", "rule": {"max_accuracy": 50}}`)
+		assert.Equal(t, []int{1, comer}, groupWordIDs(struggling))
+		tagged := createGroup(`{"name": "Food", "rule": {"tags": ["Food"]}}`)
+		assert.Equal(t, []int{comer}, groupWordIDs(tagged))
+
+		resp := request("GET", "/api/groups/"+tagged, "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var body struct{ Item models.Group }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.NotNil(t, body.Item.Rule)
+		assert.Equal(t, []string{"food"}, body.Item.Rule.Tags)
+	})
+
+	t.Run("Invalid rules and edits are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/groups", `{"name": "Bad", "rule": {"max_accuracy": 150}}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/groups", `{"name": "Bad", "rule": {"wrong_within_days": -1}}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/groups", `{"name": "Nested", "parent_id": 1, "rule": {}}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/groups", `{"name": "Child", "parent_id": `+staleVerbs+`}`).Code)
+
+		assert.Equal(t, http.StatusBadRequest, request("PUT", "/api/groups/"+staleVerbs+"/words", `{"word_ids": [1]}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request("POST", "/api/words_groups", `{"word_id": 1, "group_id": `+staleVerbs+`}`).Code)
+	})
+
+	t.Run("Study sessions use the rule as of their start", func(t *testing.T) {
+		resp := request("POST", "/api/study_sessions", `{"GroupID": `+staleVerbs+`, "StudyActivityID": 1}`)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+		var session struct{ ID int }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &session))
+
+		reviews := `[{"word_id": ` + strconv.Itoa(comer) + `, "correct": true}, {"word_id": ` + strconv.Itoa(falar) + `, "correct": true}]`
+		resp = request("POST", "/api/study_sessions/"+strconv.Itoa(session.ID)+"/reviews", reviews)
+		require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+		var result struct{ Items []reviewResult }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		require.Len(t, result.Items, 2)
+		assert.Equal(t, "valid", result.Items[0].Status)
+		assert.Equal(t, "rejected", result.Items[1].Status)
+
+		// Answering correctly does not drop the word from the running session
+		reviews = `[{"word_id": ` + strconv.Itoa(comer) + `, "correct": true}]`
+		for i := 0; i < 2; i++ {
+			resp = request("POST", "/api/study_sessions/"+strconv.Itoa(session.ID)+"/reviews", reviews)
+			require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+			require.Len(t, result.Items, 1)
+			assert.Equal(t, "created", result.Items[0].Status)
+		}
+	})
+
+	t.Run("Mastery counts the words a rule selects", func(t *testing.T) {
+		resp := request("GET", "/api/stats/group_mastery", "")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var body struct{ Items []models.GroupMastery }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		totals := make(map[string]int)
+		for _, group := range body.Items {
+			totals[strconv.Itoa(group.GroupID)] = group.TotalWords
+		}
+		assert.Equal(t, 0, totals[staleVerbs], "comer was reviewed in the session")
+		assert.Equal(t, 1, totals[wrongThisWeek])
+	})
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend_go/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getWordLatencyStatsHandler handles GET /api/stats/words endpoint
+func getWordLatencyStatsHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if page < 1 || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters"})
+		return
+	}
+
+	userID, ok := statsUserID(c)
+	if !ok {
+		return
+	}
+
+	stats, totalItems, err := db.GetWordLatencyStats(dbConn, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word stats"})
+		log.Println("Failed to fetch word latency stats:", err)
+		return
+	}
+
+	totalPages := (totalItems + limit - 1) / limit
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": stats,
+		"pagination": gin.H{
+			"page_number": page,
+			"page_size":   limit,
+			"total_pages": totalPages,
+			"total_items": totalItems,
+		},
+	})
+}
+
+// getWordAnswerStatsHandler handles GET /api/stats/words/:id endpoint
+func getWordAnswerStatsHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	wrongAnswers, _ := strconv.Atoi(c.DefaultQuery("wrong_answers", "5"))
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
+		return
+	}
+	if wrongAnswers < 1 {
+		wrongAnswers = 5
+	}
+	userID, ok := statsUserID(c)
+	if !ok {
+		return
+	}
+
+	word, err := db.GetWordByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word"})
+		log.Println("Failed to fetch word:", err)
+		return
+	}
+	if word == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Word not found"})
+		return
+	}
+
+	stats, err := db.GetWordAnswerStats(dbConn, userID, id, wrongAnswers)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word stats"})
+		log.Println("Failed to fetch word answer stats:", err)
+		return
+	}
+	if stats == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Word has not been reviewed yet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"item": stats})
+}
+
+// parseDateRange reads the optional from/to query parameters (YYYY-MM-DD).
+// It writes a 400 response and returns false when either is malformed.
+func parseDateRange(c *gin.Context) (db.DateRange, bool) {
+	dateRange := db.DateRange{From: c.Query("from"), To: c.Query("to")}
+
+	for _, value := range []string{dateRange.From, dateRange.To} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dates must be formatted as YYYY-MM-DD"})
+			return dateRange, false
+		}
+	}
+
+	if dateRange.From != "" && dateRange.To != "" && dateRange.From > dateRange.To {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return dateRange, false
+	}
+
+	return dateRange, true
+}
+
+// getAccuracyStatsHandler handles GET /api/stats/accuracy endpoint
+func getAccuracyStatsHandler(c *gin.Context) {
+	interval := c.DefaultQuery("interval", "day")
+	if interval != "day" && interval != "week" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "interval must be day or week"})
+		return
+	}
+
+	dateRange, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	userID, ok := statsUserID(c)
+	if !ok {
+		return
+	}
+
+	points, err := db.GetAccuracyTimeSeries(dbConn, userID, interval, dateRange)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch accuracy stats"})
+		log.Println("Failed to fetch accuracy stats:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"interval": interval, "items": points})
+}
+
+// getMostMissedWordsHandler handles GET /api/stats/most_missed_words endpoint
+func getMostMissedWordsHandler(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	minReviews, _ := strconv.Atoi(c.DefaultQuery("min_reviews", "1"))
+	if limit < 1 || minReviews < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit and min_reviews must be positive"})
+		return
+	}
+
+	dateRange, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	userID, ok := statsUserID(c)
+	if !ok {
+		return
+	}
+
+	words, err := db.GetMostMissedWords(dbConn, userID, dateRange, minReviews, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch most missed words"})
+		log.Println("Failed to fetch most missed words:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": words})
+}
+
+// getGroupMasteryHandler handles GET /api/stats/group_mastery endpoint
+func getGroupMasteryHandler(c *gin.Context) {
+	streak, _ := strconv.Atoi(c.DefaultQuery("streak", "3"))
+	if streak < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "streak must be positive"})
+		return
+	}
+
+	dateRange, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	userID, ok := statsUserID(c)
+	if !ok {
+		return
+	}
+
+	groups, err := db.GetGroupMastery(dbConn, userID, dateRange, streak)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group mastery"})
+		log.Println("Failed to fetch group mastery:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"streak": streak, "items": groups})
+}
+
+// getStudyTimeHandler handles GET /api/stats/study_time endpoint
+func getStudyTimeHandler(c *gin.Context) {
+	dateRange, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	userID, ok := statsUserID(c)
+	if !ok {
+		return
+	}
+
+	days, err := db.GetStudyTimePerDay(dbConn, userID, dateRange)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch study time"})
+		log.Println("Failed to fetch study time:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": days})
+}
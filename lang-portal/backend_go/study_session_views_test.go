@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudySessionViews(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+
+	_, err = db.Exec(`
+		INSERT INTO words_groups (word_id, group_id) VALUES (1, 1), (2, 1);
+		INSERT INTO study_activities (id, study_session_id, group_id, created_at, name) VALUES (1, 1, 1, '2025-02-01 09:00:00', 'Flashcards');
+		INSERT INTO study_sessions (id, group_id, created_at, study_activity_id) VALUES (1, 1, '2025-02-01 10:00:00', 1);
+		INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at) VALUES
+			(1, 1, 1, '2025-02-01 10:00:00'),
+			(2, 1, 0, '2025-02-01 10:06:00')`)
+	require.NoError(t, err)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", url, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("Session words summary", func(t *testing.T) {
+		resp := get("/api/study_sessions/1/words?view=summary")
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var body struct{ Items []models.SessionWordStats }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Len(t, body.Items, 2)
+		assert.Equal(t, 1, body.Items[1].IncorrectCount)
+	})
+
+	t.Run("Session words detail", func(t *testing.T) {
+		resp := get("/api/study_sessions/1/words?view=detail")
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var body struct{ Items []models.WordReviewItem }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Len(t, body.Items, 2)
+	})
+
+	t.Run("Group sessions summary", func(t *testing.T) {
+		resp := get("/api/words_groups/1/study_sessions?view=summary")
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var body struct {
+			Items []models.GroupStudySessionSummary
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Len(t, body.Items, 1)
+		assert.Equal(t, 50.0, body.Items[0].SuccessRate)
+		assert.Equal(t, 6, body.Items[0].DurationMinutes)
+		assert.Equal(t, models.StudySessionActive, body.Items[0].Status)
+	})
+
+	t.Run("Group sessions detail", func(t *testing.T) {
+		resp := get("/api/words_groups/1/study_sessions?view=detail")
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var body struct {
+			Items []models.GroupStudySessionReview
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Len(t, body.Items, 2)
+		assert.Equal(t, "Flashcards", body.Items[0].ActivityName)
+	})
+
+	t.Run("Group sessions raw", func(t *testing.T) {
+		resp := get("/api/words_groups/1/study_sessions/raw")
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var body struct {
+			Items []models.StudyActivity
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Len(t, body.Items, 2)
+		assert.Equal(t, 1, body.Items[0].StudySessionID)
+		assert.Equal(t, "Flashcards", body.Items[0].ActivityType)
+		assert.Equal(t, 1, body.Items[0].GroupID)
+	})
+
+	t.Run("Default group sessions view", func(t *testing.T) {
+		resp := get("/api/words_groups/1/study_sessions")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var body struct{ Items []models.StudySession }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Len(t, body.Items, 1)
+		assert.Equal(t, 1, body.Items[0].ID)
+		assert.Equal(t, models.StudySessionActive, body.Items[0].Status)
+		assert.Nil(t, body.Items[0].EndedAt)
+	})
+
+	t.Run("Unknown view", func(t *testing.T) {
+		resp := get("/api/words_groups/1/study_sessions?view=everything")
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Complete session", func(t *testing.T) {
+		complete := func(url string) *httptest.ResponseRecorder {
+			req, _ := http.NewRequest("POST", url, nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+			return resp
+		}
+
+		resp := complete("/api/study_sessions/1/complete")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var body struct{ Item models.StudySession }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, models.StudySessionCompleted, body.Item.Status)
+		require.NotNil(t, body.Item.EndedAt)
+
+		// Completing again keeps the original end time
+		resp = complete("/api/study_sessions/1/complete")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var again struct{ Item models.StudySession }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &again))
+		assert.Equal(t, body.Item.EndedAt.Unix(), again.Item.EndedAt.Unix())
+
+		resp = get("/api/study_sessions/1")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &again))
+		assert.Equal(t, models.StudySessionCompleted, again.Item.Status)
+
+		assert.Equal(t, http.StatusNotFound, complete("/api/study_sessions/99/complete").Code)
+	})
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend_go/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Word tag limits for PUT /api/words/:id/tags.
+const (
+	maxWordTags  = 20
+	maxTagLength = 50
+)
+
+// normalizeTag lowercases a tag and collapses its whitespace, so "Food  Basics"
+// and "food basics" are the same tag.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// getTagsHandler handles the GET /api/tags endpoint.
+func getTagsHandler(c *gin.Context) {
+	tags, err := db.GetTags(dbConn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		log.Println("Failed to fetch tags:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": tags})
+}
+
+// getWordTagsHandler handles the GET /api/words/:id/tags endpoint.
+func getWordTagsHandler(c *gin.Context) {
+	word := wordParam(c)
+	if word == nil {
+		return
+	}
+
+	tags, err := db.GetWordTags(dbConn, word.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word tags"})
+		log.Println("Failed to fetch word tags:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": tags})
+}
+
+// setWordTagsHandler handles the PUT /api/words/:id/tags endpoint.
+// It replaces the word's tags with {"tags": [...]}; an empty list removes them all.
+func setWordTagsHandler(c *gin.Context) {
+	var request struct {
+		Tags []string `json:"tags"`
+	}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range request.Tags {
+		tag = normalizeTag(tag)
+		if tag == "" || len(tag) > maxTagLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tags must be between 1 and " + strconv.Itoa(maxTagLength) + " characters"})
+			return
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxWordTags {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many tags", "max_tags": maxWordTags})
+		return
+	}
+
+	word := wordParam(c)
+	if word == nil {
+		return
+	}
+
+	if err := db.SetWordTags(dbConn, word.ID, tags); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update word tags"})
+		log.Println("Failed to update word tags:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Word tags updated successfully", "items": tags})
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordTags(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	// itemIDs returns the given ID field of each item listed at url.
+	itemIDs := func(url, field string) []int {
+		resp := request("GET", url, "")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var body struct{ Items []map[string]interface{} }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		ids := []int{}
+		for _, item := range body.Items {
+			ids = append(ids, int(item[field].(float64)))
+		}
+		return ids
+	}
+
+	t.Run("Tags are normalized and replaced", func(t *testing.T) {
+		resp := request("PUT", "/api/words/1/tags", `{"tags": ["Greetings ", "greetings", "Polite  Phrases"]}`)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.Equal(t, http.StatusOK, request("PUT", "/api/words/2/tags", `{"tags": ["greetings"]}`).Code)
+
+		resp = request("GET", "/api/words/1/tags", "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var body struct{ Items []string }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, []string{"greetings", "polite phrases"}, body.Items)
+
+		resp = request("GET", "/api/tags", "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var tags struct{ Items []models.TagCount }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &tags))
+		assert.Equal(t, []models.TagCount{{Tag: "greetings", Words: 2}, {Tag: "polite phrases", Words: 1}}, tags.Items)
+	})
+
+	t.Run("Invalid tags are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, request("PUT", "/api/words/1/tags", `{"tags": ["  "]}`).Code)
+		assert.Equal(t, http.StatusBadRequest,
+			request("PUT", "/api/words/1/tags", `{"tags": ["`+strings.Repeat("a", maxTagLength+1)+`"]}`).Code)
+		assert.Equal(t, http.StatusNotFound, request("PUT", "/api/words/999/tags", `{"tags": ["greetings"]}`).Code)
+	})
+
+	t.Run("Words and quizzes filter by tag", func(t *testing.T) {
+		assert.Equal(t, []int{1, 2}, itemIDs("/api/words?tag=Greetings", "id"))
+		assert.Equal(t, []int{1}, itemIDs("/api/words?tag=polite+phrases", "id"))
+		assert.Equal(t, []int{}, itemIDs("/api/words?tag=travel", "id"))
+
+		require.Equal(t, http.StatusOK, request("PUT", "/api/groups/1/words", `{"word_ids": [1, 2]}`).Code)
+		resp := request("POST", "/api/sentences/import", `[
+			{"word_id": 1, "text": "Olá, tudo bem?"},
+			{"word_id": 2, "text": "Ela disse adeus."}
+		]`)
+		require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+		assert.ElementsMatch(t, []int{1, 2}, itemIDs("/api/groups/1/cloze", "word_id"))
+		assert.Equal(t, []int{1}, itemIDs("/api/groups/1/cloze?tag=polite+phrases", "word_id"))
+	})
+
+	t.Run("Deleting a word removes its tags", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, request("DELETE", "/api/words/1", "").Code)
+
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM word_tags WHERE word_id = 1").Scan(&count))
+		assert.Zero(t, count)
+	})
+}
@@ -2,9 +2,15 @@ package testutils
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // Import SQLite driver
 )
 
 // SetupTestDB creates a new in-memory SQLite database for testing
@@ -15,14 +21,67 @@ func SetupTestDB() (*sql.DB, error) {
 	}
 
 	// Run migrations
-	migrationsPath := filepath.Join("..", "db", "migrations")
-	if err := runMigrations(db, migrationsPath); err != nil {
+	if err := runMigrations(db, migrationsDir()); err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
 	return db, nil
 }
 
+// SeedTestDB loads the JSON seed files from db/seeds into the database, the same
+// way the db:seed mage task does.
+func SeedTestDB(db *sql.DB) error {
+	seedsPath := filepath.Join(filepath.Dir(migrationsDir()), "seeds")
+	files, err := os.ReadDir(seedsPath)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(seedsPath, file.Name()))
+		if err != nil {
+			return err
+		}
+
+		var rows []map[string]interface{}
+		if err := json.Unmarshal(content, &rows); err != nil {
+			return fmt.Errorf("seed %s is not valid JSON: %w", file.Name(), err)
+		}
+
+		table := strings.TrimSuffix(file.Name(), ".json")
+		for _, row := range rows {
+			columns := make([]string, 0, len(row))
+			for column := range row {
+				columns = append(columns, column)
+			}
+			sort.Strings(columns)
+
+			values := make([]interface{}, len(columns))
+			for i, column := range columns {
+				values[i] = row[column]
+			}
+
+			query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table,
+				strings.Join(columns, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+			if _, err := db.Exec(query, values...); err != nil {
+				return fmt.Errorf("seed %s failed: %w", file.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// migrationsDir resolves db/migrations relative to this file so tests in any
+// package can find it regardless of their working directory.
+func migrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "db", "migrations")
+}
+
 func runMigrations(db *sql.DB, path string) error {
 	files, err := os.ReadDir(path)
 	if err != nil {
@@ -0,0 +1,64 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"backend_go/db"
+	"backend_go/media"
+	"backend_go/models"
+)
+
+// Generator synthesizes a word's pronunciation and attaches it to the word.
+type Generator struct {
+	DB       *sql.DB
+	Store    *media.Store
+	Provider Provider
+	MaxBytes int64
+}
+
+// Generate synthesizes the target text of the word in its target language, stores
+// the audio and records it as the word's media. Errors that a retry cannot fix,
+// such as a missing word or audio the store rejects, are marked permanent.
+func (g *Generator) Generate(ctx context.Context, wordID int) (*models.WordMedia, error) {
+	word, err := db.GetWordByID(g.DB, wordID)
+	if err != nil {
+		return nil, err
+	}
+	if word == nil {
+		return nil, Permanent(fmt.Errorf("word %d not found", wordID))
+	}
+
+	audio, err := g.Provider.Synthesize(ctx, word.TargetText, word.TargetLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := g.Store.Save(bytes.NewReader(audio), g.MaxBytes)
+	if errors.Is(err, media.ErrTooLarge) || errors.Is(err, media.ErrUnsupportedType) {
+		return nil, Permanent(fmt.Errorf("text-to-speech audio rejected: %w", err))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if blob.Kind != media.KindAudio {
+		return nil, Permanent(fmt.Errorf("text-to-speech service returned %s, not audio", blob.MIMEType))
+	}
+
+	m := models.WordMedia{
+		WordID:       word.ID,
+		Kind:         blob.Kind,
+		MIMEType:     blob.MIMEType,
+		SizeBytes:    blob.Size,
+		ContentHash:  blob.Hash,
+		OriginalName: "tts-" + strconv.Itoa(word.ID),
+	}
+	if _, err := db.CreateWordMedia(g.DB, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,167 @@
+// Package tts generates pronunciation audio for words with a pluggable
+// text-to-speech provider and stores it as word media.
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"time"
+)
+
+// maxResponseBytes caps how much audio is read from a provider response.
+const maxResponseBytes = 32 << 20
+
+// StubEndpoint selects the offline Stub provider in place of an HTTP endpoint.
+const StubEndpoint = "stub"
+
+// Provider turns text in a language into audio.
+type Provider interface {
+	Synthesize(ctx context.Context, text, language string) ([]byte, error)
+}
+
+// New returns the provider for endpoint: nil when it is empty (text-to-speech is
+// disabled), the Stub for StubEndpoint, and an HTTPProvider otherwise.
+func New(endpoint, voice string, timeout time.Duration) Provider {
+	switch endpoint {
+	case "":
+		return nil
+	case StubEndpoint:
+		return Stub{}
+	default:
+		return NewHTTPProvider(endpoint, voice, timeout)
+	}
+}
+
+// HTTPProvider calls a text-to-speech service such as the OPEA TTS microservice.
+// It POSTs an OpenAI-style speech request and expects the audio as the response body.
+type HTTPProvider struct {
+	Endpoint string
+	Voice    string
+	Client   *http.Client
+}
+
+// NewHTTPProvider returns a provider that posts to endpoint, giving up on a request after timeout.
+func NewHTTPProvider(endpoint, voice string, timeout time.Duration) *HTTPProvider {
+	return &HTTPProvider{Endpoint: endpoint, Voice: voice, Client: &http.Client{Timeout: timeout}}
+}
+
+// speechRequest is the body posted to the service.
+type speechRequest struct {
+	Input          string `json:"input"`
+	Voice          string `json:"voice,omitempty"`
+	Language       string `json:"language"`
+	ResponseFormat string `json:"response_format"`
+}
+
+// Synthesize implements Provider. Client errors (4xx) are permanent; network
+// errors and server errors may be retried.
+func (p *HTTPProvider) Synthesize(ctx context.Context, text, language string) ([]byte, error) {
+	body, err := json.Marshal(speechRequest{Input: text, Voice: p.Voice, Language: language, ResponseFormat: "wav"})
+	if err != nil {
+		return nil, Permanent(fmt.Errorf("failed to encode speech request: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, Permanent(fmt.Errorf("failed to create speech request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call text-to-speech service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("text-to-speech service returned %s", resp.Status)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return nil, Permanent(err)
+		}
+		return nil, err
+	}
+
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read text-to-speech response: %w", err)
+	}
+	return audio, nil
+}
+
+// Stub is an offline provider for tests and development. It returns a short WAV
+// tone whose pitch and length are derived from the text, so the same input
+// always produces the same audio.
+type Stub struct{}
+
+// Stub audio format: 8 kHz, mono, 8-bit PCM.
+const stubSampleRate = 8000
+
+// wavHeader is the RIFF header of a PCM WAV file.
+type wavHeader struct {
+	ChunkID       [4]byte
+	ChunkSize     uint32
+	Format        [4]byte
+	FmtID         [4]byte
+	FmtSize       uint32
+	AudioFormat   uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+	DataID        [4]byte
+	DataSize      uint32
+}
+
+// Synthesize implements Provider.
+func (Stub) Synthesize(ctx context.Context, text, language string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(language + "\x00" + text))
+	frequency := 220 + float64(h.Sum32()%660)
+	samples := stubSampleRate/10 + len(text)*stubSampleRate/50
+
+	header := wavHeader{
+		ChunkID: [4]byte{'R', 'I', 'F', 'F'}, ChunkSize: uint32(36 + samples), Format: [4]byte{'W', 'A', 'V', 'E'},
+		FmtID: [4]byte{'f', 'm', 't', ' '}, FmtSize: 16, AudioFormat: 1, Channels: 1,
+		SampleRate: stubSampleRate, ByteRate: stubSampleRate, BlockAlign: 1, BitsPerSample: 8,
+		DataID: [4]byte{'d', 'a', 't', 'a'}, DataSize: uint32(samples),
+	}
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+		return nil, err
+	}
+	for i := 0; i < samples; i++ {
+		buf.WriteByte(byte(128 + 64*math.Sin(2*math.Pi*frequency*float64(i)/stubSampleRate)))
+	}
+	return buf.Bytes(), nil
+}
+
+// permanentError marks an error that retrying will not fix.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so IsPermanent reports true for it.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err, or an error it wraps, was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
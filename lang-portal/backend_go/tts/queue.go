@@ -0,0 +1,190 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"backend_go/models"
+)
+
+// Queue defaults, used when the corresponding field is not positive.
+const (
+	DefaultWorkers     = 2
+	DefaultMaxAttempts = 3
+	DefaultBackoff     = 2 * time.Second
+)
+
+// Job statuses.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// ErrQueueFull is returned by Enqueue when no more jobs can be accepted.
+var ErrQueueFull = errors.New("text-to-speech queue is full")
+
+// GenerateFunc produces the audio for one word; Generator.Generate is the usual implementation.
+type GenerateFunc func(ctx context.Context, wordID int) (*models.WordMedia, error)
+
+// Job reports the state of audio generation for one word.
+type Job struct {
+	WordID    int       `json:"word_id"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+	MediaID   int       `json:"media_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Queue generates audio in the background with a pool of workers, retrying
+// failures that are not permanent with exponential backoff. It remembers the
+// latest job of every word so callers can poll its status.
+type Queue struct {
+	Generate    GenerateFunc
+	Workers     int
+	MaxAttempts int
+	Backoff     time.Duration
+
+	mu      sync.Mutex
+	jobs    map[int]*Job
+	pending chan int
+	active  sync.WaitGroup
+}
+
+// NewQueue returns a queue that holds up to size waiting jobs.
+func NewQueue(generate GenerateFunc, size int) *Queue {
+	return &Queue{
+		Generate:    generate,
+		Workers:     DefaultWorkers,
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+		jobs:        make(map[int]*Job),
+		pending:     make(chan int, size),
+	}
+}
+
+// Enqueue schedules audio generation for a word. A word whose job is still queued
+// or running is not scheduled twice; its current job is returned instead.
+func (q *Queue) Enqueue(wordID int) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if job, ok := q.jobs[wordID]; ok && (job.Status == JobQueued || job.Status == JobRunning) {
+		return *job, nil
+	}
+
+	select {
+	case q.pending <- wordID:
+	default:
+		return Job{}, ErrQueueFull
+	}
+
+	job := &Job{WordID: wordID, Status: JobQueued, UpdatedAt: time.Now().UTC()}
+	q.jobs[wordID] = job
+	q.active.Add(1)
+	return *job, nil
+}
+
+// Job returns the latest job for a word.
+func (q *Queue) Job(wordID int) (Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[wordID]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// Run processes jobs until ctx is cancelled.
+func (q *Queue) Run(ctx context.Context) {
+	workers := q.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case wordID := <-q.pending:
+					q.process(ctx, wordID)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Wait blocks until every enqueued job has finished. Run must be running.
+func (q *Queue) Wait() {
+	q.active.Wait()
+}
+
+// process runs one job, retrying it until it succeeds, fails permanently or
+// runs out of attempts.
+func (q *Queue) process(ctx context.Context, wordID int) {
+	defer q.active.Done()
+
+	maxAttempts := q.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	backoff := q.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		q.update(wordID, func(job *Job) {
+			job.Status = JobRunning
+			job.Attempts = attempt
+		})
+
+		m, err := q.Generate(ctx, wordID)
+		if err == nil {
+			q.update(wordID, func(job *Job) {
+				job.Status = JobSucceeded
+				job.Error = ""
+				job.MediaID = m.ID
+			})
+			return
+		}
+
+		if IsPermanent(err) || attempt >= maxAttempts || ctx.Err() != nil {
+			log.Printf("Failed to generate audio for word %d after %d attempts: %v", wordID, attempt, err)
+			q.update(wordID, func(job *Job) {
+				job.Status = JobFailed
+				job.Error = err.Error()
+			})
+			return
+		}
+
+		q.update(wordID, func(job *Job) { job.Error = err.Error() })
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff << (attempt - 1)):
+		}
+	}
+}
+
+// update applies change to a word's job under the lock.
+func (q *Queue) update(wordID int, change func(job *Job)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job := q.jobs[wordID]
+	change(job)
+	job.UpdatedAt = time.Now().UTC()
+}
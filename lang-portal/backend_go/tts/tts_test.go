@@ -0,0 +1,148 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"backend_go/db"
+	"backend_go/media"
+	"backend_go/models"
+	"backend_go/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStubIsDeterministicWAV(t *testing.T) {
+	a, err := Stub{}.Synthesize(context.Background(), "olá", "pt")
+	require.NoError(t, err)
+	b, err := Stub{}.Synthesize(context.Background(), "olá", "pt")
+	require.NoError(t, err)
+	c, err := Stub{}.Synthesize(context.Background(), "olá", "es")
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+	assert.Equal(t, "audio/wave", http.DetectContentType(a))
+}
+
+func TestHTTPProvider(t *testing.T) {
+	var status int32 = http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req speechRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, speechRequest{Input: "obrigado", Voice: "default", Language: "pt", ResponseFormat: "wav"}, req)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		w.Write([]byte("RIFF audio"))
+	}))
+	defer server.Close()
+
+	provider := New(server.URL, "default", time.Second)
+	audio, err := provider.Synthesize(context.Background(), "obrigado", "pt")
+	require.NoError(t, err)
+	assert.Equal(t, "RIFF audio", string(audio))
+
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+	_, err = provider.Synthesize(context.Background(), "obrigado", "pt")
+	require.Error(t, err)
+	assert.False(t, IsPermanent(err))
+
+	atomic.StoreInt32(&status, http.StatusBadRequest)
+	_, err = provider.Synthesize(context.Background(), "obrigado", "pt")
+	assert.True(t, IsPermanent(err))
+
+	assert.Nil(t, New("", "", time.Second))
+	assert.Equal(t, Stub{}, New(StubEndpoint, "", time.Second))
+}
+
+func TestGenerator(t *testing.T) {
+	conn, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, testutils.SeedTestDB(conn))
+
+	generator := &Generator{DB: conn, Store: media.NewStore(t.TempDir()), Provider: Stub{}, MaxBytes: 1 << 20}
+	m, err := generator.Generate(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, media.KindAudio, m.Kind)
+	assert.Equal(t, "audio/wav", m.MIMEType)
+
+	ids, err := db.GetWordIDsWithoutMedia(conn, media.KindAudio)
+	require.NoError(t, err)
+	assert.NotContains(t, ids, 1)
+	assert.Contains(t, ids, 2)
+
+	_, err = generator.Generate(context.Background(), 999)
+	assert.True(t, IsPermanent(err))
+
+	generator.MaxBytes = 10
+	_, err = generator.Generate(context.Background(), 2)
+	assert.True(t, IsPermanent(err))
+}
+
+func TestQueueRetries(t *testing.T) {
+	var calls int32
+	queue := NewQueue(func(ctx context.Context, wordID int) (*models.WordMedia, error) {
+		n := atomic.AddInt32(&calls, 1)
+		switch {
+		case wordID == 2:
+			return nil, Permanent(errors.New("word not found"))
+		case wordID == 3:
+			return nil, errors.New("service unavailable")
+		case n == 1:
+			return nil, errors.New("timeout")
+		}
+		return &models.WordMedia{ID: 10 + wordID}, nil
+	}, 10)
+	queue.Workers = 1
+	queue.Backoff = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	for _, id := range []int{1, 2, 3} {
+		job, err := queue.Enqueue(id)
+		require.NoError(t, err)
+		assert.Equal(t, id, job.WordID)
+	}
+	queue.Wait()
+
+	job, ok := queue.Job(1)
+	require.True(t, ok)
+	assert.Equal(t, JobSucceeded, job.Status)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Equal(t, 11, job.MediaID)
+
+	job, _ = queue.Job(2)
+	assert.Equal(t, JobFailed, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+
+	job, _ = queue.Job(3)
+	assert.Equal(t, JobFailed, job.Status)
+	assert.Equal(t, DefaultMaxAttempts, job.Attempts)
+	assert.Equal(t, "service unavailable", job.Error)
+
+	_, ok = queue.Job(4)
+	assert.False(t, ok)
+}
+
+func TestQueueFull(t *testing.T) {
+	queue := NewQueue(func(ctx context.Context, wordID int) (*models.WordMedia, error) {
+		return &models.WordMedia{}, nil
+	}, 1)
+
+	_, err := queue.Enqueue(1)
+	require.NoError(t, err)
+	job, err := queue.Enqueue(1)
+	require.NoError(t, err, "a queued word is not scheduled twice")
+	assert.Equal(t, JobQueued, job.Status)
+	_, err = queue.Enqueue(2)
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"backend_go/tts"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ttsQueueSize is how many audio generation jobs may wait at once.
+const ttsQueueSize = 1000
+
+// generateWordAudioHandler handles the POST /api/words/:id/audio/generate endpoint.
+// It schedules pronunciation audio generation for the word and returns the job,
+// which can be polled with GET on the same path.
+func generateWordAudioHandler(c *gin.Context) {
+	if ttsQueue == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Text-to-speech is not configured"})
+		return
+	}
+
+	word := wordParam(c)
+	if word == nil {
+		return
+	}
+
+	job, err := ttsQueue.Enqueue(word.ID)
+	if errors.Is(err, tts.ErrQueueFull) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Too many audio generation jobs, try again later"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule audio generation"})
+		log.Println("Failed to schedule audio generation:", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"item": job})
+}
+
+// getWordAudioJobHandler handles the GET /api/words/:id/audio/generate endpoint.
+// It returns the word's latest audio generation job.
+func getWordAudioJobHandler(c *gin.Context) {
+	if ttsQueue == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Text-to-speech is not configured"})
+		return
+	}
+
+	word := wordParam(c)
+	if word == nil {
+		return
+	}
+
+	job, ok := ttsQueue.Job(word.ID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No audio generation job for this word"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"item": job})
+}
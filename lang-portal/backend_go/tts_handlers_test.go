@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbpkg "backend_go/db"
+	"backend_go/media"
+	"backend_go/testutils"
+	"backend_go/tts"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateWordAudio(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = true
+	mediaStore = media.NewStore(t.TempDir())
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	request := func(method, url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	ttsQueue = nil
+	assert.Equal(t, http.StatusServiceUnavailable, request("POST", "/api/words/1/audio/generate").Code)
+
+	generator := &tts.Generator{DB: db, Store: mediaStore, Provider: tts.Stub{}, MaxBytes: 1 << 20}
+	ttsQueue = tts.NewQueue(generator.Generate, 10)
+	defer func() { ttsQueue = nil }()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ttsQueue.Run(ctx)
+
+	assert.Equal(t, http.StatusNotFound, request("GET", "/api/words/1/audio/generate").Code)
+	assert.Equal(t, http.StatusNotFound, request("POST", "/api/words/999/audio/generate").Code)
+
+	resp := request("POST", "/api/words/1/audio/generate")
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+	ttsQueue.Wait()
+
+	resp = request("GET", "/api/words/1/audio/generate")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var body struct{ Item tts.Job }
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, tts.JobSucceeded, body.Item.Status)
+	assert.NotZero(t, body.Item.MediaID)
+	assert.WithinDuration(t, time.Now(), body.Item.UpdatedAt, time.Minute)
+
+	attachments, err := dbpkg.GetWordMedia(db, 1)
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	assert.Equal(t, body.Item.MediaID, attachments[0].ID)
+	assert.Equal(t, media.KindAudio, attachments[0].Kind)
+}
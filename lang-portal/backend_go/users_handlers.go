@@ -0,0 +1,203 @@
+package main
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend_go/auth"
+	"backend_go/db"
+	"backend_go/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUser returns the user the request acts as.
+func currentUser(c *gin.Context) *models.User {
+	return c.MustGet(currentUserKey).(*models.User)
+}
+
+// canViewUser reports whether the request's user may read userID's data.
+// Learners only see their own data; teachers and admins see everyone's.
+func canViewUser(c *gin.Context, userID int) bool {
+	user := currentUser(c)
+	return user.ID == userID || user.Role == models.RoleTeacher || user.Role == models.RoleAdmin
+}
+
+// statsUserID returns the user whose stats are requested: the optional user_id query
+// parameter, or the request's own user. It writes an error response and returns
+// false when the parameter is malformed or names another user's data without permission.
+func statsUserID(c *gin.Context) (int, bool) {
+	idStr := c.Query("user_id")
+	if idStr == "" {
+		return currentUser(c).ID, true
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, false
+	}
+	if !canViewUser(c, id) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return 0, false
+	}
+
+	return id, true
+}
+
+// validUserRole reports whether role is a known user role.
+func validUserRole(role string) bool {
+	return role == models.RoleLearner || role == models.RoleTeacher || role == models.RoleAdmin
+}
+
+// getUsersHandler handles the GET /api/users endpoint.
+func getUsersHandler(c *gin.Context) {
+	users, err := db.GetAllUsers(dbConn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users from database"})
+		log.Println("Failed to fetch users:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": users})
+}
+
+// getCurrentUserHandler handles the GET /api/users/me endpoint.
+func getCurrentUserHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"item": currentUser(c)})
+}
+
+// getUserByIDHandler handles the GET /api/users/:id endpoint.
+func getUserByIDHandler(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if !canViewUser(c, id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	user, err := db.GetUserByID(dbConn, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user from database"})
+		log.Println("Failed to fetch user:", err)
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"item": user})
+}
+
+// createUserRequest is the payload for creating a user; the password is optional.
+type createUserRequest struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Role        string `json:"role"`
+	Password    string `json:"password"`
+}
+
+// createUserHandler handles the POST /api/users endpoint.
+func createUserHandler(c *gin.Context) {
+	var request createUserRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user := models.User{Username: strings.TrimSpace(request.Username), DisplayName: request.DisplayName, Role: request.Role}
+	if user.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return
+	}
+	if user.DisplayName == "" {
+		user.DisplayName = user.Username
+	}
+	if user.Role == "" {
+		user.Role = models.RoleLearner
+	}
+	if !validUserRole(user.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be learner, teacher or admin"})
+		return
+	}
+	if request.Password != "" {
+		hash, err := auth.HashPassword(request.Password)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": passwordErrorMessage})
+			return
+		}
+		user.PasswordHash = hash
+	}
+
+	exists, err := db.UsernameExists(dbConn, user.Username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check username"})
+		log.Println("Failed to check username:", err)
+		return
+	}
+	if exists {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username already taken"})
+		return
+	}
+
+	id, err := db.CreateUser(dbConn, &user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user in database"})
+		log.Println("Failed to create user:", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// getClassProgressHandler handles the GET /api/teacher/progress endpoint.
+// It lists every learner's progress in the optional date range with class-wide totals.
+func getClassProgressHandler(c *gin.Context) {
+	dateRange, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	learners, err := db.GetLearnerProgress(dbConn, dateRange)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch class progress"})
+		log.Println("Failed to fetch learner progress:", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":  learners,
+		"totals": classProgressTotals(learners),
+	})
+}
+
+// classProgressTotals sums learner progress; accuracy is weighted by review count.
+func classProgressTotals(learners []models.LearnerProgress) models.ClassProgress {
+	totals := models.ClassProgress{Learners: len(learners)}
+
+	correct := 0
+	for _, learner := range learners {
+		if learner.ReviewCount > 0 {
+			totals.ActiveLearners++
+		}
+		totals.StudySessions += learner.StudySessions
+		totals.ReviewCount += learner.ReviewCount
+		correct += learner.CorrectCount
+	}
+
+	if totals.ReviewCount > 0 {
+		totals.Accuracy = math.Round(float64(correct)*1000/float64(totals.ReviewCount)) / 10
+	}
+
+	return totals
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"backend_go/auth"
+	dbpkg "backend_go/db"
+	"backend_go/models"
+	"backend_go/sessiontoken"
+	"backend_go/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerUserScoping(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, testutils.SeedTestDB(db))
+	dbConn = db
+	appConfig.OpenMode = false
+	tokenSigner = sessiontoken.NewSigner([]byte("test-secret"), time.Hour)
+
+	_, err = db.Exec(`INSERT INTO words_groups (word_id, group_id) VALUES (1, 1), (2, 1)`)
+	require.NoError(t, err)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	// Every user authenticates with an API token; user 0 sends no credentials
+	tokens := map[int]string{models.DefaultUserID: issueTestToken(t, models.DefaultUserID)}
+	admin := models.User{Username: "admin", DisplayName: "Admin", Role: models.RoleAdmin}
+	_, err = dbpkg.CreateUser(db, &admin)
+	require.NoError(t, err)
+	tokens[admin.ID] = issueTestToken(t, admin.ID)
+
+	request := func(method, url string, userID int, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		if token, ok := tokens[userID]; ok {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	createUser := func(body string) int {
+		resp := request("POST", "/api/users", admin.ID, body)
+		require.Equal(t, http.StatusCreated, resp.Code)
+		var created struct{ ID int }
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+		tokens[created.ID] = issueTestToken(t, created.ID)
+		return created.ID
+	}
+
+	ana := createUser(`{"username": "ana", "display_name": "Ana"}`)
+	teacher := createUser(`{"username": "prof", "role": "teacher"}`)
+
+	resp := request("POST", "/api/users", admin.ID, `{"username": "ana"}`)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	resp = request("POST", "/api/users", ana, `{"username": "bruno"}`)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	// Ana studies group 1; the default learner has no sessions
+	resp = request("POST", "/api/study_sessions", ana, `{"GroupID": 1, "StudyActivityID": 1}`)
+	require.Equal(t, http.StatusCreated, resp.Code)
+	var session struct{ ID int }
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &session))
+	sessionURL := "/api/study_sessions/" + strconv.Itoa(session.ID)
+
+	resp = request("POST", sessionURL+"/reviews", ana, `[{"word_id": 1, "correct": true}, {"word_id": 2, "correct": false}]`)
+	require.Equal(t, http.StatusCreated, resp.Code)
+
+	t.Run("Sessions are scoped to their owner", func(t *testing.T) {
+		var list struct{ Items []models.StudySession }
+		resp := request("GET", "/api/study_sessions", models.DefaultUserID, "")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		assert.Empty(t, list.Items)
+
+		resp = request("GET", "/api/study_sessions", ana, "")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, ana, list.Items[0].UserID)
+
+		assert.Equal(t, http.StatusNotFound, request("GET", sessionURL, models.DefaultUserID, "").Code)
+		assert.Equal(t, http.StatusOK, request("GET", sessionURL, teacher, "").Code)
+		assert.Equal(t, http.StatusNotFound, request("POST", sessionURL+"/reviews", teacher, `[{"word_id": 1, "correct": true}]`).Code)
+	})
+
+	t.Run("Reviews inherit the session's user", func(t *testing.T) {
+		var list struct{ Items []models.WordReviewItem }
+		resp := request("GET", "/api/word_review_items", ana, "")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		require.Len(t, list.Items, 2)
+		assert.Equal(t, ana, list.Items[0].UserID)
+
+		resp = request("GET", "/api/word_review_items", models.DefaultUserID, "")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		assert.Empty(t, list.Items)
+	})
+
+	t.Run("Stats are scoped per user", func(t *testing.T) {
+		var stats struct{ Data models.QuickStats }
+		resp := request("GET", "/api/dashboard/quick_stats", ana, "")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+		assert.Equal(t, 2, stats.Data.TotalWordsStudied)
+		assert.Equal(t, 50.0, stats.Data.SuccessRate)
+
+		resp = request("GET", "/api/dashboard/quick_stats", models.DefaultUserID, "")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+		assert.Zero(t, stats.Data.TotalWordsStudied)
+
+		anaStats := "/api/dashboard/quick_stats?user_id=" + strconv.Itoa(ana)
+		assert.Equal(t, http.StatusForbidden, request("GET", anaStats, models.DefaultUserID, "").Code)
+		resp = request("GET", anaStats, teacher, "")
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+		assert.Equal(t, 2, stats.Data.TotalWordsStudied)
+	})
+
+	t.Run("Teachers see class progress", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, request("GET", "/api/teacher/progress", ana, "").Code)
+
+		resp := request("GET", "/api/teacher/progress", teacher, "")
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var body struct {
+			Items  []models.LearnerProgress
+			Totals models.ClassProgress
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		require.Len(t, body.Items, 2, "teachers are not listed as learners")
+		assert.Equal(t, ana, body.Items[1].UserID)
+		assert.Equal(t, 1, body.Items[1].StudySessions)
+		assert.Equal(t, 2, body.Items[1].ReviewCount)
+		assert.Equal(t, 2, body.Totals.Learners)
+		assert.Equal(t, 1, body.Totals.ActiveLearners)
+		assert.Equal(t, 50.0, body.Totals.Accuracy)
+	})
+}
+
+// issueTestToken stores a personal API token for userID and returns its value.
+func issueTestToken(t *testing.T, userID int) string {
+	token, hash, err := auth.NewToken()
+	require.NoError(t, err)
+	_, err = dbpkg.CreateAuthToken(dbConn, &models.AuthToken{UserID: userID, Kind: models.TokenKindAPI}, hash)
+	require.NoError(t, err)
+	return token
+}
@@ -0,0 +1,38 @@
+package xapi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var durationPattern = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// ParseDuration parses the ISO 8601 durations used in statement results, e.g. "PT1.25S".
+func ParseDuration(value string) (time.Duration, error) {
+	matches := durationPattern.FindStringSubmatch(value)
+	if matches == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", value)
+	}
+
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if matches[i+1] == "" {
+			continue
+		}
+		amount, err := strconv.ParseFloat(matches[i+1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q: %w", value, err)
+		}
+		total += time.Duration(amount * float64(unit))
+	}
+
+	return total, nil
+}
+
+// FormatDuration renders d as an ISO 8601 duration in seconds, e.g. "PT1.25S".
+func FormatDuration(d time.Duration) string {
+	return "PT" + strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+}
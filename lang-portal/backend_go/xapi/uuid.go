@@ -0,0 +1,26 @@
+package xapi
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// NewStatementID returns a random (version 4) UUID.
+func NewStatementID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate statement id: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// ValidStatementID reports whether id is a UUID.
+func ValidStatementID(id string) bool {
+	return uuidPattern.MatchString(id)
+}
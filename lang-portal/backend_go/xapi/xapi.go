@@ -0,0 +1,144 @@
+// Package xapi contains the subset of the Experience API (Tin Can) statement
+// format the learning record store understands, and the mapping between
+// "answered" statements about vocabulary and word review items.
+package xapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the xAPI version reported in responses.
+const Version = "1.0.3"
+
+// VerbAnswered is the only verb mapped onto word review items.
+const VerbAnswered = "http://adlnet.gov/expapi/verbs/answered"
+
+// ActivityTypeInteraction is the activity type used for vocabulary objects.
+const ActivityTypeInteraction = "http://adlnet.gov/expapi/activities/cmi.interaction"
+
+// Statement is an xAPI statement.
+type Statement struct {
+	ID        string          `json:"id,omitempty"`
+	Actor     json.RawMessage `json:"actor"`
+	Verb      Verb            `json:"verb"`
+	Object    Activity        `json:"object"`
+	Result    *Result         `json:"result,omitempty"`
+	Context   *Context        `json:"context,omitempty"`
+	Timestamp *time.Time      `json:"timestamp,omitempty"`
+	Stored    *time.Time      `json:"stored,omitempty"`
+	Version   string          `json:"version,omitempty"`
+}
+
+// Verb identifies the action of a statement.
+type Verb struct {
+	ID      string            `json:"id"`
+	Display map[string]string `json:"display,omitempty"`
+}
+
+// Activity is the object of a statement.
+type Activity struct {
+	ObjectType string              `json:"objectType,omitempty"`
+	ID         string              `json:"id"`
+	Definition *ActivityDefinition `json:"definition,omitempty"`
+}
+
+// ActivityDefinition describes an activity.
+type ActivityDefinition struct {
+	Name map[string]string `json:"name,omitempty"`
+	Type string            `json:"type,omitempty"`
+}
+
+// Result holds the outcome of a statement.
+type Result struct {
+	Success  *bool  `json:"success,omitempty"`
+	Response string `json:"response,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// Context carries the activities a statement took place in.
+type Context struct {
+	Registration      string             `json:"registration,omitempty"`
+	ContextActivities *ContextActivities `json:"contextActivities,omitempty"`
+}
+
+// ContextActivities groups related activities.
+type ContextActivities struct {
+	Parent   []Activity `json:"parent,omitempty"`
+	Grouping []Activity `json:"grouping,omitempty"`
+}
+
+// WordActivityID returns the activity IRI for a word.
+func WordActivityID(baseURL string, wordID int) string {
+	return fmt.Sprintf("%s/api/words/%d", strings.TrimRight(baseURL, "/"), wordID)
+}
+
+// SessionActivityID returns the activity IRI for a study session.
+func SessionActivityID(baseURL string, sessionID int) string {
+	return fmt.Sprintf("%s/api/study_sessions/%d", strings.TrimRight(baseURL, "/"), sessionID)
+}
+
+// ParseWordID extracts the word ID from an activity IRI ending in /words/{id}.
+func ParseWordID(activityID string) (int, bool) {
+	return trailingID(activityID, "/words/")
+}
+
+// SessionID finds the study session a statement belongs to in its context activities.
+func (s *Statement) SessionID() (int, bool) {
+	if s.Context == nil || s.Context.ContextActivities == nil {
+		return 0, false
+	}
+
+	for _, activities := range [][]Activity{s.Context.ContextActivities.Parent, s.Context.ContextActivities.Grouping} {
+		for _, activity := range activities {
+			if id, ok := trailingID(activity.ID, "/study_sessions/"); ok {
+				return id, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// derivedStatementPrefix starts every statement ID produced by DerivedStatementID.
+const derivedStatementPrefix = "00000000-0000-4000-8000-"
+
+// DerivedStatementID returns a stable UUID for a review that was not recorded
+// through xAPI, so every stored review can be addressed as a statement.
+func DerivedStatementID(reviewID int) string {
+	return fmt.Sprintf(derivedStatementPrefix+"%012x", reviewID)
+}
+
+// IsDerivedStatementID reports whether statementID is in the range reserved for
+// DerivedStatementID. Clients must not use such IDs for their own statements.
+func IsDerivedStatementID(statementID string) bool {
+	return strings.HasPrefix(strings.ToLower(statementID), derivedStatementPrefix)
+}
+
+// ParseDerivedStatementID reverses DerivedStatementID.
+func ParseDerivedStatementID(statementID string) (int, bool) {
+	if !strings.HasPrefix(statementID, derivedStatementPrefix) || len(statementID) != len(derivedStatementPrefix)+12 {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(statementID[len(derivedStatementPrefix):], 16, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(id), true
+}
+
+func trailingID(iri, marker string) (int, bool) {
+	index := strings.LastIndex(iri, marker)
+	if index < 0 {
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(strings.TrimRight(iri[index+len(marker):], "/"))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,62 @@
+package xapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWordID(t *testing.T) {
+	id, ok := ParseWordID(WordActivityID("http://localhost:5000/", 12))
+	assert.True(t, ok)
+	assert.Equal(t, 12, id)
+
+	_, ok = ParseWordID("http://example.com/activities/quiz")
+	assert.False(t, ok)
+}
+
+func TestStatementSessionID(t *testing.T) {
+	statement := Statement{Context: &Context{ContextActivities: &ContextActivities{
+		Grouping: []Activity{{ID: "http://example.com/course"}, {ID: SessionActivityID("http://localhost:5000", 7)}},
+	}}}
+
+	id, ok := statement.SessionID()
+	assert.True(t, ok)
+	assert.Equal(t, 7, id)
+
+	_, ok = (&Statement{}).SessionID()
+	assert.False(t, ok)
+}
+
+func TestDerivedStatementID(t *testing.T) {
+	statementID := DerivedStatementID(255)
+	assert.True(t, ValidStatementID(statementID))
+
+	id, ok := ParseDerivedStatementID(statementID)
+	assert.True(t, ok)
+	assert.Equal(t, 255, id)
+	assert.True(t, IsDerivedStatementID(statementID))
+
+	generated, err := NewStatementID()
+	assert.NoError(t, err)
+	assert.True(t, ValidStatementID(generated))
+	_, ok = ParseDerivedStatementID(generated)
+	assert.False(t, ok)
+	assert.False(t, IsDerivedStatementID(generated))
+}
+
+func TestParseDuration(t *testing.T) {
+	d, err := ParseDuration("PT1.25S")
+	assert.NoError(t, err)
+	assert.Equal(t, 1250*time.Millisecond, d)
+
+	d, err = ParseDuration("PT2M3S")
+	assert.NoError(t, err)
+	assert.Equal(t, 123*time.Second, d)
+
+	_, err = ParseDuration("1.5 seconds")
+	assert.Error(t, err)
+
+	assert.Equal(t, "PT0.85S", FormatDuration(850*time.Millisecond))
+}
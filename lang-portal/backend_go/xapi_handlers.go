@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend_go/db"
+	"backend_go/models"
+	"backend_go/xapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// xapiClockSkew is how far a statement timestamp may fall outside its study session,
+// allowing for clients whose clocks run slightly ahead or behind.
+const xapiClockSkew = time.Minute
+
+// defaultXAPIActor is reported for reviews that were not recorded through xAPI.
+var defaultXAPIActor = json.RawMessage(`{"objectType":"Agent","name":"Learner","account":{"homePage":"http://localhost:5000","name":"learner"}}`)
+
+// postXAPIStatementsHandler handles the POST /xapi/statements endpoint.
+// It accepts a single statement or an array and stores each "answered" statement as a word review item.
+// Statements that were already stored unchanged are skipped, with 204 returned when nothing is new.
+func postXAPIStatementsHandler(c *gin.Context) {
+	c.Header("X-Experience-API-Version", xapi.Version)
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	var statements []xapi.Statement
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &statements)
+	} else {
+		var statement xapi.Statement
+		err = json.Unmarshal(trimmed, &statement)
+		statements = []xapi.Statement{statement}
+	}
+	if err != nil || len(statements) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	records := make([]models.XAPIStatementRecord, 0, len(statements))
+	ids := make([]string, 0, len(statements))
+	for i, statement := range statements {
+		record, status, message := xapiStatementToRecord(statement, currentUser(c).ID)
+		if status != 0 {
+			c.JSON(status, gin.H{"error": message, "statement_index": i})
+			return
+		}
+		ids = append(ids, record.StatementID)
+
+		if statement.ID != "" {
+			stored, err := db.GetXAPIStatements(dbConn, db.XAPIStatementFilter{StatementID: statement.ID, Limit: 1})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch statements"})
+				log.Println("Failed to fetch xapi statements:", err)
+				return
+			}
+			if len(stored) > 0 {
+				if !sameXAPIStatement(stored[0], record, statement.Timestamp != nil) {
+					c.JSON(http.StatusConflict, gin.H{"error": "Statement ID already exists", "statement_index": i})
+					return
+				}
+				// Resending an identical statement is a no-op
+				continue
+			}
+		}
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	if err := db.CreateXAPIStatements(dbConn, records); err != nil {
+		if errors.Is(err, db.ErrDuplicateStatement) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Statement ID already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store statements"})
+		log.Println("Failed to store xapi statements:", err)
+		return
+	}
+	reviewsRecorded.Add(float64(len(records)))
+
+	c.JSON(http.StatusOK, ids)
+}
+
+// xapiStatementToRecord validates a statement and maps it onto a word review item in
+// one of userID's study sessions. A non-zero status is returned together with an
+// error message when the statement is rejected.
+func xapiStatementToRecord(statement xapi.Statement, userID int) (models.XAPIStatementRecord, int, string) {
+	var record models.XAPIStatementRecord
+
+	if statement.Verb.ID != xapi.VerbAnswered {
+		return record, http.StatusBadRequest, "Only the answered verb is supported"
+	}
+	if len(statement.Actor) == 0 || string(statement.Actor) == "null" {
+		return record, http.StatusBadRequest, "Statement actor is required"
+	}
+	if statement.Result == nil || statement.Result.Success == nil {
+		return record, http.StatusBadRequest, "Statement result.success is required"
+	}
+
+	wordID, ok := xapi.ParseWordID(statement.Object.ID)
+	if !ok {
+		return record, http.StatusBadRequest, "Statement object is not a vocabulary activity"
+	}
+	sessionID, ok := statement.SessionID()
+	if !ok {
+		return record, http.StatusBadRequest, "Statement context must reference a study session"
+	}
+
+	word, err := db.GetWordByID(dbConn, wordID)
+	if err != nil {
+		log.Println("Failed to fetch word:", err)
+		return record, http.StatusInternalServerError, "Failed to fetch word"
+	}
+	if word == nil {
+		return record, http.StatusNotFound, "Word not found"
+	}
+
+	session, err := db.GetStudySessionByID(dbConn, sessionID)
+	if err != nil {
+		log.Println("Failed to fetch study session:", err)
+		return record, http.StatusInternalServerError, "Failed to fetch study session"
+	}
+	if session == nil || session.UserID != userID {
+		return record, http.StatusNotFound, "Study session not found"
+	}
+
+	scope := db.SessionScope(session)
+	groupWordIDs, err := db.GetGroupWordIDs(dbConn, session.GroupID, scope)
+	if err != nil {
+		log.Println("Failed to fetch group words:", err)
+		return record, http.StatusInternalServerError, "Failed to fetch group words"
+	}
+	if !groupWordIDs[wordID] {
+		return record, http.StatusBadRequest, "Word does not belong to the study session's group"
+	}
+
+	now := time.Now().UTC()
+	if statement.Timestamp != nil {
+		if statement.Timestamp.After(now.Add(xapiClockSkew)) {
+			return record, http.StatusBadRequest, "Statement timestamp is in the future"
+		}
+		if statement.Timestamp.Before(scope.AsOf.Add(-xapiClockSkew)) {
+			return record, http.StatusBadRequest, "Statement timestamp is before the study session started"
+		}
+	}
+
+	record.StatementID = statement.ID
+	if record.StatementID == "" {
+		record.StatementID, err = xapi.NewStatementID()
+		if err != nil {
+			log.Println("Failed to generate statement id:", err)
+			return record, http.StatusInternalServerError, "Failed to generate statement ID"
+		}
+	} else if !xapi.ValidStatementID(record.StatementID) {
+		return record, http.StatusBadRequest, "Statement ID must be a UUID"
+	} else if xapi.IsDerivedStatementID(record.StatementID) {
+		return record, http.StatusBadRequest, "Statement ID is reserved for reviews recorded outside xAPI"
+	}
+
+	record.Actor = string(statement.Actor)
+	record.Review = models.WordReviewItem{
+		UserID:         userID,
+		WordID:         wordID,
+		StudySessionID: sessionID,
+		Correct:        *statement.Result.Success,
+		CreatedAt:      now,
+	}
+	if statement.Timestamp != nil {
+		record.Review.CreatedAt = statement.Timestamp.UTC()
+	}
+	record.Review.GivenAnswer = statement.Result.Response
+	if statement.Result.Duration != "" {
+		duration, err := xapi.ParseDuration(statement.Result.Duration)
+		if err != nil {
+			return record, http.StatusBadRequest, "Statement result.duration is not an ISO 8601 duration"
+		}
+		responseMS := int(duration.Milliseconds())
+		record.Review.ResponseMS = &responseMS
+	}
+
+	return record, 0, ""
+}
+
+// sameXAPIStatement reports whether record describes the same answer as the stored
+// statement. The timestamp is only compared when the client supplied one, since
+// otherwise it was assigned on receipt.
+func sameXAPIStatement(stored, record models.XAPIStatementRecord, timestamped bool) bool {
+	if stored.Review.UserID != record.Review.UserID ||
+		stored.Review.WordID != record.Review.WordID ||
+		stored.Review.StudySessionID != record.Review.StudySessionID ||
+		stored.Review.Correct != record.Review.Correct ||
+		stored.Review.GivenAnswer != record.Review.GivenAnswer {
+		return false
+	}
+	if (stored.Review.ResponseMS == nil) != (record.Review.ResponseMS == nil) ||
+		(stored.Review.ResponseMS != nil && *stored.Review.ResponseMS != *record.Review.ResponseMS) {
+		return false
+	}
+	if timestamped && !stored.Review.CreatedAt.Equal(record.Review.CreatedAt) {
+		return false
+	}
+
+	var storedActor, actor bytes.Buffer
+	if json.Compact(&storedActor, []byte(stored.Actor)) != nil || json.Compact(&actor, []byte(record.Actor)) != nil {
+		return stored.Actor == record.Actor
+	}
+	return bytes.Equal(storedActor.Bytes(), actor.Bytes())
+}
+
+// getXAPIStatementsHandler handles the GET /xapi/statements endpoint.
+// Supported filters are statementId, verb, activity, since, until, limit and ascending,
+// plus user_id for teachers.
+func getXAPIStatementsHandler(c *gin.Context) {
+	c.Header("X-Experience-API-Version", xapi.Version)
+
+	userID, ok := statsUserID(c)
+	if !ok {
+		return
+	}
+	filter := db.XAPIStatementFilter{UserID: userID, Limit: 100}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		// As in the xAPI spec, a limit of 0 means the server maximum
+		if limit == 0 || limit > 500 {
+			limit = 500
+		}
+		filter.Limit = limit
+	}
+
+	if verb := c.Query("verb"); verb != "" && verb != xapi.VerbAnswered {
+		// Only answered statements are stored
+		c.JSON(http.StatusOK, gin.H{"statements": []xapi.Statement{}, "more": ""})
+		return
+	}
+
+	if activity := c.Query("activity"); activity != "" {
+		if wordID, ok := xapi.ParseWordID(activity); ok {
+			filter.WordID = wordID
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Activity is not a vocabulary activity"})
+			return
+		}
+	}
+
+	for param, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " timestamp"})
+				return
+			}
+			*target = parsed
+		}
+	}
+
+	filter.Ascending = c.Query("ascending") == "true"
+
+	statementID := c.Query("statementId")
+	if statementID != "" {
+		if reviewID, ok := xapi.ParseDerivedStatementID(statementID); ok {
+			filter.ReviewID = reviewID
+		} else {
+			filter.StatementID = statementID
+		}
+	}
+
+	records, err := db.GetXAPIStatements(dbConn, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch statements"})
+		log.Println("Failed to fetch xapi statements:", err)
+		return
+	}
+
+	statements := make([]xapi.Statement, 0, len(records))
+	for _, record := range records {
+		statements = append(statements, recordToXAPIStatement(record))
+	}
+
+	// A statementId lookup returns the single statement itself
+	if statementID != "" {
+		if len(statements) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Statement not found"})
+			return
+		}
+		c.JSON(http.StatusOK, statements[0])
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"statements": statements, "more": ""})
+}
+
+// recordToXAPIStatement renders a stored review as an xAPI statement.
+func recordToXAPIStatement(record models.XAPIStatementRecord) xapi.Statement {
+	statementID := record.StatementID
+	if statementID == "" {
+		statementID = xapi.DerivedStatementID(record.Review.ID)
+	}
+
+	actor := defaultXAPIActor
+	if record.Actor != "" {
+		actor = json.RawMessage(record.Actor)
+	}
+
+	success := record.Review.Correct
+	timestamp := record.Review.CreatedAt.UTC()
+
+	result := &xapi.Result{Success: &success, Response: record.Review.GivenAnswer}
+	if record.Review.ResponseMS != nil {
+		result.Duration = xapi.FormatDuration(time.Duration(*record.Review.ResponseMS) * time.Millisecond)
+	}
+
+	return xapi.Statement{
+		ID:    statementID,
+		Actor: actor,
+		Verb: xapi.Verb{
+			ID:      xapi.VerbAnswered,
+			Display: map[string]string{"en-US": "answered"},
+		},
+		Object: xapi.Activity{
+			ObjectType: "Activity",
+			ID:         xapi.WordActivityID(appConfig.PublicURL, record.Review.WordID),
+			Definition: &xapi.ActivityDefinition{
+				Name: map[string]string{record.SourceLanguage: record.SourceText, record.TargetLanguage: record.TargetText},
+				Type: xapi.ActivityTypeInteraction,
+			},
+		},
+		Result: result,
+		Context: &xapi.Context{
+			ContextActivities: &xapi.ContextActivities{
+				Grouping: []xapi.Activity{{ID: xapi.SessionActivityID(appConfig.PublicURL, record.Review.StudySessionID)}},
+			},
+		},
+		Timestamp: &timestamp,
+		Stored:    &timestamp,
+		Version:   xapi.Version,
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend_go/testutils"
+	"backend_go/xapi"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXAPIStatements(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	dbConn = db
+	appConfig.OpenMode = true
+	appConfig.PublicURL = "http://lrs.test"
+
+	_, err = db.Exec(`
+		INSERT INTO groups (id, name, description) VALUES (1, 'Basics', '');
+		INSERT INTO words (id, source_text, target_text, parts) VALUES (1, 'hello', 'olá', 'interjection'), (2, 'cat', 'gato', 'noun');
+		INSERT INTO words_groups (word_id, group_id) VALUES (1, 1);
+		INSERT INTO study_sessions (id, group_id, created_at, study_activity_id) VALUES (1, 1, '2025-02-01 09:00:00', 1);`)
+	require.NoError(t, err)
+
+	router := gin.Default()
+	SetupRoutes(router)
+
+	statement := `{
+		"id": "6f1c2a4e-8b7d-4c3a-9e21-0f5d6c7b8a90",
+		"actor": {"mbox": "mailto:learner@example.com"},
+		"verb": {"id": "http://adlnet.gov/expapi/verbs/answered"},
+		"object": {"id": "http://lrs.test/api/words/1"},
+		"result": {"success": true},
+		"context": {"contextActivities": {"grouping": [{"id": "http://lrs.test/api/study_sessions/1"}]}},
+		"timestamp": "2025-02-01T10:00:00Z"
+	}`
+
+	t.Run("Stores an answered statement as a review", func(t *testing.T) {
+		reviews := reviewsRecorded.Value()
+		req, _ := http.NewRequest("POST", "/xapi/statements", bytes.NewBufferString(statement))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, reviews+1, reviewsRecorded.Value())
+
+		var ids []string
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &ids))
+		assert.Equal(t, []string{"6f1c2a4e-8b7d-4c3a-9e21-0f5d6c7b8a90"}, ids)
+
+		var correct bool
+		require.NoError(t, db.QueryRow("SELECT is_correct FROM word_review_items WHERE word_id = 1").Scan(&correct))
+		assert.True(t, correct)
+	})
+
+	t.Run("Accepts an identical statement again without storing it", func(t *testing.T) {
+		reviews := reviewsRecorded.Value()
+		req, _ := http.NewRequest("POST", "/xapi/statements", bytes.NewBufferString(statement))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+		assert.Equal(t, reviews, reviewsRecorded.Value())
+
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM word_review_items").Scan(&count))
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Rejects a conflicting statement ID", func(t *testing.T) {
+		conflicting := strings.Replace(statement, `"success": true`, `"success": false`, 1)
+		req, _ := http.NewRequest("POST", "/xapi/statements", bytes.NewBufferString(conflicting))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("Rejects invalid statements", func(t *testing.T) {
+		cases := map[string][2]string{
+			"word outside the group":   {"http://lrs.test/api/words/1", "http://lrs.test/api/words/2"},
+			"derived statement ID":     {"6f1c2a4e-8b7d-4c3a-9e21-0f5d6c7b8a90", "00000000-0000-4000-8000-000000000001"},
+			"future timestamp":         {"2025-02-01T10:00:00Z", "2999-01-01T00:00:00Z"},
+			"timestamp before session": {"2025-02-01T10:00:00Z", "2025-01-01T10:00:00Z"},
+		}
+		for name, replacement := range cases {
+			body := strings.Replace(statement, replacement[0], replacement[1], 1)
+			body = strings.Replace(body, "6f1c2a4e-8b7d-4c3a-9e21-0f5d6c7b8a90", "7a2d3b5f-9c8e-4d4b-8f32-1a6e7d8c9ba1", 1)
+			req, _ := http.NewRequest("POST", "/xapi/statements", bytes.NewBufferString(body))
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+			assert.Equal(t, http.StatusBadRequest, resp.Code, name)
+		}
+
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM word_review_items").Scan(&count))
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Emits stored reviews as statements", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO word_review_items (word_id, study_session_id, is_correct, created_at)
+			VALUES (1, 1, 0, '2025-02-02 10:00:00')`)
+		require.NoError(t, err)
+
+		req, _ := http.NewRequest("GET", "/xapi/statements?activity=http://lrs.test/api/words/1&ascending=true", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var result struct {
+			Statements []xapi.Statement `json:"statements"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		require.Len(t, result.Statements, 2)
+		assert.Equal(t, "6f1c2a4e-8b7d-4c3a-9e21-0f5d6c7b8a90", result.Statements[0].ID)
+		assert.True(t, *result.Statements[0].Result.Success)
+		assert.False(t, *result.Statements[1].Result.Success)
+		assert.Equal(t, "http://lrs.test/api/words/1", result.Statements[1].Object.ID)
+	})
+
+	t.Run("Filters by since", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/xapi/statements?since=2025-02-01T12:00:00Z", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var result struct {
+			Statements []xapi.Statement `json:"statements"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Len(t, result.Statements, 1)
+	})
+}